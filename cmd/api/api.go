@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 	"github.com/bendrucker/honeycomb-cli/internal/config"
 	"github.com/bendrucker/honeycomb-cli/internal/fields"
 	"github.com/bendrucker/honeycomb-cli/internal/jq"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -113,7 +115,10 @@ func run(cmd *cobra.Command, o *apiOptions, path string) error {
 	}
 
 	baseURL := o.root.ResolveAPIUrl()
-	client := &http.Client{}
+	client, err := o.root.HTTPClient()
+	if err != nil {
+		return err
+	}
 	ios := o.root.IOStreams
 
 	if isV2Path(path) && body == nil && len(f) > 0 {
@@ -159,11 +164,20 @@ func run(cmd *cobra.Command, o *apiOptions, path string) error {
 			}
 		}
 
-		if o.jqExpr != "" {
+		switch {
+		case o.jqExpr != "":
 			if err := jq.Filter(bytes.NewReader(respBody), ios.Out, o.jqExpr); err != nil {
 				return err
 			}
-		} else {
+		case o.root.Template != "":
+			var data any
+			if err := json.Unmarshal(respBody, &data); err != nil {
+				return fmt.Errorf("decoding JSON for --template: %w", err)
+			}
+			if err := output.RenderTemplate(ios.Out, o.root.Template, data); err != nil {
+				return err
+			}
+		default:
 			writeBody(ios.Out, respBody)
 		}
 
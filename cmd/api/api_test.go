@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -122,6 +123,24 @@ func TestRun_JQ(t *testing.T) {
 	}
 }
 
+func TestRun_Template(t *testing.T) {
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"name": "test-team"})
+	}), config.KeyConfig, "test-key")
+	opts.Template = "team: {{.name}}\n"
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"/1/auth"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ts.OutBuf.String(); got != "team: test-team\n" {
+		t.Errorf("template output = %q, want %q", got, "team: test-team\n")
+	}
+}
+
 func TestRun_Include(t *testing.T) {
 	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -791,3 +810,47 @@ func TestRun_PositionalMethodErrors(t *testing.T) {
 func writeTestFile(path, content string) error {
 	return os.WriteFile(path, []byte(content), 0o644)
 }
+
+func TestRun_RecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"api_key_access":{"boards":true}}`))
+	}), config.KeyConfig, "test-key")
+
+	t.Setenv("HONEYCOMB_RECORD", dir)
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"/1/auth"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	recorded := ts.OutBuf.String()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("recorded %d interactions, want 1", len(entries))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "test-key") {
+		t.Errorf("recording contains the key:\n%s", data)
+	}
+
+	t.Setenv("HONEYCOMB_RECORD", "")
+	t.Setenv("HONEYCOMB_REPLAY", dir)
+	opts.APIUrl = "http://127.0.0.1:1"
+	ts.OutBuf.Reset()
+	cmd = NewCmd(opts)
+	cmd.SetArgs([]string{"/1/auth"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if ts.OutBuf.String() != recorded {
+		t.Errorf("replayed output = %q, want %q", ts.OutBuf.String(), recorded)
+	}
+}
@@ -0,0 +1,166 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/bendrucker/honeycomb-cli/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var actionListTable = output.TableFromTags[manifest.Action]()
+
+type applyOptions struct {
+	files  []string
+	prune  bool
+	dryRun bool
+	yes    bool
+}
+
+func NewCmd(opts *options.RootOptions) *cobra.Command {
+	o := &applyOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Create, update, and delete resources to match manifests",
+		Long: "Reconcile Honeycomb resources with YAML or JSON manifests.\n\n" +
+			"Each manifest has a kind (" + strings.Join(manifest.KindNames(), ", ") + "), a dataset " +
+			"for dataset-scoped kinds, and a spec in the shape of the API request body. Manifests " +
+			"are matched to existing resources by name (alias for calculated fields, type for " +
+			"marker settings, description for burn alerts, and type and target for recipients), " +
+			"or by id when set. Burn alerts name their SLO with slo.\n\n" +
+			"The plan is printed before it is applied. Only the fields a spec sets are compared " +
+			"and updated. With --prune, resources of the kinds and datasets the manifests cover " +
+			"that no manifest matches are deleted.",
+		Example: `  # Preview changes without applying them
+  honeycomb apply -f honeycomb/ --dry-run
+
+  # Apply a directory of manifests
+  honeycomb apply -f honeycomb/
+
+  # Apply and delete unmanaged triggers, SLOs, and boards without prompting
+  honeycomb apply -f honeycomb/ --prune --yes
+
+  # A manifest file
+  cat honeycomb/latency.yaml
+  kind: Trigger
+  dataset: production
+  spec:
+    name: High latency
+    frequency: 300
+    query:
+      time_range: 900
+      calculations:
+        - op: P99
+          column: duration_ms
+    threshold:
+      op: ">"
+      value: 1000`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runApply(cmd.Context(), opts, o)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.files, "file", "f", nil, "Manifest file or directory (repeatable)")
+	cmd.Flags().BoolVar(&o.prune, "prune", false, "Delete resources that no manifest matches")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Print the plan without applying it")
+	cmd.Flags().BoolVar(&o.yes, "yes", false, "Skip confirmation prompt")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runApply(ctx context.Context, opts *options.RootOptions, o *applyOptions) error {
+	var manifests []manifest.Manifest
+	for _, f := range o.files {
+		loaded, err := manifest.Load(f)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, loaded...)
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no manifests found in %s", strings.Join(o.files, ", "))
+	}
+
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	plan, err := manifest.Plan(ctx, client, manifests, manifest.PlanOptions{Prune: o.prune})
+	if err != nil {
+		return err
+	}
+
+	if o.dryRun || len(plan) == 0 {
+		return opts.OutputWriterList().WriteList(plan, actionListTable, "No changes.")
+	}
+
+	ios := opts.IOStreams
+	writePlan(ios.Err, plan)
+
+	proceed, err := confirm(opts, o, plan)
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return fmt.Errorf("aborted")
+	}
+
+	done, applyErr := manifest.Apply(ctx, client, plan)
+	if err := opts.OutputWriterList().WriteList(done, actionListTable, "No changes applied."); err != nil {
+		return err
+	}
+	return applyErr
+}
+
+// confirm prompts before applying when interactive. A non-interactive apply
+// proceeds without --yes unless the plan deletes resources.
+func confirm(opts *options.RootOptions, o *applyOptions, plan []manifest.Action) (bool, error) {
+	if o.yes {
+		return true, nil
+	}
+
+	ios := opts.IOStreams
+	if !ios.CanPrompt() {
+		for _, a := range plan {
+			if a.Op == manifest.OpDelete {
+				return false, fmt.Errorf("--yes is required in non-interactive mode when the plan deletes resources")
+			}
+		}
+		return true, nil
+	}
+
+	answer, err := prompt.Line(ios.Err, ios.In, fmt.Sprintf("Apply %d changes? (y/N): ", len(plan)))
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(answer, "y"), nil
+}
+
+var planSymbols = map[string]string{
+	manifest.OpCreate: "+",
+	manifest.OpUpdate: "~",
+	manifest.OpDelete: "-",
+}
+
+func writePlan(w io.Writer, plan []manifest.Action) {
+	counts := map[string]int{}
+	for _, a := range plan {
+		counts[a.Op]++
+		line := fmt.Sprintf("%s %s", planSymbols[a.Op], a)
+		if len(a.Changes) > 0 {
+			line += " (" + a.Changes.FormatField() + ")"
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	_, _ = fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		counts[manifest.OpCreate], counts[manifest.OpUpdate], counts[manifest.OpDelete])
+}
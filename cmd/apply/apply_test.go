@@ -0,0 +1,207 @@
+package apply
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/zalando/go-keyring"
+)
+
+func init() {
+	keyring.MockInit()
+}
+
+func setupTest(t *testing.T, handler http.Handler) (*options.RootOptions, *iostreams.TestStreams) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{
+		IOStreams: ts.IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    srv.URL,
+		Format:    "json",
+	}
+
+	if err := config.SetKey("default", config.KeyConfig, "test-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyConfig) })
+
+	return opts, ts
+}
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const boardManifest = `kind: Board
+spec:
+  name: Overview
+  description: Service health
+---
+kind: Board
+spec:
+  name: Latency
+`
+
+func boardsHandler(t *testing.T, writes *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/1/boards":
+			_ = json.NewEncoder(w).Encode([]map[string]any{
+				{"id": "b1", "name": "Overview", "description": "Old"},
+				{"id": "b2", "name": "Unmanaged"},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/1/boards":
+			*writes = append(*writes, "POST")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "b3", "name": "Latency"}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/1/boards/b1":
+			*writes = append(*writes, "PUT b1")
+			_, _ = w.Write([]byte(`{"id": "b1", "name": "Overview", "description": "Service health"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/1/boards/b2":
+			*writes = append(*writes, "DELETE b2")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestApply_DryRun(t *testing.T) {
+	var writes []string
+	opts, ts := setupTest(t, boardsHandler(t, &writes))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, boardManifest), "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(writes) != 0 {
+		t.Errorf("dry run made writes: %v", writes)
+	}
+
+	var plan []map[string]any
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &plan); err != nil {
+		t.Fatalf("unmarshal output: %v\n%s", err, ts.OutBuf.String())
+	}
+	if len(plan) != 2 {
+		t.Fatalf("got %d actions, want 2", len(plan))
+	}
+	if plan[0]["action"] != "update" || plan[0]["id"] != "b1" {
+		t.Errorf("plan[0] = %v, want update of b1", plan[0])
+	}
+	if changes, _ := plan[0]["changes"].([]any); len(changes) != 1 || changes[0] != "description" {
+		t.Errorf("plan[0] changes = %v, want [description]", plan[0]["changes"])
+	}
+	if plan[1]["action"] != "create" || plan[1]["name"] != "Latency" {
+		t.Errorf("plan[1] = %v, want create of Latency", plan[1])
+	}
+}
+
+func TestApply(t *testing.T) {
+	var writes []string
+	opts, ts := setupTest(t, boardsHandler(t, &writes))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, boardManifest)})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(writes, ","); got != "PUT b1,POST" {
+		t.Errorf("writes = %s, want PUT b1,POST", got)
+	}
+
+	var done []map[string]any
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &done); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(done) != 2 || done[1]["id"] != "b3" {
+		t.Errorf("done = %v, want created board id b3", done)
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "Plan: 1 to create, 1 to update, 0 to delete.") {
+		t.Errorf("stderr = %q, want plan summary", ts.ErrBuf.String())
+	}
+}
+
+func TestApply_PruneRequiresYes(t *testing.T) {
+	var writes []string
+	opts, _ := setupTest(t, boardsHandler(t, &writes))
+	opts.IOStreams.SetNeverPrompt(true)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, boardManifest), "--prune"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--yes is required") {
+		t.Fatalf("err = %v, want --yes required", err)
+	}
+	if len(writes) != 0 {
+		t.Errorf("made writes without confirmation: %v", writes)
+	}
+}
+
+func TestApply_PruneWithYes(t *testing.T) {
+	var writes []string
+	opts, ts := setupTest(t, boardsHandler(t, &writes))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, boardManifest), "--prune", "--yes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(writes, ","); got != "PUT b1,POST,DELETE b2" {
+		t.Errorf("writes = %s, want PUT b1,POST,DELETE b2", got)
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "- delete Board Unmanaged") {
+		t.Errorf("stderr = %q, want delete line", ts.ErrBuf.String())
+	}
+}
+
+func TestApply_InteractiveDecline(t *testing.T) {
+	var writes []string
+	opts, _ := setupTest(t, boardsHandler(t, &writes))
+	ts := iostreams.TestPromptable(t)
+	opts.IOStreams = ts.IOStreams
+	ts.InBuf.WriteString("n\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, boardManifest)})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "aborted" {
+		t.Fatalf("err = %v, want aborted", err)
+	}
+	if len(writes) != 0 {
+		t.Errorf("made writes after declining: %v", writes)
+	}
+}
+
+func TestApply_NoManifests(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", t.TempDir()})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no manifests found") {
+		t.Errorf("err = %v, want no manifests error", err)
+	}
+}
@@ -150,7 +150,11 @@ func runAuthLogin(ctx context.Context, opts *options.RootOptions, keyType, keyID
 	}
 
 	if verify {
-		client, err := api.NewClientWithResponses(opts.ResolveAPIUrl())
+		httpClient, err := opts.HTTPClient()
+		if err != nil {
+			return err
+		}
+		client, err := api.NewClientWithResponses(opts.ResolveAPIUrl(), api.WithHTTPClient(httpClient))
 		if err != nil {
 			return fmt.Errorf("creating API client: %w", err)
 		}
@@ -97,7 +97,11 @@ func runAuthStatus(ctx context.Context, opts *options.RootOptions, offline bool)
 			})
 		}
 	} else {
-		client, err := api.NewClientWithResponses(opts.ResolveAPIUrl())
+		httpClient, err := opts.HTTPClient()
+		if err != nil {
+			return err
+		}
+		client, err := api.NewClientWithResponses(opts.ResolveAPIUrl(), api.WithHTTPClient(httpClient))
 		if err != nil {
 			return fmt.Errorf("creating API client: %w", err)
 		}
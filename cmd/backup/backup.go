@@ -0,0 +1,8 @@
+package backup
+
+import (
+	envbackup "github.com/bendrucker/honeycomb-cli/internal/backup"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+)
+
+var entryListTable = output.TableFromTags[envbackup.Entry]()
@@ -0,0 +1,135 @@
+package backup
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/zalando/go-keyring"
+)
+
+func init() {
+	keyring.MockInit()
+}
+
+func setupTest(t *testing.T, handler http.Handler) (*options.RootOptions, *iostreams.TestStreams) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{
+		IOStreams: ts.IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    srv.URL,
+		Format:    "json",
+	}
+
+	if err := config.SetKey("default", config.KeyConfig, "test-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyConfig) })
+
+	return opts, ts
+}
+
+// recipientsOnly serves an environment with a single recipient and nothing
+// else.
+func recipientsOnly(t *testing.T, created *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/1/recipients":
+			_, _ = w.Write([]byte(`[{"id": "r1", "type": "email", "details": {"email_address": "oncall@example.com"}, "created_at": "2024-01-01T00:00:00Z"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/1/recipients":
+			*created = append(*created, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "r2"}`))
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestExport(t *testing.T) {
+	opts, ts := setupTest(t, recipientsOnly(t, nil))
+	dir := filepath.Join(t.TempDir(), "export")
+
+	cmd := NewExportCmd(opts)
+	cmd.SetArgs([]string{"--dir", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []map[string]any
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &entries); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(entries) != 1 || entries[0]["kind"] != "Recipient" || entries[0]["status"] != "exported" {
+		t.Errorf("entries = %v", entries)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "recipients", "r1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "created_at") {
+		t.Errorf("definition = %s, want read-only fields stripped", data)
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "recipients"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"r1.json": `{"type": "email", "details": {"email_address": "oncall@example.com"}}`,
+		"r5.json": `{"type": "email", "details": {"email_address": "alerts@example.com"}}`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "recipients", name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var created []string
+	opts, ts := setupTest(t, recipientsOnly(t, &created))
+
+	cmd := NewImportCmd(opts)
+	cmd.SetArgs([]string{"--dir", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(created) != 1 {
+		t.Errorf("created %d recipients, want 1", len(created))
+	}
+
+	var entries []map[string]any
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &entries); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(entries) != 2 || entries[0]["status"] != "exists" || entries[1]["status"] != "created" || entries[1]["id"] != "r2" {
+		t.Errorf("entries = %v", entries)
+	}
+}
+
+func TestImport_MissingDir(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewImportCmd(opts)
+	cmd.SetArgs([]string{"--dir", filepath.Join(t.TempDir(), "missing")})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
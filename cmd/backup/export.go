@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	envbackup "github.com/bendrucker/honeycomb-cli/internal/backup"
+	"github.com/spf13/cobra"
+)
+
+func NewExportCmd(opts *options.RootOptions) *cobra.Command {
+	var (
+		dir      string
+		datasets []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export environment configuration to a directory",
+		Long: "Write the configuration of an environment to a directory tree, one JSON definition " +
+			"per resource with read-only fields removed: recipients, boards and their views, and for " +
+			"each selected dataset its settings, columns, definitions, calculated fields, marker " +
+			"settings, triggers, SLOs, burn alerts, query annotations, and the queries they describe.\n\n" +
+			"Files are named by resource ID, and definitions keep the IDs of the resources they " +
+			"reference so that honeycomb import can remap them in another environment. Boards with " +
+			"panels that show queries or SLOs of datasets that are not exported are skipped.",
+		Example: `  # Export every dataset
+  honeycomb export --dir staging/ --dataset '*'
+
+  # Export only datasets with a prefix
+  honeycomb export --dir staging/ --dataset 'checkout-*' --profile staging`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runExport(cmd.Context(), opts, dir, datasets)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory to export to; must be empty or not exist (required)")
+	cmd.Flags().StringSliceVar(&datasets, "dataset", []string{"*"}, "Dataset slugs or glob patterns to export")
+	_ = cmd.MarkFlagRequired("dir")
+
+	return cmd
+}
+
+func runExport(ctx context.Context, opts *options.RootOptions, dir string, datasets []string) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	entries, err := envbackup.Export(ctx, client, dir, envbackup.ExportOptions{Datasets: datasets})
+	if err != nil {
+		return err
+	}
+	return opts.OutputWriterList().WriteList(entries, entryListTable, "Nothing to export.")
+}
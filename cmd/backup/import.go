@@ -0,0 +1,56 @@
+package backup
+
+import (
+	"context"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	envbackup "github.com/bendrucker/honeycomb-cli/internal/backup"
+	"github.com/spf13/cobra"
+)
+
+func NewImportCmd(opts *options.RootOptions) *cobra.Command {
+	var (
+		dir    string
+		update bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import environment configuration from a directory",
+		Long: "Recreate the resources in a directory written by honeycomb export in the current " +
+			"environment. Resources are created in dependency order, and the IDs they reference " +
+			"(recipients in triggers and burn alerts, SLOs in burn alerts and boards, queries and " +
+			"query annotations in boards) are rewritten to the IDs in this environment.\n\n" +
+			"Resources that already exist, matched by name or the same key honeycomb apply uses, " +
+			"are left unchanged unless --update is set. Missing datasets and columns are created.",
+		Example: `  # Clone staging configuration into production
+  honeycomb export --dir staging/ --dataset '*' --profile staging
+  honeycomb import --dir staging/ --profile prod
+
+  # Overwrite resources that already exist
+  honeycomb import --dir staging/ --profile prod --update`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runImport(cmd.Context(), opts, dir, update)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory written by honeycomb export (required)")
+	cmd.Flags().BoolVar(&update, "update", false, "Update resources that already exist")
+	_ = cmd.MarkFlagRequired("dir")
+
+	return cmd
+}
+
+func runImport(ctx context.Context, opts *options.RootOptions, dir string, update bool) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	entries, importErr := envbackup.Import(ctx, client, dir, envbackup.ImportOptions{Update: update})
+	if err := opts.OutputWriterList().WriteList(entries, entryListTable, "Nothing to import."); err != nil {
+		return err
+	}
+	return importErr
+}
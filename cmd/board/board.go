@@ -33,6 +33,8 @@ func NewCmd(opts *options.RootOptions) *cobra.Command {
 	cmd.AddCommand(NewCreateCmd(opts))
 	cmd.AddCommand(NewUpdateCmd(opts))
 	cmd.AddCommand(NewDeleteCmd(opts))
+	cmd.AddCommand(NewDiffCmd(opts))
+	cmd.AddCommand(NewCopyCmd(opts))
 	cmd.AddCommand(NewViewCmd(opts))
 
 	return command.Group(cmd)
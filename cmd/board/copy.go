@@ -0,0 +1,439 @@
+package board
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// allDatasets is the dataset slug for environment-wide queries.
+const allDatasets = "__all__"
+
+type object = map[string]any
+
+func NewCopyCmd(opts *options.RootOptions) *cobra.Command {
+	var (
+		toProfile string
+		name      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "copy <board-id>",
+		Short: "Copy a board to another profile",
+		Long: `Copy a board, with its queries, query annotations, views, and preset filters,
+to the environment of another profile.
+
+Each query the board displays is re-created in the target environment along
+with its annotation, and the panels are rewritten to reference the copies.
+Datasets are matched by name. SLO panels are matched to an SLO of the same name
+in the target dataset and dropped when there is none. Columns that queries,
+preset filters, or views use but the target dataset lacks are reported as
+warnings; the board is still created.`,
+		Example: `  # Copy a board from the active profile to the prod profile
+  honeycomb board copy abc123 --to-profile prod
+
+  # Copy a board under a new name
+  honeycomb board copy abc123 --to-profile prod --name "Latency (prod)"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBoardCopy(cmd.Context(), opts, args[0], toProfile, name)
+		},
+	}
+
+	cmd.Flags().StringVar(&toProfile, "to-profile", "", "Profile of the environment to copy the board to")
+	cmd.Flags().StringVar(&name, "name", "", "Name for the copied board (default: the source board's name)")
+	_ = cmd.MarkFlagRequired("to-profile")
+
+	return cmd
+}
+
+func runBoardCopy(ctx context.Context, opts *options.RootOptions, boardID, toProfile, name string) error {
+	if toProfile == opts.ActiveProfile() {
+		return fmt.Errorf("--to-profile must differ from the active profile %q", toProfile)
+	}
+
+	src, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+	dst, err := opts.WithProfile(toProfile).ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	board, err := api.DecodeResponse[object](src.GetBoard(ctx, boardID))
+	if err != nil {
+		return fmt.Errorf("getting board: %w", err)
+	}
+
+	c := &boardCopier{
+		ctx:     ctx,
+		src:     src,
+		dst:     dst,
+		profile: toProfile,
+		warn:    opts.IOStreams.Err,
+		queries: map[string]string{},
+		columns: map[string][]string{},
+		missing: map[string]bool{},
+	}
+	if c.srcSlugs, err = datasetSlugs(ctx, src); err != nil {
+		return err
+	}
+	if c.dstSlugs, err = datasetSlugs(ctx, dst); err != nil {
+		return fmt.Errorf("profile %s: %w", toProfile, err)
+	}
+
+	if err := c.copyPanels(board); err != nil {
+		return err
+	}
+	c.checkFilters(board["preset_filters"], "preset filter")
+
+	views, err := api.DecodeResponse[[]object](src.ListBoardViews(ctx, boardID))
+	if err != nil {
+		return fmt.Errorf("listing board views: %w", err)
+	}
+	for _, v := range views {
+		c.checkFilters(v["filters"], fmt.Sprintf("view %q", v["name"]))
+	}
+
+	if name != "" {
+		board["name"] = name
+	}
+	data, err := api.MarshalStrippingReadOnly(board, "Board")
+	if err != nil {
+		return fmt.Errorf("stripping read-only fields: %w", err)
+	}
+	data, err = stripPanelDataset(data)
+	if err != nil {
+		return fmt.Errorf("stripping panel dataset: %w", err)
+	}
+
+	resp, err := dst.CreateBoardWithBodyWithResponse(ctx, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating board: %w", err)
+	}
+	created, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON201)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range views {
+		delete(v, "id")
+		body, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		if _, err := api.ReadResponse(dst.CreateBoardViewWithBody(ctx, *created.Id, "application/json", bytes.NewReader(body))); err != nil {
+			return fmt.Errorf("creating board view %q: %w", v["name"], err)
+		}
+	}
+
+	return writeBoardDetail(opts, boardToDetail(*created))
+}
+
+// boardCopier re-creates the resources a board's panels reference in another
+// environment.
+type boardCopier struct {
+	ctx      context.Context
+	src, dst api.ClientInterface
+	profile  string
+	warn     io.Writer
+
+	// srcSlugs and dstSlugs map dataset names to slugs in each environment.
+	srcSlugs, dstSlugs map[string]string
+	// queries maps source query IDs to their copies.
+	queries map[string]string
+	// columns caches the column names and calculated field aliases of each
+	// target dataset.
+	columns map[string][]string
+	// missing records the reported columns so each is reported once.
+	missing map[string]bool
+	// used lists the target datasets the board's queries run against, where
+	// preset filter and view columns are looked up.
+	used []string
+	// srcSLOs indexes the source environment's SLOs by ID, once the first SLO
+	// panel needs it, and dstSLOs caches the SLOs of each target dataset.
+	srcSLOs map[string]sloRef
+	dstSLOs map[string][]object
+}
+
+// sloRef identifies an SLO by names that are the same in every environment.
+type sloRef struct {
+	dataset, name string
+}
+
+func (c *boardCopier) copyPanels(board object) error {
+	panels, _ := board["panels"].([]any)
+	kept := make([]any, 0, len(panels))
+	for _, p := range panels {
+		panel, _ := p.(object)
+		if q, ok := panel["query_panel"].(object); ok {
+			if err := c.copyQueryPanel(q); err != nil {
+				return err
+			}
+		}
+		if s, ok := panel["slo_panel"].(object); ok {
+			found, err := c.mapSLOPanel(s)
+			if err != nil {
+				return err
+			}
+			if !found {
+				continue
+			}
+		}
+		kept = append(kept, panel)
+	}
+	if panels != nil {
+		board["panels"] = kept
+	}
+	return nil
+}
+
+func (c *boardCopier) copyQueryPanel(panel object) error {
+	name, _ := panel["dataset"].(string)
+	srcSlug, dstSlug, err := c.slugs(name)
+	if err != nil {
+		return err
+	}
+
+	queryID, _ := panel["query_id"].(string)
+	newID, ok := c.queries[queryID]
+	if !ok {
+		query, err := api.DecodeResponse[object](c.src.GetQuery(c.ctx, srcSlug, queryID))
+		if err != nil {
+			return fmt.Errorf("getting query %s: %w", queryID, err)
+		}
+		if dstSlug != allDatasets {
+			c.checkColumns(dstSlug, queryColumns(query), "query "+queryID)
+		}
+		created, err := c.create(c.dst.CreateQueryWithBody, dstSlug, query, "Query")
+		if err != nil {
+			return fmt.Errorf("creating query %s: %w", queryID, err)
+		}
+		newID = created
+		c.queries[queryID] = newID
+	}
+	panel["query_id"] = newID
+
+	if annotationID, _ := panel["query_annotation_id"].(string); annotationID != "" {
+		annotation, err := api.DecodeResponse[object](c.src.GetQueryAnnotation(c.ctx, srcSlug, annotationID))
+		if err != nil {
+			return fmt.Errorf("getting query annotation %s: %w", annotationID, err)
+		}
+		annotation["query_id"] = newID
+		created, err := c.create(c.dst.CreateQueryAnnotationWithBody, dstSlug, annotation, "QueryAnnotation")
+		if err != nil {
+			return fmt.Errorf("creating query annotation %s: %w", annotationID, err)
+		}
+		panel["query_annotation_id"] = created
+	}
+	return nil
+}
+
+type createFunc func(ctx context.Context, dataset string, contentType string, body io.Reader, reqEditors ...api.RequestEditorFn) (*http.Response, error)
+
+// create strips a resource read from the source environment and creates it in
+// the target, returning the new ID.
+func (c *boardCopier) create(fn createFunc, dataset string, obj object, schema string) (string, error) {
+	body, err := api.MarshalStrippingReadOnly(obj, schema)
+	if err != nil {
+		return "", err
+	}
+	created, err := api.DecodeResponse[object](fn(c.ctx, dataset, "application/json", bytes.NewReader(body)))
+	if err != nil {
+		return "", err
+	}
+	id, _ := created["id"].(string)
+	return id, nil
+}
+
+// mapSLOPanel points an SLO panel at the target SLO with the same name and
+// dataset as the source SLO, reporting false when there is none.
+func (c *boardCopier) mapSLOPanel(panel object) (bool, error) {
+	id, _ := panel["slo_id"].(string)
+	if c.srcSLOs == nil {
+		if err := c.indexSourceSLOs(); err != nil {
+			return false, err
+		}
+	}
+	ref, ok := c.srcSLOs[id]
+	if !ok {
+		c.warnf("SLO %s not found, dropping its panel", id)
+		return false, nil
+	}
+
+	if dstSlug, ok := c.dstSlugs[ref.dataset]; ok {
+		targets, ok := c.dstSLOs[dstSlug]
+		if !ok {
+			var err error
+			targets, err = api.DecodeResponse[[]object](c.dst.ListSlos(c.ctx, dstSlug))
+			if err != nil {
+				return false, fmt.Errorf("profile %s: listing SLOs: %w", c.profile, err)
+			}
+			c.dstSLOs[dstSlug] = targets
+		}
+		if j := slices.IndexFunc(targets, func(s object) bool { return s["name"] == ref.name }); j >= 0 {
+			panel["slo_id"] = targets[j]["id"]
+			return true, nil
+		}
+	}
+	c.warnf("SLO %q is not in dataset %q in profile %s, dropping its panel", ref.name, ref.dataset, c.profile)
+	return false, nil
+}
+
+// indexSourceSLOs lists the SLOs of every source dataset once, so each SLO
+// panel is mapped without further requests.
+func (c *boardCopier) indexSourceSLOs() error {
+	c.srcSLOs = map[string]sloRef{}
+	c.dstSLOs = map[string][]object{}
+	for _, name := range slices.Sorted(maps.Keys(c.srcSlugs)) {
+		slos, err := api.DecodeResponse[[]object](c.src.ListSlos(c.ctx, c.srcSlugs[name]))
+		if err != nil {
+			return fmt.Errorf("listing SLOs: %w", err)
+		}
+		for _, s := range slos {
+			id, _ := s["id"].(string)
+			sloName, _ := s["name"].(string)
+			c.srcSLOs[id] = sloRef{dataset: name, name: sloName}
+		}
+	}
+	return nil
+}
+
+// slugs resolves a panel's dataset name to its slug in each environment. An
+// empty name is an environment-wide query.
+func (c *boardCopier) slugs(name string) (string, string, error) {
+	if name == "" {
+		return allDatasets, allDatasets, nil
+	}
+	srcSlug, ok := c.srcSlugs[name]
+	if !ok {
+		return "", "", fmt.Errorf("dataset %q not found", name)
+	}
+	dstSlug, ok := c.dstSlugs[name]
+	if !ok {
+		return "", "", fmt.Errorf("dataset %q not found in profile %s", name, c.profile)
+	}
+	if !slices.Contains(c.used, dstSlug) {
+		c.used = append(c.used, dstSlug)
+	}
+	return srcSlug, dstSlug, nil
+}
+
+// checkFilters reports preset filter or view filter columns that none of the
+// board's target datasets have.
+func (c *boardCopier) checkFilters(v any, source string) {
+	if len(c.used) == 0 {
+		return
+	}
+	filters, _ := v.([]any)
+	for _, f := range filters {
+		filter, _ := f.(object)
+		column, _ := filter["column"].(string)
+		if column == "" {
+			continue
+		}
+		found := slices.ContainsFunc(c.used, func(slug string) bool {
+			return slices.Contains(c.columns[slug], column)
+		})
+		if !found && !c.missing[column] {
+			c.missing[column] = true
+			c.warnf("column %q used by %s is not in any of the board's datasets in profile %s", column, source, c.profile)
+		}
+	}
+}
+
+func (c *boardCopier) checkColumns(slug string, columns []string, source string) {
+	known, ok := c.columns[slug]
+	if !ok {
+		var err error
+		known, err = c.targetColumns(slug)
+		if err != nil {
+			c.warnf("checking columns of dataset %s in profile %s: %v", slug, c.profile, err)
+			return
+		}
+		c.columns[slug] = known
+	}
+	for _, col := range columns {
+		key := slug + "/" + col
+		if !slices.Contains(known, col) && !c.missing[key] {
+			c.missing[key] = true
+			c.warnf("column %q used by %s is not in dataset %s in profile %s", col, source, slug, c.profile)
+		}
+	}
+}
+
+func (c *boardCopier) targetColumns(slug string) ([]string, error) {
+	columns, err := api.DecodeResponse[[]object](c.dst.ListColumns(c.ctx, slug, nil))
+	if err != nil {
+		return nil, err
+	}
+	fields, err := api.DecodeResponse[[]object](c.dst.ListCalculatedFields(c.ctx, slug, nil))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, col := range columns {
+		if s, _ := col["key_name"].(string); s != "" {
+			names = append(names, s)
+		}
+	}
+	for _, f := range fields {
+		if s, _ := f["alias"].(string); s != "" {
+			names = append(names, s)
+		}
+	}
+	return names, nil
+}
+
+func (c *boardCopier) warnf(format string, args ...any) {
+	_, _ = fmt.Fprintf(c.warn, "warning: "+format+"\n", args...)
+}
+
+// queryColumns returns the columns a query spec breaks down, calculates,
+// filters, orders, or limits by.
+func queryColumns(query object) []string {
+	var columns []string
+	add := func(s string) {
+		if s != "" && !slices.Contains(columns, s) {
+			columns = append(columns, s)
+		}
+	}
+	breakdowns, _ := query["breakdowns"].([]any)
+	for _, b := range breakdowns {
+		s, _ := b.(string)
+		add(s)
+	}
+	for _, field := range []string{"calculations", "filters", "orders", "havings"} {
+		items, _ := query[field].([]any)
+		for _, item := range items {
+			obj, _ := item.(object)
+			s, _ := obj["column"].(string)
+			add(s)
+		}
+	}
+	return columns
+}
+
+func datasetSlugs(ctx context.Context, c api.ClientInterface) (map[string]string, error) {
+	datasets, err := api.DecodeResponse[[]object](c.ListDatasets(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("listing datasets: %w", err)
+	}
+	slugs := make(map[string]string, len(datasets))
+	for _, d := range datasets {
+		name, _ := d["name"].(string)
+		slug, _ := d["slug"].(string)
+		slugs[name] = slug
+	}
+	return slugs, nil
+}
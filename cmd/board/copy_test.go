@@ -0,0 +1,212 @@
+package board
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestCopy(t *testing.T) {
+	var srcSLOLists, dstSLOLists atomic.Int32
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /1/boards/b1":
+			writeJSON(w, http.StatusOK, map[string]any{
+				"id":   "b1",
+				"name": "Latency",
+				"type": "flexible",
+				"links": map[string]any{
+					"board_url": "https://ui.honeycomb.io/b1",
+				},
+				"preset_filters": []any{
+					map[string]any{"column": "service.name", "alias": "Service"},
+					map[string]any{"column": "region", "alias": "Region"},
+				},
+				"panels": []any{
+					map[string]any{
+						"type": "query",
+						"query_panel": map[string]any{
+							"dataset":             "API",
+							"query_id":            "q1",
+							"query_annotation_id": "a1",
+						},
+					},
+					map[string]any{
+						"type":      "slo",
+						"slo_panel": map[string]any{"slo_id": "s1"},
+					},
+					map[string]any{
+						"type":      "slo",
+						"slo_panel": map[string]any{"slo_id": "s2"},
+					},
+				},
+			})
+		case "GET /1/datasets":
+			writeJSON(w, http.StatusOK, []any{map[string]any{"name": "API", "slug": "api"}})
+		case "GET /1/queries/api/q1":
+			writeJSON(w, http.StatusOK, map[string]any{
+				"id":           "q1",
+				"breakdowns":   []any{"service.name", "endpoint"},
+				"calculations": []any{map[string]any{"op": "P99", "column": "duration_ms"}},
+			})
+		case "GET /1/query_annotations/api/a1":
+			writeJSON(w, http.StatusOK, map[string]any{
+				"id":         "a1",
+				"name":       "P99 latency",
+				"query_id":   "q1",
+				"created_at": "2024-01-01T00:00:00Z",
+			})
+		case "GET /1/slos/api":
+			srcSLOLists.Add(1)
+			writeJSON(w, http.StatusOK, []any{
+				map[string]any{"id": "s1", "name": "Availability"},
+				map[string]any{"id": "s2", "name": "Latency"},
+			})
+		case "GET /1/boards/b1/views":
+			writeJSON(w, http.StatusOK, []any{map[string]any{
+				"id":      "v1",
+				"name":    "Errors",
+				"filters": []any{map[string]any{"column": "error", "operation": "exists"}},
+			}})
+		default:
+			t.Errorf("unexpected source request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	var board, view, query, annotation map[string]any
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Honeycomb-Team"); got != "prod-key" {
+			t.Errorf("target key = %q, want prod-key", got)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /1/datasets":
+			writeJSON(w, http.StatusOK, []any{map[string]any{"name": "API", "slug": "api-prod"}})
+		case "GET /1/columns/api-prod":
+			writeJSON(w, http.StatusOK, []any{
+				map[string]any{"key_name": "service.name"},
+				map[string]any{"key_name": "duration_ms"},
+				map[string]any{"key_name": "error"},
+			})
+		case "GET /1/derived_columns/api-prod":
+			writeJSON(w, http.StatusOK, []any{map[string]any{"alias": "region"}})
+		case "GET /1/slos/api-prod":
+			dstSLOLists.Add(1)
+			writeJSON(w, http.StatusOK, []any{
+				map[string]any{"id": "s9", "name": "Availability"},
+				map[string]any{"id": "s8", "name": "Latency"},
+			})
+		case "POST /1/queries/api-prod":
+			_ = json.NewDecoder(r.Body).Decode(&query)
+			writeJSON(w, http.StatusOK, map[string]any{"id": "q9"})
+		case "POST /1/query_annotations/api-prod":
+			_ = json.NewDecoder(r.Body).Decode(&annotation)
+			writeJSON(w, http.StatusCreated, map[string]any{"id": "a9"})
+		case "POST /1/boards":
+			_ = json.NewDecoder(r.Body).Decode(&board)
+			board["id"] = "b9"
+			writeJSON(w, http.StatusCreated, board)
+		case "POST /1/boards/b9/views":
+			_ = json.NewDecoder(r.Body).Decode(&view)
+			writeJSON(w, http.StatusCreated, map[string]any{"id": "v9"})
+		default:
+			t.Errorf("unexpected target request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(target.Close)
+
+	opts.Config.Profiles = map[string]*config.Profile{"prod": {APIUrl: target.URL}}
+	if err := config.SetKey("prod", config.KeyConfig, "prod-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("prod", config.KeyConfig) })
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"copy", "b1", "--to-profile", "prod"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := query["id"]; ok {
+		t.Error("query copy should not include id")
+	}
+	if annotation["query_id"] != "q9" {
+		t.Errorf("annotation query_id = %v, want q9", annotation["query_id"])
+	}
+	if _, ok := annotation["created_at"]; ok {
+		t.Error("annotation copy should not include created_at")
+	}
+
+	if _, ok := board["links"]; ok {
+		t.Error("board copy should not include links")
+	}
+	panels := board["panels"].([]any)
+	qp := panels[0].(map[string]any)["query_panel"].(map[string]any)
+	if qp["query_id"] != "q9" || qp["query_annotation_id"] != "a9" {
+		t.Errorf("query panel = %v, want query q9 and annotation a9", qp)
+	}
+	if _, ok := qp["dataset"]; ok {
+		t.Error("query panel should not include dataset")
+	}
+	sp := panels[1].(map[string]any)["slo_panel"].(map[string]any)
+	if sp["slo_id"] != "s9" {
+		t.Errorf("slo_id = %v, want s9", sp["slo_id"])
+	}
+	if sp := panels[2].(map[string]any)["slo_panel"].(map[string]any); sp["slo_id"] != "s8" {
+		t.Errorf("slo_id = %v, want s8", sp["slo_id"])
+	}
+	if srcSLOLists.Load() != 1 || dstSLOLists.Load() != 1 {
+		t.Errorf("listed SLOs %d times in the source and %d in the target, want once each", srcSLOLists.Load(), dstSLOLists.Load())
+	}
+	if len(board["preset_filters"].([]any)) != 2 {
+		t.Errorf("preset_filters = %v, want 2 filters", board["preset_filters"])
+	}
+
+	if view["name"] != "Errors" {
+		t.Errorf("view name = %v, want Errors", view["name"])
+	}
+	if _, ok := view["id"]; ok {
+		t.Error("view copy should not include id")
+	}
+
+	stderr := ts.ErrBuf.String()
+	if !strings.Contains(stderr, `column "endpoint" used by query q1 is not in dataset api-prod`) {
+		t.Errorf("stderr = %q, want missing endpoint column", stderr)
+	}
+	if strings.Count(stderr, "warning:") != 1 {
+		t.Errorf("stderr = %q, want exactly one warning", stderr)
+	}
+
+	var detail boardDetail
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &detail); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if detail.ID != "b9" {
+		t.Errorf("ID = %q, want b9", detail.ID)
+	}
+}
+
+func TestCopy_SameProfile(t *testing.T) {
+	opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"copy", "b1", "--to-profile", "default"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "must differ") {
+		t.Errorf("err = %v, want profile error", err)
+	}
+}
@@ -0,0 +1,36 @@
+package board
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func NewDiffCmd(opts *options.RootOptions) *cobra.Command {
+	return command.NewDiffCmd(opts.IOStreams, command.DiffCmd{
+		Use:    "diff <board-id>",
+		Short:  "Compare a board with a local definition",
+		Noun:   "board",
+		Schema: "Board",
+		Example: `  # Check a board for drift
+  honeycomb board diff abc123 --file board.json
+
+  # List changed fields instead of a line diff
+  honeycomb board diff abc123 --file board.json --style structural`,
+		Get: func(ctx context.Context, boardID string) ([]byte, error) {
+			client, err := opts.ClientFor(nil, options.AuthConfig)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.GetBoardWithResponse(ctx, boardID)
+			if err != nil {
+				return nil, fmt.Errorf("getting board: %w", err)
+			}
+			return resp.Body, api.CheckResponse(resp.StatusCode(), resp.Body)
+		},
+	})
+}
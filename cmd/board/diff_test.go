@@ -0,0 +1,76 @@
+package board
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func boardDiffServer(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/boards/b-1" {
+			t.Errorf("path = %q, want /1/boards/b-1", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "b-1",
+			"name": "Overview",
+			"type": "flexible",
+			"links": {"board_url": "https://ui.honeycomb.io/team/board/b-1"},
+			"panels": [{"type": "query", "query_panel": {"query_id": "q-1"}}]
+		}`))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		definition string
+		wantErr    string
+		wantOut    []string
+	}{
+		{
+			// The ID and links are read-only, so a board saved elsewhere
+			// compares cleanly.
+			name:       "no drift",
+			definition: `{"id": "b-2", "name": "Overview", "type": "flexible", "links": {"board_url": "https://example.com"}, "panels": [{"type": "query", "query_panel": {"query_id": "q-1"}}]}`,
+		},
+		{
+			name:       "drift",
+			definition: `{"name": "Overview", "panels": [{"type": "query", "query_panel": {"query_id": "q-2"}}]}`,
+			wantErr:    "board b-1 differs from",
+			wantOut:    []string{"--- live/b-1\n", `-        "query_id": "q-1"`, `+        "query_id": "q-2"`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, ts := setupTest(t, boardDiffServer(t))
+			file := filepath.Join(t.TempDir(), "board.json")
+			if err := os.WriteFile(file, []byte(tc.definition), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := NewCmd(opts)
+			cmd.SetArgs([]string{"diff", "b-1", "--file", file})
+			err := cmd.Execute()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ts.OutBuf.Len() != 0 || !strings.Contains(ts.ErrBuf.String(), "No differences.") {
+					t.Errorf("stdout = %q, stderr = %q, want no differences", ts.OutBuf.String(), ts.ErrBuf.String())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			for _, want := range tc.wantOut {
+				if !strings.Contains(ts.OutBuf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, ts.OutBuf.String())
+				}
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
 	"github.com/spf13/cobra"
 )
 
@@ -235,51 +236,12 @@ func encodeJSON(v any) ([]byte, error) {
 // within the "panels" array. The API returns dataset in query panels on read
 // but rejects it on write.
 func stripPanelDataset(data []byte) ([]byte, error) {
-	var board map[string]json.RawMessage
-	if err := json.Unmarshal(data, &board); err != nil {
+	var board manifest.Object
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&board); err != nil {
 		return nil, err
 	}
-
-	raw, ok := board["panels"]
-	if !ok {
-		return data, nil
-	}
-
-	var panels []map[string]json.RawMessage
-	if err := json.Unmarshal(raw, &panels); err != nil {
-		return data, err
-	}
-
-	changed := false
-	for i, panel := range panels {
-		qp, ok := panel["query_panel"]
-		if !ok {
-			continue
-		}
-		var qpMap map[string]json.RawMessage
-		if err := json.Unmarshal(qp, &qpMap); err != nil {
-			continue
-		}
-		if _, has := qpMap["dataset"]; !has {
-			continue
-		}
-		delete(qpMap, "dataset")
-		changed = true
-		reencoded, err := json.Marshal(qpMap)
-		if err != nil {
-			return nil, err
-		}
-		panels[i]["query_panel"] = reencoded
-	}
-
-	if !changed {
-		return data, nil
-	}
-
-	reencoded, err := json.Marshal(panels)
-	if err != nil {
-		return nil, err
-	}
-	board["panels"] = reencoded
+	manifest.StripPanelDatasets(board)
 	return json.Marshal(board)
 }
@@ -0,0 +1,540 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/cmd/query"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/deref"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/bendrucker/honeycomb-cli/internal/poll"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(opts *options.RootOptions) *cobra.Command {
+	var dataset string
+
+	cmd := &cobra.Command{
+		Use:   "browse",
+		Short: "Explore datasets, columns, and queries interactively",
+		Long: `Open a full-screen explorer to pick a dataset, browse its columns, and compose
+and run queries.
+
+Queries are composed one clause at a time, in the syntax of the query run
+builder flags: calc, where, breakdown, order, limit, granularity, and
+time-range. Tab completes clause names, columns, and ops. Results can be
+viewed as a table or as sparklines of the time series, and saved as a query
+annotation.`,
+		Example: `  # Pick a dataset to explore
+  honeycomb browse
+
+  # Start at the columns of a dataset
+  honeycomb browse --dataset my-dataset`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runBrowse(cmd.Context(), opts, dataset)
+		},
+	}
+
+	cmd.Flags().StringVar(&dataset, "dataset", "", "Dataset slug to start with")
+
+	return cmd
+}
+
+func runBrowse(ctx context.Context, opts *options.RootOptions, dataset string) error {
+	if !opts.IOStreams.CanPrompt() {
+		return fmt.Errorf("browse requires an interactive terminal")
+	}
+
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	p := tea.NewProgram(newBrowser(ctx, client, dataset),
+		tea.WithContext(ctx),
+		tea.WithInput(opts.IOStreams.In),
+		tea.WithOutput(opts.IOStreams.Out),
+		tea.WithAltScreen(),
+	)
+	_, err = p.Run()
+	return err
+}
+
+type browseScreen int
+
+const (
+	screenDatasets browseScreen = iota
+	screenColumns
+	screenQuery
+	screenResults
+)
+
+type (
+	datasetsMsg []api.Dataset
+	columnsMsg  struct {
+		columns    []api.Column
+		calculated []api.CalculatedField
+	}
+	resultMsg struct {
+		queryID string
+		details *api.QueryResultDetails
+	}
+	savedMsg  api.QueryAnnotation
+	browseErr struct{ err error }
+)
+
+var (
+	browseTitle = lipgloss.NewStyle().Bold(true)
+	browseHelp  = lipgloss.NewStyle().Faint(true)
+	browseError = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+// browser is the model of honeycomb browse. Each screen leads to the next:
+// datasets, the columns of the chosen dataset, a query composed from clauses,
+// and its results.
+type browser struct {
+	ctx    context.Context
+	client *api.ClientWithResponses
+
+	screen browseScreen
+	height int
+
+	datasets table.Model
+	dataset  string
+
+	columns     table.Model
+	columnNames []string
+
+	input   textinput.Model
+	clauses []string
+
+	results    table.Model
+	series     table.Model
+	showSeries bool
+	queryID    string
+	naming     bool
+
+	spinner spinner.Model
+	loading string
+	status  string
+	err     error
+}
+
+func newBrowser(ctx context.Context, client *api.ClientWithResponses, dataset string) browser {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "calc COUNT"
+	input.ShowSuggestions = true
+
+	b := browser{
+		ctx:     ctx,
+		client:  client,
+		height:  20,
+		input:   input,
+		spinner: spinner.New(spinner.WithSpinner(spinner.Dot)),
+		dataset: dataset,
+	}
+	if dataset != "" {
+		b.screen = screenColumns
+		b.loading = "Loading columns..."
+	} else {
+		b.loading = "Loading datasets..."
+	}
+	return b
+}
+
+func (b browser) Init() tea.Cmd {
+	if b.dataset != "" {
+		return tea.Batch(b.spinner.Tick, b.loadColumns())
+	}
+	return tea.Batch(b.spinner.Tick, b.loadDatasets())
+}
+
+func (b browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.height = max(msg.Height-8, 5)
+		for _, t := range []*table.Model{&b.datasets, &b.columns, &b.results, &b.series} {
+			t.SetHeight(b.height)
+		}
+		b.input.Width = max(msg.Width-4, 20)
+		return b, nil
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		b.spinner, cmd = b.spinner.Update(msg)
+		return b, cmd
+	case browseErr:
+		b.loading, b.status = "", ""
+		b.err = msg.err
+		return b, nil
+	case datasetsMsg:
+		b.loading = ""
+		b.datasets = newBrowseTable([]string{"Name", "Slug", "Last Written"}, datasetRows(msg), b.height)
+		return b, nil
+	case columnsMsg:
+		b.loading = ""
+		b.columnNames = nil
+		var rows [][]string
+		for _, c := range msg.columns {
+			name := deref.String(c.KeyName)
+			b.columnNames = append(b.columnNames, name)
+			rows = append(rows, []string{name, deref.Enum(c.Type), formatLastWritten(deref.String(c.LastWritten)), deref.String(c.Description)})
+		}
+		for _, f := range msg.calculated {
+			b.columnNames = append(b.columnNames, f.Alias)
+			rows = append(rows, []string{f.Alias, "calculated", "", deref.String(f.Description)})
+		}
+		b.columns = newBrowseTable([]string{"Column", "Type", "Last Written", "Description"}, rows, b.height)
+		return b, nil
+	case resultMsg:
+		b.loading = ""
+		b.queryID = msg.queryID
+		result := query.BuildResultTable(msg.details)
+		b.results = newBrowseTable(result.Headers, result.Rows, b.height)
+		sparklines := query.BuildSparklineTable(msg.details)
+		b.series = newBrowseTable(sparklines.Headers, sparklines.Rows, b.height)
+		b.screen = screenResults
+		b.status = ""
+		if msg.details.Links != nil && msg.details.Links.QueryUrl != nil {
+			b.status = *msg.details.Links.QueryUrl
+		}
+		return b, nil
+	case savedMsg:
+		b.loading = ""
+		b.status = fmt.Sprintf("Saved query annotation %s.", deref.String(msg.Id))
+		return b, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return b, tea.Quit
+		}
+		if b.loading != "" {
+			return b, nil
+		}
+		b.err = nil
+		return b.handleKey(msg)
+	}
+	return b, nil
+}
+
+func (b browser) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if b.naming {
+		return b.handleNameKey(msg)
+	}
+
+	switch b.screen {
+	case screenDatasets:
+		switch msg.String() {
+		case "q", "esc":
+			return b, tea.Quit
+		case "enter":
+			row := b.datasets.SelectedRow()
+			if row == nil {
+				return b, nil
+			}
+			b.dataset = row[1]
+			b.screen = screenColumns
+			b.loading = "Loading columns..."
+			return b, tea.Batch(b.spinner.Tick, b.loadColumns())
+		}
+		var cmd tea.Cmd
+		b.datasets, cmd = b.datasets.Update(msg)
+		return b, cmd
+	case screenColumns:
+		switch msg.String() {
+		case "q":
+			return b, tea.Quit
+		case "esc":
+			if b.datasets.Rows() == nil {
+				b.loading = "Loading datasets..."
+				b.screen = screenDatasets
+				return b, tea.Batch(b.spinner.Tick, b.loadDatasets())
+			}
+			b.screen = screenDatasets
+			return b, nil
+		case "enter", "tab":
+			b.screen = screenQuery
+			b.input.SetSuggestions(query.ClauseSuggestions("", b.columnNames, b.clauses))
+			return b, b.input.Focus()
+		}
+		var cmd tea.Cmd
+		b.columns, cmd = b.columns.Update(msg)
+		return b, cmd
+	case screenQuery:
+		return b.handleQueryKey(msg)
+	case screenResults:
+		switch msg.String() {
+		case "q":
+			return b, tea.Quit
+		case "esc", "e":
+			b.screen = screenQuery
+			b.status = ""
+			return b, b.input.Focus()
+		case "v":
+			b.showSeries = !b.showSeries
+			return b, nil
+		case "s":
+			b.naming = true
+			b.input.Reset()
+			b.input.Placeholder = "Annotation name"
+			b.input.SetSuggestions(nil)
+			return b, b.input.Focus()
+		}
+		var cmd tea.Cmd
+		if b.showSeries {
+			b.series, cmd = b.series.Update(msg)
+		} else {
+			b.results, cmd = b.results.Update(msg)
+		}
+		return b, cmd
+	}
+	return b, nil
+}
+
+func (b browser) handleQueryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		b.input.Blur()
+		b.screen = screenColumns
+		return b, nil
+	case tea.KeyCtrlR:
+		return b.run()
+	case tea.KeyEnter:
+		clause := strings.TrimSpace(b.input.Value())
+		if clause == "" {
+			return b.run()
+		}
+		clauses := append(append([]string{}, b.clauses...), clause)
+		if _, err := query.ComposeQuery(clauses); err != nil {
+			b.err = err
+			return b, nil
+		}
+		b.clauses = clauses
+		b.input.Reset()
+		b.input.SetSuggestions(query.ClauseSuggestions("", b.columnNames, b.clauses))
+		return b, nil
+	case tea.KeyBackspace:
+		if b.input.Value() == "" && len(b.clauses) > 0 {
+			b.clauses = b.clauses[:len(b.clauses)-1]
+			return b, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	b.input, cmd = b.input.Update(msg)
+	b.input.SetSuggestions(query.ClauseSuggestions(b.input.Value(), b.columnNames, b.clauses))
+	return b, cmd
+}
+
+func (b browser) handleNameKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		b.naming = false
+		b.input.Reset()
+		b.input.Placeholder = "calc COUNT"
+		return b, nil
+	case tea.KeyEnter:
+		name := strings.TrimSpace(b.input.Value())
+		if name == "" {
+			b.err = fmt.Errorf("annotation name is required")
+			return b, nil
+		}
+		b.naming = false
+		b.input.Reset()
+		b.input.Placeholder = "calc COUNT"
+		b.loading = "Saving..."
+		return b, tea.Batch(b.spinner.Tick, b.saveAnnotation(name))
+	}
+
+	var cmd tea.Cmd
+	b.input, cmd = b.input.Update(msg)
+	return b, cmd
+}
+
+func (b browser) run() (tea.Model, tea.Cmd) {
+	if len(b.clauses) == 0 {
+		b.err = fmt.Errorf("add a clause such as calc COUNT to run a query")
+		return b, nil
+	}
+	q, err := query.ComposeQuery(b.clauses)
+	if err != nil {
+		b.err = err
+		return b, nil
+	}
+	b.loading = "Running query..."
+	return b, tea.Batch(b.spinner.Tick, b.runQuery(q))
+}
+
+func (b browser) View() string {
+	var s strings.Builder
+
+	title := "honeycomb browse"
+	if b.dataset != "" && b.screen != screenDatasets {
+		title += " · " + b.dataset
+	}
+	s.WriteString(browseTitle.Render(title) + "\n\n")
+
+	var help string
+	switch {
+	case b.loading != "":
+		s.WriteString(b.spinner.View() + " " + b.loading + "\n")
+	case b.naming:
+		s.WriteString("Save the query as an annotation named:\n")
+		s.WriteString(b.input.View() + "\n")
+		help = "enter save · esc cancel"
+	case b.screen == screenDatasets:
+		s.WriteString(b.datasets.View() + "\n")
+		help = "enter select · q quit"
+	case b.screen == screenColumns:
+		s.WriteString(b.columns.View() + "\n")
+		help = "enter compose a query · esc datasets · q quit"
+	case b.screen == screenQuery:
+		for _, clause := range b.clauses {
+			s.WriteString("  " + clause + "\n")
+		}
+		s.WriteString(b.input.View() + "\n")
+		help = "tab complete · enter add clause, or run when empty · backspace remove last clause · esc columns"
+	case b.screen == screenResults:
+		if b.showSeries {
+			s.WriteString(b.series.View() + "\n")
+		} else {
+			s.WriteString(b.results.View() + "\n")
+		}
+		help = "v toggle series · s save · e edit query · q quit"
+	}
+
+	if b.err != nil {
+		s.WriteString("\n" + browseError.Render("Error: "+b.err.Error()) + "\n")
+	}
+	if b.status != "" {
+		s.WriteString("\n" + b.status + "\n")
+	}
+	if help != "" {
+		s.WriteString("\n" + browseHelp.Render(help) + "\n")
+	}
+	return s.String()
+}
+
+func (b browser) loadDatasets() tea.Cmd {
+	return func() tea.Msg {
+		resp, err := b.client.ListDatasetsWithResponse(b.ctx)
+		if err != nil {
+			return browseErr{fmt.Errorf("listing datasets: %w", err)}
+		}
+		datasets, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
+		if err != nil {
+			return browseErr{err}
+		}
+		return datasetsMsg(*datasets)
+	}
+}
+
+func (b browser) loadColumns() tea.Cmd {
+	return func() tea.Msg {
+		columns, err := api.DecodeResponse[[]api.Column](b.client.ListColumns(b.ctx, b.dataset, nil))
+		if err != nil {
+			return browseErr{fmt.Errorf("listing columns: %w", err)}
+		}
+		calculated, err := api.DecodeResponse[[]api.CalculatedField](b.client.ListCalculatedFields(b.ctx, b.dataset, nil))
+		if err != nil {
+			return browseErr{fmt.Errorf("listing calculated fields: %w", err)}
+		}
+		return columnsMsg{columns: columns, calculated: calculated}
+	}
+}
+
+func (b browser) runQuery(q query.ComposedQuery) tea.Cmd {
+	return func() tea.Msg {
+		spec, err := q.Spec()
+		if err != nil {
+			return browseErr{err}
+		}
+		queryID, err := query.CreateQuery(b.ctx, b.client, b.dataset, spec)
+		if err != nil {
+			return browseErr{err}
+		}
+		details, err := query.RunQueryResult(b.ctx, b.client, b.dataset, queryID, true, poll.Config{})
+		if err != nil {
+			return browseErr{err}
+		}
+		return resultMsg{queryID: queryID, details: details}
+	}
+}
+
+func (b browser) saveAnnotation(name string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := b.client.CreateQueryAnnotationWithResponse(b.ctx, b.dataset, api.QueryAnnotation{
+			Name:    name,
+			QueryId: b.queryID,
+		})
+		if err != nil {
+			return browseErr{fmt.Errorf("creating query annotation: %w", err)}
+		}
+		annotation, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON201)
+		if err != nil {
+			return browseErr{err}
+		}
+		return savedMsg(*annotation)
+	}
+}
+
+func datasetRows(datasets []api.Dataset) [][]string {
+	rows := make([][]string, len(datasets))
+	for i, d := range datasets {
+		var lastWritten string
+		if d.LastWrittenAt.IsSpecified() && !d.LastWrittenAt.IsNull() {
+			lastWritten = formatLastWritten(d.LastWrittenAt.MustGet())
+		}
+		rows[i] = []string{d.Name, deref.String(d.Slug), lastWritten}
+	}
+	return rows
+}
+
+// formatLastWritten shortens an ISO8601 timestamp to the minute, in local
+// time. Values it cannot parse are returned as-is.
+func formatLastWritten(s string) string {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return s
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// newBrowseTable builds a table sized to its contents, with columns no wider
+// than 40 characters.
+func newBrowseTable(headers []string, rows [][]string, height int) table.Model {
+	cols := make([]table.Column, len(headers))
+	for i, h := range headers {
+		width := lipgloss.Width(h)
+		for _, row := range rows {
+			if i < len(row) {
+				width = max(width, lipgloss.Width(row[i]))
+			}
+		}
+		cols[i] = table.Column{Title: h, Width: min(width, 40)}
+	}
+
+	tableRows := make([]table.Row, len(rows))
+	for i, row := range rows {
+		cells := make(table.Row, len(row))
+		for j, cell := range row {
+			cells[j] = output.Truncate(cell, 40)
+		}
+		tableRows[i] = cells
+	}
+
+	return table.New(
+		table.WithColumns(cols),
+		table.WithRows(tableRows),
+		table.WithHeight(height),
+		table.WithFocused(true),
+	)
+}
@@ -0,0 +1,188 @@
+package browse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zalando/go-keyring"
+)
+
+func init() {
+	keyring.MockInit()
+}
+
+func setupTest(t *testing.T, handler http.Handler) (*options.RootOptions, *iostreams.TestStreams) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{
+		IOStreams: ts.IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    srv.URL,
+		Format:    output.FormatJSON,
+	}
+
+	if err := config.SetKey("default", config.KeyConfig, "test-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyConfig) })
+
+	return opts, ts
+}
+
+func TestBrowse(t *testing.T) {
+	var annotation api.QueryAnnotation
+	opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/1/datasets":
+			_, _ = w.Write([]byte(`[{"name": "Production", "slug": "production", "last_written_at": "2025-01-02T03:04:05Z"}]`))
+		case r.URL.Path == "/1/columns/production":
+			_, _ = w.Write([]byte(`[{"key_name": "service.name", "type": "string", "last_written": "2025-01-02T03:04:05Z"}]`))
+		case r.URL.Path == "/1/derived_columns/production":
+			_, _ = w.Write([]byte(`[{"alias": "is_error", "expression": "EQUALS($status, 500)"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/1/queries/production":
+			var spec map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&spec)
+			if spec["time_range"] != float64(7200) {
+				t.Errorf("time_range = %v, want the default of 2h", spec["time_range"])
+			}
+			_, _ = w.Write([]byte(`{"id": "qry-1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/production":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "result-1"}`))
+		case r.URL.Path == "/1/query_results/production/result-1":
+			_, _ = w.Write([]byte(`{
+				"id": "result-1",
+				"complete": true,
+				"data": {
+					"results": [{"data": {"service.name": "api", "COUNT": 10}}],
+					"series": [{"time": "2025-01-02T03:00:00Z", "data": {"service.name": "api", "COUNT": 10}}]
+				},
+				"query": {"breakdowns": ["service.name"], "calculations": [{"op": "COUNT"}]}
+			}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/1/query_annotations/production":
+			_ = json.NewDecoder(r.Body).Decode(&annotation)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "ann-1", "name": "API volume", "query_id": "qry-1"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := newBrowser(t.Context(), client, "")
+	b = drive(t, b, b.Init())
+	if got := b.datasets.SelectedRow(); len(got) == 0 || got[1] != "production" {
+		t.Fatalf("selected dataset row = %v", got)
+	}
+
+	b = press(t, b, tea.KeyMsg{Type: tea.KeyEnter})
+	if want := []string{"service.name", "is_error"}; !slices.Equal(b.columnNames, want) {
+		t.Errorf("columns = %v, want %v", b.columnNames, want)
+	}
+
+	b = press(t, b, tea.KeyMsg{Type: tea.KeyEnter})
+	if b.screen != screenQuery {
+		t.Fatalf("screen = %d, want the query composer", b.screen)
+	}
+	b = typeClause(t, b, "calc COUNT")
+	b = typeClause(t, b, "breakdown service.name")
+	if want := []string{"calc COUNT", "breakdown service.name"}; !slices.Equal(b.clauses, want) {
+		t.Errorf("clauses = %v, want %v", b.clauses, want)
+	}
+
+	b = press(t, b, tea.KeyMsg{Type: tea.KeyEnter})
+	if b.err != nil {
+		t.Fatal(b.err)
+	}
+	if b.screen != screenResults {
+		t.Fatalf("screen = %d, want results", b.screen)
+	}
+	if got := b.results.Rows(); len(got) != 1 || got[0][0] != "api" || got[0][1] != "10" {
+		t.Errorf("result rows = %v", got)
+	}
+	if got := b.series.Rows(); len(got) != 1 {
+		t.Errorf("series rows = %v, want one sparkline", got)
+	}
+
+	b = press(t, b, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	b = typeClause(t, b, "API volume")
+	if b.err != nil {
+		t.Fatal(b.err)
+	}
+	if annotation.Name != "API volume" || annotation.QueryId != "qry-1" {
+		t.Errorf("annotation = %+v", annotation)
+	}
+	if !strings.Contains(b.View(), "Saved query annotation ann-1.") {
+		t.Errorf("view = %s", b.View())
+	}
+}
+
+func TestBrowse_NonInteractive(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+	err := runBrowse(t.Context(), opts, "")
+	if err == nil || !strings.Contains(err.Error(), "interactive terminal") {
+		t.Errorf("err = %v, want an interactive terminal error", err)
+	}
+}
+
+// drive runs cmd and feeds the messages it produces back into b, as the
+// program would, skipping spinner ticks.
+func drive(t *testing.T, b browser, cmd tea.Cmd) browser {
+	t.Helper()
+	if cmd == nil {
+		return b
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			b = drive(t, b, c)
+		}
+	case spinner.TickMsg, nil:
+	default:
+		m, next := b.Update(msg)
+		b = drive(t, m.(browser), next)
+	}
+	return b
+}
+
+// press sends a key to b, running the commands it starts only when they load
+// data, so that cursor blinks are not waited on.
+func press(t *testing.T, b browser, msg tea.KeyMsg) browser {
+	t.Helper()
+	m, cmd := b.Update(msg)
+	b = m.(browser)
+	if b.loading != "" {
+		return drive(t, b, cmd)
+	}
+	return b
+}
+
+func typeClause(t *testing.T, b browser, text string) browser {
+	t.Helper()
+	for _, r := range text {
+		b = press(t, b, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return press(t, b, tea.KeyMsg{Type: tea.KeyEnter})
+}
@@ -0,0 +1,157 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/bendrucker/honeycomb-cli/internal/jsondiff"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DiffUnified    = "unified"
+	DiffStructural = "structural"
+)
+
+// DiffStyles are the accepted values of a diff command's --style flag.
+var DiffStyles = []string{DiffUnified, DiffStructural}
+
+// DiffCmd describes the diff command of a resource kind.
+type DiffCmd struct {
+	// Use and Short are the command's usage line and summary, as in "diff
+	// <trigger-id>" and "Compare a trigger with a local definition".
+	Use, Short string
+	// Noun names the resource in help text and errors, such as "trigger".
+	Noun string
+	// Schema is the API schema whose read-only fields are ignored.
+	Schema  string
+	Example string
+	// Get returns the API response body of the live resource with id.
+	Get func(ctx context.Context, id string) ([]byte, error)
+}
+
+// NewDiffCmd returns a command comparing one live resource with a local
+// definition file. It exits non-zero when they differ.
+func NewDiffCmd(ios *iostreams.IOStreams, d DiffCmd) *cobra.Command {
+	var (
+		file  string
+		style string
+	)
+
+	cmd := &cobra.Command{
+		Use:   d.Use,
+		Short: d.Short,
+		Long: "Compare a live " + d.Noun + " with a local JSON definition and print the differences. " +
+			"Read-only fields are ignored on both sides, as are fields the definition omits. " +
+			"Exits non-zero when the " + d.Noun + " has drifted from the definition.",
+		Example: d.Example,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ValidateEnum("style", style, DiffStyles); err != nil {
+				return err
+			}
+
+			local, err := ReadDefinitionFile(ios, file)
+			if err != nil {
+				return err
+			}
+			live, err := d.Get(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			drifted, err := DiffDefinition(ios, style, d.Schema, live, local, "live/"+args[0], file)
+			if err != nil {
+				return err
+			}
+			if drifted {
+				return fmt.Errorf("%s %s differs from %s", d.Noun, args[0], file)
+			}
+			_, _ = fmt.Fprintln(ios.Err, "No differences.")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Path to JSON file with the "+d.Noun+" definition (- for stdin)")
+	cmd.Flags().StringVar(&style, "style", DiffUnified, "Diff style: "+EnumUsage(DiffStyles))
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// DiffDefinition compares a local JSON definition with the live resource's
+// API response. Both sides have the schema's read-only fields stripped, so a
+// definition saved from a get compares cleanly; see WriteDiff for how the
+// rest is compared and written.
+func DiffDefinition(ios *iostreams.IOStreams, style, schema string, live, local []byte, liveLabel, localLabel string) (bool, error) {
+	liveValue, err := normalize(live, schema)
+	if err != nil {
+		return false, fmt.Errorf("parsing live resource: %w", err)
+	}
+	localValue, err := normalize(local, schema)
+	if err != nil {
+		return false, fmt.Errorf("parsing %s: %w", localLabel, err)
+	}
+	return WriteDiff(ios, style, liveValue, localValue, liveLabel, localLabel)
+}
+
+func normalize(data []byte, schema string) (any, error) {
+	stripped, err := api.StripReadOnly(data, schema)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(stripped, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// WriteDiff writes the difference from live to local in the given style and
+// reports whether there is one. Live fields that local omits, such as server
+// defaults, are ignored. A nil live or local stands for a resource that would
+// be created or deleted. The diff is colored when writing to a terminal.
+func WriteDiff(ios *iostreams.IOStreams, style string, live, local any, liveLabel, localLabel string) (bool, error) {
+	if local != nil {
+		live = jsondiff.Project(local, live)
+	}
+	if live == nil {
+		liveLabel = "/dev/null"
+	}
+	if local == nil {
+		localLabel = "/dev/null"
+	}
+
+	var diff string
+	switch style {
+	case DiffStructural:
+		changes := jsondiff.Structural(live, local)
+		if len(changes) == 0 {
+			return false, nil
+		}
+		lines := make([]string, len(changes))
+		for i, c := range changes {
+			lines[i] = c.String()
+		}
+		diff = fmt.Sprintf("--- %s\n+++ %s\n%s\n", liveLabel, localLabel, strings.Join(lines, "\n"))
+	default:
+		var err error
+		diff, err = jsondiff.Unified(live, local, liveLabel, localLabel)
+		if err != nil {
+			return false, err
+		}
+		if diff == "" {
+			return false, nil
+		}
+	}
+
+	if ios.ColorEnabled() {
+		diff = jsondiff.Colorize(diff)
+	}
+	_, err := fmt.Fprint(ios.Out, diff)
+	return true, err
+}
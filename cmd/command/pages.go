@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// ListLimit holds the --limit flag of list commands whose API results are
+// paginated. Without it, every item is listed. --all is deprecated, since
+// listing every item is already the default.
+type ListLimit struct {
+	Limit int
+	All   bool
+}
+
+// AddFlags registers --limit and the deprecated --all on cmd.
+func (l *ListLimit) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&l.Limit, "limit", "L", 0, "Maximum number of items to list (default all)")
+	cmd.Flags().BoolVar(&l.All, "all", false, "List every item, fetching as many pages as needed")
+	cmd.MarkFlagsMutuallyExclusive("limit", "all")
+	_ = cmd.Flags().MarkDeprecated("all", "every item is listed unless --limit is set")
+}
+
+// Max returns the item limit to paginate with, 0 for every item.
+func (l ListLimit) Max() (int, error) {
+	if l.All {
+		return 0, nil
+	}
+	if l.Limit < 0 {
+		return 0, fmt.Errorf("--limit must not be negative")
+	}
+	return l.Limit, nil
+}
+
+// WritePages streams a paginated list to w, converting each item with toRow,
+// so rows are written as pages arrive. When the limit leaves items unlisted it
+// says so on stderr.
+func WritePages[T, R any](ctx context.Context, ios *iostreams.IOStreams, w *output.Writer, pages *api.Pages[T], td output.TableDef, emptyMessage string, toRow func(T) R) error {
+	stream := w.StreamList(td, emptyMessage)
+	count := 0
+	for items, err := range pages.All(ctx) {
+		if err != nil {
+			_ = stream.Abort()
+			return err
+		}
+		rows := make([]R, len(items))
+		for i, item := range items {
+			rows[i] = toRow(item)
+		}
+		if err := stream.Append(rows); err != nil {
+			return err
+		}
+		count += len(items)
+	}
+	if err := stream.Close(); err != nil {
+		return err
+	}
+
+	if pages.Truncated() {
+		_, _ = fmt.Fprintf(ios.Err, "Showing the first %d results. Raise or omit --limit to list more.\n", count)
+	}
+	return nil
+}
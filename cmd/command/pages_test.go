@@ -0,0 +1,66 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/oapi-codegen/nullable"
+	"github.com/spf13/cobra"
+)
+
+func TestListLimit(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want int
+		err  bool
+	}{
+		{args: nil, want: 0},
+		{args: []string{"--all"}, want: 0},
+		{args: []string{"--limit", "5"}, want: 5},
+		{args: []string{"--limit", "-1"}, err: true},
+	} {
+		var l ListLimit
+		cmd := &cobra.Command{}
+		l.AddFlags(cmd)
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := l.Max()
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("%v: Max() = %d, %v; want %d", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestWritePages_PageError(t *testing.T) {
+	fetchErr := errors.New("page 2 failed")
+	pages := api.NewPages(func(_ context.Context, cursor string, _ int) ([]string, *api.PaginationLinks, error) {
+		if cursor != "" {
+			return nil, nil, fetchErr
+		}
+		next := &api.PaginationLinks{Next: nullable.NewNullableWithValue("/1/items?page[after]=c1")}
+		return []string{"a", "b"}, next, nil
+	}, 0)
+
+	ts := iostreams.Test(t)
+	var buf bytes.Buffer
+	td := output.TableDef{Columns: []output.Column{output.Col("Name", func(s string) string { return s })}}
+	err := WritePages(t.Context(), ts.IOStreams, output.New(&buf, output.FormatJSON), pages, td, "", func(s string) string { return s })
+	if !errors.Is(err, fetchErr) {
+		t.Fatalf("err = %v, want %v", err, fetchErr)
+	}
+
+	var got []string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output %q is not a JSON array: %v", buf.String(), err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %v, want the first page", got)
+	}
+}
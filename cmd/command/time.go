@@ -0,0 +1,180 @@
+package command
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeFormats describes the values accepted by Time flags, for use in flag
+// usage strings.
+const TimeFormats = "now, relative (-2h, 7d), RFC3339, date, or Unix timestamp"
+
+// Now is the clock that relative times resolve against. Tests replace it to
+// get deterministic results.
+var Now = time.Now
+
+// Time is a pflag.Value for timestamp flags. It accepts the formats listed in
+// TimeFormats; register it with cmd.Flags().Var(&t, name, usage).
+type Time struct {
+	time.Time
+}
+
+func (t *Time) Set(s string) error {
+	parsed, err := ParseTime(s, Now())
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (t *Time) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (t *Time) Type() string {
+	return "time"
+}
+
+// UnixSeconds returns the time as the int Unix timestamp the generated API
+// types use.
+func (t Time) UnixSeconds() int {
+	return int(t.Unix())
+}
+
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+}
+
+// ParseTime parses a human-friendly timestamp relative to now:
+//
+//   - "now"
+//   - a duration ago, such as "2h", "-2h", or "7d"; a leading "+" is in the future
+//   - RFC3339, such as "2024-01-02T15:04:05Z"
+//   - a local date or date-time, such as "2024-01-02" or "2024-01-02 15:04"
+//   - a Unix timestamp in seconds
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+	if strings.EqualFold(s, "now") {
+		return now, nil
+	}
+
+	if isDigits(s) {
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid Unix timestamp %q", s)
+		}
+		return time.Unix(sec, 0), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	sign := -1
+	rel := s
+	switch s[0] {
+	case '+':
+		sign, rel = 1, s[1:]
+	case '-':
+		rel = s[1:]
+	}
+	if d, err := ParseDuration(rel); err == nil {
+		return now.Add(time.Duration(sign) * d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: expected %s", s, TimeFormats)
+}
+
+var durationPart = regexp.MustCompile(`^(\d+(?:\.\d+)?)(ns|us|µs|ms|s|m|h|d|w)`)
+
+// ParseDuration parses a Go duration extended with d (24h) and w (7d) units,
+// such as "7d" or "1d12h". Negative durations are rejected.
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	for rest := s; rest != ""; {
+		m := durationPart.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		rest = rest[len(m[0]):]
+
+		var unit time.Duration
+		switch m[2] {
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		default:
+			d, err := time.ParseDuration(m[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			total += d
+			continue
+		}
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n * float64(unit))
+	}
+	return total, nil
+}
+
+// Duration is a pflag.Value for duration flags that also accepts d and w
+// units; see ParseDuration.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) Set(s string) error {
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+func (d *Duration) String() string {
+	if d.Duration == 0 {
+		return ""
+	}
+	return d.Duration.String()
+}
+
+func (d *Duration) Type() string {
+	return "duration"
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   string
+		want time.Time
+	}{
+		{name: "now", in: "now", want: now},
+		{name: "now uppercase", in: "NOW", want: now},
+		{name: "negative relative", in: "-2h", want: now.Add(-2 * time.Hour)},
+		{name: "unsigned relative is in the past", in: "7d", want: now.Add(-7 * 24 * time.Hour)},
+		{name: "positive relative", in: "+30m", want: now.Add(30 * time.Minute)},
+		{name: "compound relative", in: "-1d12h", want: now.Add(-36 * time.Hour)},
+		{name: "weeks", in: "2w", want: now.Add(-14 * 24 * time.Hour)},
+		{name: "rfc3339", in: "2024-01-02T03:04:05Z", want: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{name: "rfc3339 offset", in: "2024-01-02T03:04:05-07:00", want: time.Date(2024, 1, 2, 10, 4, 5, 0, time.UTC)},
+		{name: "date", in: "2024-01-02", want: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		{name: "date time", in: "2024-01-02 15:04", want: time.Date(2024, 1, 2, 15, 4, 0, 0, time.Local)},
+		{name: "unix", in: "1700000000", want: time.Unix(1700000000, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.in, now)
+			if err != nil {
+				t.Fatalf("ParseTime(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTime_Invalid(t *testing.T) {
+	for _, in := range []string{"", "yesterday", "-", "2h ago", "2024-13-01", "5x"} {
+		if _, err := ParseTime(in, time.Now()); err == nil {
+			t.Errorf("ParseTime(%q): expected error", in)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{in: "90s", want: 90 * time.Second},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "7d", want: 7 * 24 * time.Hour},
+		{in: "1.5d", want: 36 * time.Hour},
+		{in: "1w2d", want: 9 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil {
+			t.Errorf("ParseDuration(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "d", "-2h", "7days", "h1"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q): expected error", in)
+		}
+	}
+}
+
+func TestTimeFlag(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	orig := Now
+	Now = func() time.Time { return now }
+	t.Cleanup(func() { Now = orig })
+
+	var (
+		start Time
+		since Duration
+	)
+	cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return nil }}
+	cmd.Flags().Var(&start, "start-time", "")
+	cmd.Flags().Var(&since, "since", "")
+
+	cmd.SetArgs([]string{"--start-time", "-1h", "--since", "3d"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	if got, want := start.UnixSeconds(), int(now.Add(-time.Hour).Unix()); got != want {
+		t.Errorf("start = %d, want %d", got, want)
+	}
+	if since.Duration != 72*time.Hour {
+		t.Errorf("since = %s, want 72h", since.Duration)
+	}
+
+	cmd.SetArgs([]string{"--start-time", "bogus"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected error for invalid --start-time")
+	}
+}
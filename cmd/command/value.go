@@ -0,0 +1,32 @@
+package command
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// decimalNumber matches the JSON number syntax, which has no leading plus,
+// leading zeros, hexadecimal, or infinities.
+var decimalNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// InferValue converts a text value, such as a CSV cell or a --where value, to
+// a number or boolean when it parses as one, so numeric values are sent to
+// Honeycomb as numbers. Values that would not survive the conversion, such as
+// the identifier 00123, +1, or NaN, are kept as strings.
+func InferValue(s string) any {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(i, 10) == s {
+		return i
+	}
+	if decimalNumber.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
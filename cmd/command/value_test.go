@@ -0,0 +1,24 @@
+package command
+
+import "testing"
+
+func TestInferValue(t *testing.T) {
+	for in, want := range map[string]any{
+		"42":    int64(42),
+		"-7":    int64(-7),
+		"1.5":   1.5,
+		"2e3":   2000.0,
+		"true":  true,
+		"00123": "00123",
+		"+1":    "+1",
+		"0x1F":  "0x1F",
+		"Inf":   "Inf",
+		"NaN":   "NaN",
+		"1_000": "1_000",
+		"api":   "api",
+	} {
+		if got := InferValue(in); got != want {
+			t.Errorf("InferValue(%q) = %#v, want %#v", in, got, want)
+		}
+	}
+}
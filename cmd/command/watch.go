@@ -0,0 +1,166 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/bendrucker/honeycomb-cli/internal/poll"
+	"github.com/spf13/cobra"
+)
+
+// DefaultWatchInterval is how often --watch refreshes without an interval.
+const DefaultWatchInterval = 30 * time.Second
+
+// WatchFlag holds the --watch flag of commands that can refresh their output
+// until interrupted.
+type WatchFlag struct {
+	Interval Duration
+}
+
+// AddFlags registers --watch on cmd. The interval is optional, and is given
+// either attached, as in --watch=10s, or as the next argument, as in
+// --watch 10s. The flag parser leaves a separate interval among the
+// positional arguments, so AddFlags wraps cmd's Args and RunE to take it out
+// first; it must be called after they are set.
+func (w *WatchFlag) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().Var(&w.Interval, "watch", "Refresh every interval until interrupted (default "+DefaultWatchInterval.String()+")")
+	cmd.Flags().Lookup("watch").NoOptDefVal = DefaultWatchInterval.String()
+
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			_, args = w.intervalArg(cmd, args)
+			return validate(cmd, args)
+		}
+	}
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			if interval, rest := w.intervalArg(cmd, args); interval != 0 {
+				w.Interval.Duration = interval
+				args = rest
+			}
+			return run(cmd, args)
+		}
+	}
+}
+
+// intervalArg returns the interval given as a separate argument after a bare
+// --watch, and args without it. It returns 0 and args unchanged when --watch
+// was not given bare or no positional argument before -- is a duration.
+func (w *WatchFlag) intervalArg(cmd *cobra.Command, args []string) (time.Duration, []string) {
+	if !cmd.Flags().Changed("watch") || w.Interval.Duration != DefaultWatchInterval {
+		return 0, args
+	}
+	end := len(args)
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		end = dash
+	}
+	for i, arg := range args[:end] {
+		if interval, err := ParseDuration(arg); err == nil && interval > 0 {
+			return interval, append(args[:i:i], args[i+1:]...)
+		}
+	}
+	return 0, args
+}
+
+// Enabled reports whether --watch was set.
+func (w WatchFlag) Enabled() bool {
+	return w.Interval.Duration != 0
+}
+
+// Watch refreshes rows every Interval until the context is cancelled. In a
+// terminal it redraws every row in place with Render. Otherwise it writes
+// each row as a JSON line when it first appears and again whenever its state
+// changes, and a removal record when it drops out of a refresh, so the output
+// can be piped into a notifier. A row that reappears is written again.
+type Watch[R any] struct {
+	Interval time.Duration
+	// Fetch returns the current rows.
+	Fetch func(ctx context.Context) ([]R, error)
+	// Key identifies a row across refreshes.
+	Key func(R) string
+	// State is compared between refreshes to decide whether a row changed.
+	// Without it, rows are compared by their JSON encoding.
+	State func(R) string
+	// Render writes every row, for the terminal.
+	Render func(rows []R) error
+}
+
+// Run watches until ctx is cancelled. An error from the first fetch is
+// returned; later errors are reported on stderr and the next refresh retries.
+func (w Watch[R]) Run(ctx context.Context, ios *iostreams.IOStreams) error {
+	if w.Interval <= 0 {
+		return fmt.Errorf("--watch interval must be positive")
+	}
+
+	tty := ios.IsStdoutTTY()
+	seen := map[string]string{}
+	first := true
+	enc := json.NewEncoder(ios.Out)
+
+	return poll.Watch(ctx, w.Interval, func(ctx context.Context) error {
+		rows, err := w.Fetch(ctx)
+		if err != nil {
+			if first || ctx.Err() != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(ios.Err, "%s: %v\n", time.Now().Format(time.TimeOnly), err)
+			return nil
+		}
+		first = false
+
+		if tty {
+			_, _ = fmt.Fprintf(ios.Out, "\x1b[H\x1b[2J")
+			_, _ = fmt.Fprintf(ios.Out, "Every %s, updated %s. Press Ctrl+C to stop.\n\n", w.Interval, time.Now().Format(time.TimeOnly))
+			return w.Render(rows)
+		}
+
+		current := make(map[string]string, len(rows))
+		for _, row := range rows {
+			state, err := w.state(row)
+			if err != nil {
+				return err
+			}
+			key := w.Key(row)
+			current[key] = state
+			if prev, ok := seen[key]; ok && prev == state {
+				continue
+			}
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+
+		var removed []string
+		for key := range seen {
+			if _, ok := current[key]; !ok {
+				removed = append(removed, key)
+			}
+		}
+		slices.Sort(removed)
+		for _, key := range removed {
+			if err := enc.Encode(watchRemoval{ID: key, Removed: true}); err != nil {
+				return err
+			}
+		}
+		seen = current
+		return nil
+	})
+}
+
+// watchRemoval is written when a row is no longer returned by a refresh.
+type watchRemoval struct {
+	ID      string `json:"id"`
+	Removed bool   `json:"removed"`
+}
+
+func (w Watch[R]) state(row R) (string, error) {
+	if w.State != nil {
+		return w.State(row), nil
+	}
+	b, err := json.Marshal(row)
+	return string(b), err
+}
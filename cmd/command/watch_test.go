@@ -0,0 +1,220 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type watchRow struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+}
+
+func TestWatch_ChangedRows(t *testing.T) {
+	ts := iostreams.Test(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := [][]watchRow{
+		{{ID: "a", State: "ok"}, {ID: "b", State: "ok"}},
+		{{ID: "a", State: "ok"}, {ID: "b", State: "alerting"}},
+		{{ID: "a", State: "ok"}, {ID: "b", State: "alerting"}, {ID: "c", State: "ok"}},
+	}
+	calls := 0
+	err := Watch[watchRow]{
+		Interval: time.Millisecond,
+		Fetch: func(context.Context) ([]watchRow, error) {
+			rows := polls[calls]
+			calls++
+			if calls == len(polls) {
+				cancel()
+			}
+			return rows, nil
+		},
+		Key: func(r watchRow) string { return r.ID },
+	}.Run(ctx, ts.IOStreams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"id":"a","state":"ok"}
+{"id":"b","state":"ok"}
+{"id":"b","state":"alerting"}
+{"id":"c","state":"ok"}
+`
+	if got := ts.OutBuf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWatch_RemovedRows(t *testing.T) {
+	ts := iostreams.Test(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polls := [][]watchRow{
+		{{ID: "a", State: "anomalous"}, {ID: "b", State: "anomalous"}},
+		{{ID: "b", State: "anomalous"}},
+		{{ID: "a", State: "anomalous"}, {ID: "b", State: "anomalous"}},
+	}
+	calls := 0
+	err := Watch[watchRow]{
+		Interval: time.Millisecond,
+		Fetch: func(context.Context) ([]watchRow, error) {
+			rows := polls[calls]
+			calls++
+			if calls == len(polls) {
+				cancel()
+			}
+			return rows, nil
+		},
+		Key: func(r watchRow) string { return r.ID },
+	}.Run(ctx, ts.IOStreams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"id":"a","state":"anomalous"}
+{"id":"b","state":"anomalous"}
+{"id":"a","removed":true}
+{"id":"a","state":"anomalous"}
+`
+	if got := ts.OutBuf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWatch_Errors(t *testing.T) {
+	ts := iostreams.Test(t)
+	err := Watch[watchRow]{
+		Interval: time.Millisecond,
+		Fetch: func(context.Context) ([]watchRow, error) {
+			return nil, errors.New("unauthorized")
+		},
+		Key: func(r watchRow) string { return r.ID },
+	}.Run(context.Background(), ts.IOStreams)
+	if err == nil || err.Error() != "unauthorized" {
+		t.Errorf("error = %v, want unauthorized", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := 0
+	err = Watch[watchRow]{
+		Interval: time.Millisecond,
+		Fetch: func(context.Context) ([]watchRow, error) {
+			calls++
+			switch calls {
+			case 1:
+				return []watchRow{{ID: "a"}}, nil
+			case 2:
+				return nil, errors.New("timeout")
+			}
+			cancel()
+			return nil, nil
+		},
+		Key: func(r watchRow) string { return r.ID },
+	}.Run(ctx, ts.IOStreams)
+	if err != nil {
+		t.Fatalf("later errors should not stop watching: %v", err)
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "timeout") {
+		t.Errorf("stderr = %q, want the refresh error", ts.ErrBuf.String())
+	}
+}
+
+func TestWatch_Terminal(t *testing.T) {
+	ts := iostreams.TestPromptable(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	renders := 0
+	err := Watch[watchRow]{
+		Interval: time.Millisecond,
+		Fetch: func(context.Context) ([]watchRow, error) {
+			return []watchRow{{ID: "a"}}, nil
+		},
+		Key: func(r watchRow) string { return r.ID },
+		Render: func(rows []watchRow) error {
+			renders++
+			if renders == 2 {
+				cancel()
+			}
+			_, err := ts.Out.Write([]byte("table\n"))
+			return err
+		},
+	}.Run(ctx, ts.IOStreams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := ts.OutBuf.String()
+	if n := strings.Count(out, "\x1b[H\x1b[2J"); n != 2 {
+		t.Errorf("screen cleared %d times, want 2", n)
+	}
+	if !strings.Contains(out, "Every 1ms") || strings.Count(out, "table\n") != 2 {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestWatchFlag(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want time.Duration
+	}{
+		{nil, 0},
+		{[]string{"--watch"}, DefaultWatchInterval},
+		{[]string{"--watch=5m"}, 5 * time.Minute},
+	} {
+		var w WatchFlag
+		cmd := &cobra.Command{}
+		w.AddFlags(cmd)
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if w.Interval.Duration != tt.want || w.Enabled() != (tt.want != 0) {
+			t.Errorf("%v: interval = %s, enabled = %t", tt.args, w.Interval.Duration, w.Enabled())
+		}
+	}
+}
+
+func TestWatchFlag_SeparateInterval(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		interval time.Duration
+		id       string
+	}{
+		{[]string{"--watch", "10s", "abc"}, 10 * time.Second, "abc"},
+		{[]string{"abc", "--watch", "1m"}, time.Minute, "abc"},
+		{[]string{"--watch", "abc"}, DefaultWatchInterval, "abc"},
+		{[]string{"--watch=5m", "abc"}, 5 * time.Minute, "abc"},
+		{[]string{"--watch", "--", "10s"}, DefaultWatchInterval, "10s"},
+	} {
+		var (
+			w   WatchFlag
+			got []string
+		)
+		cmd := &cobra.Command{
+			Args: cobra.ExactArgs(1),
+			RunE: func(_ *cobra.Command, args []string) error {
+				got = args
+				return nil
+			},
+		}
+		w.AddFlags(cmd)
+		cmd.SetArgs(tt.args)
+		if err := cmd.Execute(); err != nil {
+			t.Errorf("%v: %v", tt.args, err)
+			continue
+		}
+		if w.Interval.Duration != tt.interval || len(got) != 1 || got[0] != tt.id {
+			t.Errorf("%v: interval = %s, args = %q; want %s, [%s]", tt.args, w.Interval.Duration, got, tt.interval, tt.id)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package dataset
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/fakeapi"
+)
+
+func TestLifecycle_FakeAPI(t *testing.T) {
+	opts, ts := setupTest(t, fakeapi.New(fakeapi.Options{}))
+
+	run := func(args ...string) error {
+		t.Helper()
+		ts.OutBuf.Reset()
+		cmd := NewCmd(opts)
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := run("create", "--name", "Checkout API", "--description", "Checkout traffic"); err != nil {
+		t.Fatal(err)
+	}
+	var created datasetDetail
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &created); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, ts.OutBuf.String())
+	}
+	if created.Slug != "checkout-api" || created.Description != "Checkout traffic" {
+		t.Errorf("created = %+v", created)
+	}
+
+	if err := run("delete", "checkout-api", "--yes"); err == nil || !strings.Contains(err.Error(), "is delete protected") {
+		t.Errorf("err = %v, want a delete protection error", err)
+	}
+	if err := run("update", "checkout-api", "--delete-protected=false"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run("delete", "checkout-api", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run("list"); err != nil {
+		t.Fatal(err)
+	}
+	if out := strings.TrimSpace(ts.OutBuf.String()); out != "[]" {
+		t.Errorf("list after delete = %s", out)
+	}
+}
@@ -0,0 +1,23 @@
+package dev
+
+import (
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(opts *options.RootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Tools for developing against the Honeycomb API",
+		Example: `  # Serve a fake Honeycomb API with an example dataset
+  honeycomb dev server --seed
+
+  # Run commands against it
+  honeycomb dataset list --api-url http://127.0.0.1:8080`,
+	}
+
+	cmd.AddCommand(NewServerCmd(opts))
+
+	return command.Group(cmd)
+}
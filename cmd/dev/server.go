@@ -0,0 +1,92 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/fakeapi"
+	"github.com/spf13/cobra"
+)
+
+type serverOptions struct {
+	addr        string
+	team        string
+	environment string
+	seed        bool
+}
+
+func NewServerCmd(opts *options.RootOptions) *cobra.Command {
+	var o serverOptions
+
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Serve a fake Honeycomb API",
+		Long: "Serve an in-memory fake of the Honeycomb API for local development and tests, " +
+			"with no network or Honeycomb team needed.\n\n" +
+			"The fake serves datasets, columns, calculated fields, boards, triggers, SLOs, burn alerts, " +
+			"markers, recipients, queries, and query annotations, query results with canned data, " +
+			"and the Management API's environments and API keys. Everything is kept in memory and is " +
+			"lost when the server stops.\n\n" +
+			"Point commands at it with --api-url. Any API key is accepted, so a profile needs a key " +
+			"of the right type stored, but its value does not matter.",
+		Example: `  # Serve on the default address with an example dataset
+  honeycomb dev server --seed
+
+  # Serve on another port for a different team
+  honeycomb dev server --addr 127.0.0.1:9000 --team acme
+
+  # List datasets from the fake
+  honeycomb dataset list --api-url http://127.0.0.1:8080`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ln, err := net.Listen("tcp", o.addr)
+			if err != nil {
+				return fmt.Errorf("listening on %s: %w", o.addr, err)
+			}
+			return serve(cmd.Context(), opts, ln, o)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.addr, "addr", "127.0.0.1:8080", "Address to serve on")
+	cmd.Flags().StringVar(&o.team, "team", "dev", "Team slug the fake serves")
+	cmd.Flags().StringVar(&o.environment, "environment", "development", "Name of the fake team's default environment")
+	cmd.Flags().BoolVar(&o.seed, "seed", false, "Start with an example dataset and columns")
+
+	return cmd
+}
+
+// serve runs the fake API on ln until ctx is done.
+func serve(ctx context.Context, opts *options.RootOptions, ln net.Listener, o serverOptions) error {
+	fake := fakeapi.New(fakeapi.Options{Team: o.team, Environment: o.environment})
+	if o.seed {
+		fake.Seed()
+	}
+
+	hs := &http.Server{Handler: fake, ReadHeaderTimeout: 10 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- hs.Serve(ln) }()
+
+	url := "http://" + ln.Addr().String()
+	_, _ = fmt.Fprintf(opts.IOStreams.Err, "Serving a fake Honeycomb API at %s. Press Ctrl+C to stop.\n", url)
+	_, _ = fmt.Fprintf(opts.IOStreams.Err, "Run commands against it with --api-url %s.\n", url)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := hs.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
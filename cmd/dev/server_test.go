@@ -0,0 +1,72 @@
+package dev
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+)
+
+func TestServe(t *testing.T) {
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{IOStreams: ts.IOStreams}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + ln.Addr().String()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- serve(ctx, opts, ln, serverOptions{team: "acme", seed: true}) }()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url+"/1/datasets", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Honeycomb-Team", "any-key")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var datasets []struct {
+		Slug string `json:"slug"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&datasets)
+	_ = resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datasets) != 1 || datasets[0].Slug != "example" {
+		t.Errorf("datasets = %+v, want the seeded example dataset", datasets)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("serve: %v", err)
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "--api-url "+url) {
+		t.Errorf("stderr = %q, want the API URL", ts.ErrBuf.String())
+	}
+}
+
+func TestServerCmd_AddressInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	ts := iostreams.Test(t)
+	cmd := NewServerCmd(&options.RootOptions{IOStreams: ts.IOStreams})
+	cmd.SetArgs([]string{"--addr", ln.Addr().String()})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "listening on") {
+		t.Errorf("err = %v, want a listen error", err)
+	}
+}
@@ -0,0 +1,116 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	files []string
+	prune bool
+	style string
+}
+
+func NewCmd(opts *options.RootOptions) *cobra.Command {
+	o := &diffOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "Show drift between manifests and live resources",
+		Long: "Compare manifests, in the format honeycomb apply reads, with the live resources " +
+			"they match and print the differences. Read-only fields and fields a spec omits " +
+			"are ignored. Resources a manifest would create are shown in full, and with --prune, " +
+			"so are the unmanaged resources apply would delete.\n\n" +
+			"Exits non-zero when any resource has drifted, for drift detection in CI. To compare " +
+			"a single resource with a JSON definition, use the diff command of its resource, " +
+			"such as honeycomb trigger diff.",
+		Example: `  # Check a directory of manifests for drift
+  honeycomb diff -f honeycomb/
+
+  # Also report resources that no manifest matches
+  honeycomb diff -f honeycomb/ --prune --style structural`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := command.ValidateEnum("style", o.style, command.DiffStyles); err != nil {
+				return err
+			}
+			return runDiff(cmd.Context(), opts, o)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.files, "file", "f", nil, "Manifest file or directory (repeatable)")
+	cmd.Flags().BoolVar(&o.prune, "prune", false, "Include resources that no manifest matches")
+	cmd.Flags().StringVar(&o.style, "style", command.DiffUnified, "Diff style: "+command.EnumUsage(command.DiffStyles))
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runDiff(ctx context.Context, opts *options.RootOptions, o *diffOptions) error {
+	var manifests []manifest.Manifest
+	for _, f := range o.files {
+		loaded, err := manifest.Load(f)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, loaded...)
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no manifests found in %s", strings.Join(o.files, ", "))
+	}
+
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	plan, err := manifest.Plan(ctx, client, manifests, manifest.PlanOptions{Prune: o.prune})
+	if err != nil {
+		return err
+	}
+
+	drifted := 0
+	for _, a := range plan {
+		live, err := a.Live()
+		if err != nil {
+			return fmt.Errorf("%s: %w", a, err)
+		}
+
+		var local any
+		if spec := a.Spec(); spec != nil {
+			local = spec
+		}
+		var current any
+		if live != nil {
+			current = live
+		}
+
+		changed, err := command.WriteDiff(opts.IOStreams, o.style, current, local, liveLabel(a), a.Source)
+		if err != nil {
+			return err
+		}
+		if changed {
+			drifted++
+		}
+	}
+
+	if drifted > 0 {
+		return fmt.Errorf("%d resources have drifted from their manifests", drifted)
+	}
+	_, _ = fmt.Fprintln(opts.IOStreams.Err, "No differences.")
+	return nil
+}
+
+func liveLabel(a manifest.Action) string {
+	name := a.Name
+	if a.Dataset != "" {
+		name = a.Dataset + "/" + name
+	}
+	return "live/" + a.Kind + "/" + name
+}
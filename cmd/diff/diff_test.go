@@ -0,0 +1,125 @@
+package diff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/zalando/go-keyring"
+)
+
+func init() {
+	keyring.MockInit()
+}
+
+func setupTest(t *testing.T, handler http.Handler) (*options.RootOptions, *iostreams.TestStreams) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{
+		IOStreams: ts.IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    srv.URL,
+		Format:    "json",
+	}
+
+	if err := config.SetKey("default", config.KeyConfig, "test-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyConfig) })
+
+	return opts, ts
+}
+
+func boardsHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/1/boards" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[
+			{"id": "b1", "name": "Overview", "description": "Service health", "type": "flexible"},
+			{"id": "b2", "name": "Unmanaged", "type": "flexible"}
+		]`))
+	})
+}
+
+func writeManifests(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "boards.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestDiff_NoDrift(t *testing.T) {
+	opts, ts := setupTest(t, boardsHandler(t))
+	dir := writeManifests(t, "kind: Board\nspec: {name: Overview, description: Service health}\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", dir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if ts.OutBuf.Len() != 0 {
+		t.Errorf("output = %q, want empty", ts.OutBuf.String())
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "No differences.") {
+		t.Errorf("stderr = %q, want no differences", ts.ErrBuf.String())
+	}
+}
+
+func TestDiff_Drift(t *testing.T) {
+	opts, ts := setupTest(t, boardsHandler(t))
+	dir := writeManifests(t, `kind: Board
+spec: {name: Overview, description: Latency and errors}
+---
+kind: Board
+spec: {name: New}
+`)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", dir, "--style", "structural"})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "2 resources have drifted from their manifests" {
+		t.Fatalf("err = %v, want 2 drifted", err)
+	}
+
+	source := filepath.Join(dir, "boards.yaml")
+	want := "--- live/Board/Overview\n" +
+		"+++ " + source + "#1\n" +
+		"~ description: \"Service health\" -> \"Latency and errors\"\n" +
+		"--- /dev/null\n" +
+		"+++ " + source + "#2\n" +
+		"+ name: \"New\"\n"
+	if got := ts.OutBuf.String(); got != want {
+		t.Errorf("output =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiff_Prune(t *testing.T) {
+	opts, ts := setupTest(t, boardsHandler(t))
+	dir := writeManifests(t, "kind: Board\nspec: {name: Overview}\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", dir, "--prune"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected drift error")
+	}
+
+	out := ts.OutBuf.String()
+	for _, want := range []string{"--- live/Board/Unmanaged\n", "+++ /dev/null\n", "-  \"name\": \"Unmanaged\",\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
 	"github.com/bendrucker/honeycomb-cli/internal/output"
@@ -13,47 +14,43 @@ import (
 var environmentListTable = output.TableFromTags[environmentItem]()
 
 func NewListCmd(opts *options.RootOptions, team *string) *cobra.Command {
-	return &cobra.Command{
+	var limit command.ListLimit
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List environments",
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			n, err := limit.Max()
+			if err != nil {
+				return err
+			}
 			client, err := opts.ClientFor(team, options.AuthManagement)
 			if err != nil {
 				return err
 			}
-			return runEnvironmentList(cmd.Context(), opts, client, *team)
+			return runEnvironmentList(cmd.Context(), opts, client, *team, n)
 		},
 	}
+
+	limit.AddFlags(cmd)
+
+	return cmd
 }
 
-func runEnvironmentList(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, team string) error {
-	var items []environmentItem
-	params := &api.ListEnvironmentsParams{}
-	var cursor string
-	for {
+func runEnvironmentList(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, team string, limit int) error {
+	pages := api.NewPages(func(ctx context.Context, cursor string, size int) ([]api.Environment, *api.PaginationLinks, error) {
+		params := &api.ListEnvironmentsParams{}
+		params.PageAfter, params.PageSize = api.PageParams(cursor, size)
 		resp, err := client.ListEnvironmentsWithResponse(ctx, team, params)
 		if err != nil {
-			return fmt.Errorf("listing environments: %w", err)
+			return nil, nil, fmt.Errorf("listing environments: %w", err)
 		}
-
 		list, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.ApplicationvndApiJSON200)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
+		return list.Data, list.Links, nil
+	}, limit)
 
-		for _, e := range list.Data {
-			items = append(items, envToItem(e))
-		}
-
-		cursor, err = api.NextPageCursor(list.Links, cursor)
-		if err != nil {
-			return err
-		}
-		if cursor == "" {
-			break
-		}
-		params.PageAfter = &cursor
-	}
-
-	return opts.OutputWriterList().WriteList(items, environmentListTable, "No environments found.")
+	return command.WritePages(ctx, opts.IOStreams, opts.OutputWriterList(), pages, environmentListTable, "No environments found.", envToItem)
 }
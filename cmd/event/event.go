@@ -0,0 +1,33 @@
+package event
+
+import (
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(opts *options.RootOptions) *cobra.Command {
+	var dataset string
+
+	cmd := &cobra.Command{
+		Use:     "event",
+		Short:   "Send events",
+		Aliases: []string{"events"},
+		Example: `  # Send a single event from fields
+  honeycomb event send --dataset my-dataset -f service=api -F duration_ms=42
+
+  # Send NDJSON events from stdin
+  cat events.ndjson | honeycomb event send --dataset my-dataset --input -
+
+  # Backfill a CSV export
+  honeycomb event import --dataset my-dataset export.csv`,
+	}
+
+	cmd.PersistentFlags().StringVar(&dataset, "dataset", "", "Dataset slug (required)")
+	_ = cmd.MarkPersistentFlagRequired("dataset")
+
+	cmd.AddCommand(NewSendCmd(opts, &dataset))
+	cmd.AddCommand(NewImportCmd(opts, &dataset))
+
+	return command.Group(cmd)
+}
@@ -0,0 +1,537 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultImportBatchSize  = 500
+	defaultImportBatchBytes = 4 << 20
+	defaultConcurrency      = 4
+	defaultMaxRetries       = 5
+)
+
+// Retry and follow timing. Variables rather than constants so tests can run
+// without real delays.
+var (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+	followInterval = 250 * time.Millisecond
+)
+
+type importOptions struct {
+	root    *options.RootOptions
+	dataset string
+
+	inputFormat     string
+	timestampField  string
+	timestampFormat string
+	samplerate      int
+	batchSize       int
+	batchBytes      int
+	concurrency     int
+	maxRetries      int
+	rate            float64
+	follow          bool
+	flushInterval   time.Duration
+}
+
+type importSummary struct {
+	Events   int            `json:"events" detail:"Events"`
+	Accepted int            `json:"accepted" detail:"Accepted"`
+	Rejected int            `json:"rejected" detail:"Rejected"`
+	Batches  int            `json:"batches" detail:"Batches"`
+	Retries  int            `json:"retries" detail:"Retries"`
+	Errors   map[string]int `json:"errors,omitempty"`
+}
+
+func NewImportCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	o := &importOptions{root: opts}
+
+	cmd := &cobra.Command{
+		Use:   "import [file...]",
+		Short: "Import events from NDJSON, CSV, or logfmt files",
+		Long: "Import events from NDJSON, CSV, or logfmt files using an ingest key.\n\n" +
+			"Files are read as a stream and sent in batches by concurrent workers. Batches that " +
+			"fail with HTTP 429 or 5xx, and the events within a batch that the API rejects with " +
+			"429 or 5xx, are retried with exponential backoff. Events larger than --batch-bytes " +
+			"are rejected. With no files, or " +
+			"a file named -, events are read from stdin. The input format is inferred from the " +
+			"file extension (.csv, .log, .logfmt) unless --input-format is set; CSV input uses " +
+			"its first row as the header.\n\n" +
+			"With --follow, files are tailed for new lines until interrupted, and partial " +
+			"batches are flushed every --flush-interval. An interrupt stops reading, and the " +
+			"events already read are sent. A summary of accepted and rejected events is " +
+			"printed at the end.",
+		Example: `  # Backfill NDJSON logs, using the "ts" field as the event time
+  honeycomb event import --dataset incidents --timestamp-field ts logs.ndjson
+
+  # Import a CSV export with a custom timestamp layout
+  honeycomb event import --dataset incidents export.csv \
+    --timestamp-field time --timestamp-format "2006-01-02 15:04:05"
+
+  # Tail a logfmt file, sending at most 1000 events per second
+  honeycomb event import --dataset app --follow --rate 1000 app.log`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := command.ValidateEnum("input-format", o.inputFormat, inputFormats); err != nil {
+				return err
+			}
+			o.dataset = *dataset
+			if len(args) == 0 {
+				args = []string{"-"}
+			}
+			return runImport(cmd.Context(), o, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.inputFormat, "input-format", "", "Input format: "+command.EnumUsage(inputFormats)+" (default: from file extension)")
+	cmd.Flags().StringVar(&o.timestampField, "timestamp-field", "", "Field holding the event time; removed from the event once parsed")
+	cmd.Flags().StringVar(&o.timestampFormat, "timestamp-format", timestampRFC3339, "Timestamp format: rfc3339, unix, unix_ms, unix_us, unix_ns, or a Go time layout")
+	cmd.Flags().IntVar(&o.samplerate, "samplerate", 1, "Sample rate applied to every event")
+	cmd.Flags().IntVar(&o.batchSize, "batch-size", defaultImportBatchSize, "Maximum events per batch request")
+	cmd.Flags().IntVar(&o.batchBytes, "batch-bytes", defaultImportBatchBytes, "Maximum encoded bytes per batch request")
+	cmd.Flags().IntVar(&o.concurrency, "concurrency", defaultConcurrency, "Number of batches sent in parallel")
+	cmd.Flags().IntVar(&o.maxRetries, "max-retries", defaultMaxRetries, "Retries for a batch or events rejected with HTTP 429 or 5xx")
+	cmd.Flags().Float64Var(&o.rate, "rate", 0, "Maximum events per second (0 for unlimited)")
+	cmd.Flags().BoolVar(&o.follow, "follow", false, "Keep reading files as they grow, until interrupted")
+	cmd.Flags().DurationVar(&o.flushInterval, "flush-interval", time.Second, "With --follow, send a partial batch after this long")
+
+	return cmd
+}
+
+func runImport(ctx context.Context, o *importOptions, paths []string) error {
+	if o.batchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+	if o.batchBytes < 1 {
+		return fmt.Errorf("--batch-bytes must be at least 1")
+	}
+	if o.concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if o.samplerate < 1 {
+		return fmt.Errorf("--samplerate must be at least 1")
+	}
+
+	client, err := o.root.ClientFor(nil, options.AuthIngest)
+	if err != nil {
+		return err
+	}
+
+	// Reading stops on interrupt, but sending keeps the original context so
+	// the batches already read are flushed before the summary is printed.
+	readCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	imp := &importer{
+		client:  client,
+		opts:    o,
+		summary: importSummary{Errors: map[string]int{}},
+		limiter: newRateLimiter(o.rate),
+	}
+
+	batches := make(chan []api.BatchEvent)
+	var wg sync.WaitGroup
+	for range o.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				imp.send(ctx, batch)
+			}
+		}()
+	}
+
+	readErr := imp.read(readCtx, paths, batches)
+	close(batches)
+	wg.Wait()
+
+	// An interrupt ends the import early, with a summary of what was sent.
+	if readErr != nil && !errors.Is(readErr, context.Canceled) {
+		return readErr
+	}
+
+	summary := imp.summary
+	fields := output.FieldsFromTags(summary)
+	for _, msg := range sortedKeys(summary.Errors) {
+		fields = append(fields, output.Field{Label: "Error", Value: fmt.Sprintf("%s (%d)", msg, summary.Errors[msg])})
+	}
+	if err := o.root.OutputWriter().WriteFields(summary, fields); err != nil {
+		return err
+	}
+
+	if summary.Rejected > 0 {
+		return fmt.Errorf("%d of %d events rejected", summary.Rejected, summary.Events)
+	}
+	return nil
+}
+
+// importer reads records into batches on one goroutine and tallies the results
+// reported by the sending workers, which share the rate limiter.
+type importer struct {
+	client  *api.ClientWithResponses
+	opts    *importOptions
+	limiter *rateLimiter
+
+	pending      []api.BatchEvent
+	pendingBytes int
+
+	mu      sync.Mutex
+	summary importSummary
+}
+
+func (imp *importer) read(ctx context.Context, paths []string, batches chan<- []api.BatchEvent) error {
+	records := make(chan pendingRecord)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(records)
+		errc <- imp.readFiles(ctx, paths, records)
+	}()
+
+	var tick <-chan time.Time
+	if imp.opts.follow && imp.opts.flushInterval > 0 {
+		ticker := time.NewTicker(imp.opts.flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case rec, ok := <-records:
+			if !ok {
+				imp.flush(batches)
+				return <-errc
+			}
+			imp.add(rec, batches)
+		case <-tick:
+			imp.flush(batches)
+		}
+	}
+}
+
+type pendingRecord struct {
+	source string
+	line   int
+	data   map[string]any
+	err    error
+}
+
+// readFiles reads each file in turn until ctx is done. With --follow, a file
+// is never done until ctx is, so the files are tailed concurrently, and the
+// first error stops them all.
+func (imp *importer) readFiles(ctx context.Context, paths []string, records chan<- pendingRecord) error {
+	if !imp.opts.follow {
+		for _, path := range paths {
+			if err := imp.readFile(ctx, path, records); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		wg.Go(func() {
+			if err := imp.readFile(ctx, path, records); err != nil {
+				cancel(err)
+			}
+		})
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+func (imp *importer) readFile(ctx context.Context, path string, records chan<- pendingRecord) error {
+	var r io.Reader
+	source := path
+	if path == "-" {
+		r = imp.opts.root.IOStreams.In
+		source = "stdin"
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", path, err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	if imp.opts.follow {
+		r = &followReader{ctx: ctx, r: r, interval: followInterval}
+	}
+
+	format := imp.opts.inputFormat
+	if format == "" {
+		format = detectInputFormat(path)
+	}
+
+	return readRecords(r, format, func(line int, data map[string]any, err error) error {
+		select {
+		case records <- pendingRecord{source: source, line: line, data: data, err: err}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+func (imp *importer) add(rec pendingRecord, batches chan<- []api.BatchEvent) {
+	event, size, err := imp.buildEvent(rec)
+	if err != nil {
+		_, _ = fmt.Fprintf(imp.opts.root.IOStreams.Err, "warning: %s:%d: %v\n", rec.source, rec.line, err)
+		imp.recordLocal(localErrorMessage(err))
+		return
+	}
+	if size > imp.opts.batchBytes {
+		_, _ = fmt.Fprintf(imp.opts.root.IOStreams.Err, "warning: %s:%d: event is %d bytes, larger than --batch-bytes\n", rec.source, rec.line, size)
+		imp.recordLocal("event larger than --batch-bytes")
+		return
+	}
+
+	if len(imp.pending) > 0 && imp.pendingBytes+size > imp.opts.batchBytes {
+		imp.flush(batches)
+	}
+
+	imp.pending = append(imp.pending, event)
+	imp.pendingBytes += size
+	if len(imp.pending) >= imp.opts.batchSize {
+		imp.flush(batches)
+	}
+}
+
+func (imp *importer) buildEvent(rec pendingRecord) (api.BatchEvent, int, error) {
+	if rec.err != nil {
+		return api.BatchEvent{}, 0, rec.err
+	}
+
+	event := api.BatchEvent{}
+	if field := imp.opts.timestampField; field != "" {
+		if v, ok := rec.data[field]; ok {
+			ts, err := parseTimestamp(v, imp.opts.timestampFormat)
+			if err != nil {
+				return api.BatchEvent{}, 0, fmt.Errorf("invalid timestamp: %w", err)
+			}
+			formatted := ts.Format(time.RFC3339Nano)
+			event.Time = &formatted
+			delete(rec.data, field)
+		}
+	}
+	if imp.opts.samplerate > 1 {
+		event.Samplerate = &imp.opts.samplerate
+	}
+	data := api.Event(rec.data)
+	event.Data = &data
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return api.BatchEvent{}, 0, fmt.Errorf("encoding event: %w", err)
+	}
+	return event, len(encoded), nil
+}
+
+// localErrorMessage reduces a parse error to the category before its first
+// colon, so the summary groups errors rather than listing one per line.
+func localErrorMessage(err error) string {
+	msg, _, _ := strings.Cut(err.Error(), ":")
+	return msg
+}
+
+func (imp *importer) flush(batches chan<- []api.BatchEvent) {
+	if len(imp.pending) == 0 {
+		return
+	}
+
+	batches <- imp.pending
+	imp.pending = nil
+	imp.pendingBytes = 0
+}
+
+func (imp *importer) send(ctx context.Context, batch []api.BatchEvent) {
+	if err := imp.limiter.wait(ctx, len(batch)); err != nil {
+		imp.recordBatch(len(batch), nil, 0, err)
+		return
+	}
+
+	statuses, retries := retryBatch(ctx, imp.opts.maxRetries, batch, func(events []api.BatchEvent) ([]eventStatus, error) {
+		return postBatch(ctx, imp.client, imp.opts.dataset, events)
+	})
+	imp.recordBatch(len(batch), statuses, retries, nil)
+}
+
+// recordLocal counts an event that was rejected before it could be sent.
+func (imp *importer) recordLocal(msg string) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	imp.summary.Events++
+	imp.summary.Rejected++
+	imp.summary.Errors[msg]++
+}
+
+// recordBatch tallies a batch of n events. A batch that failed outright (err
+// set) rejects every event in it; otherwise each event's status decides.
+func (imp *importer) recordBatch(n int, statuses []eventStatus, retries int, err error) {
+	imp.mu.Lock()
+	defer imp.mu.Unlock()
+
+	s := &imp.summary
+	s.Events += n
+	s.Batches++
+	s.Retries += retries
+
+	if err != nil {
+		s.Rejected += n
+		s.Errors[err.Error()] += n
+		return
+	}
+
+	for _, st := range statuses {
+		if st.accepted() {
+			s.Accepted++
+			continue
+		}
+		s.Rejected++
+		msg := st.Error
+		if msg == "" {
+			msg = fmt.Sprintf("HTTP %d", st.Status)
+		}
+		s.Errors[msg]++
+	}
+}
+
+// retryBatch sends batch, then sends again the events that are still
+// retryable: all of them when the request failed with a retryable error, or
+// those the API throttled or failed one by one. It stops when none are left
+// or after maxRetries retries, and returns the final status of each event and
+// the number of retries. The delay doubles from retryBaseDelay up to
+// retryMaxDelay between attempts.
+func retryBatch(ctx context.Context, maxRetries int, batch []api.BatchEvent, send func([]api.BatchEvent) ([]eventStatus, error)) ([]eventStatus, int) {
+	results := make([]eventStatus, len(batch))
+	pending := make([]int, len(batch))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		events := make([]api.BatchEvent, len(pending))
+		for i, idx := range pending {
+			events[i] = batch[idx]
+		}
+
+		statuses, err := send(events)
+		var retry []int
+		for i, idx := range pending {
+			st := eventStatus{Index: idx}
+			switch {
+			case err != nil:
+				st.Error = err.Error()
+				if isRetryable(err) {
+					retry = append(retry, idx)
+				}
+			case i < len(statuses):
+				st.Status, st.Error = statuses[i].Status, statuses[i].Error
+				if st.retryable() {
+					retry = append(retry, idx)
+				}
+			default:
+				st.Error = "no status returned"
+			}
+			results[idx] = st
+		}
+		if len(retry) == 0 || attempt >= maxRetries {
+			return results, attempt
+		}
+
+		select {
+		case <-ctx.Done():
+			for _, idx := range retry {
+				results[idx] = eventStatus{Index: idx, Error: ctx.Err().Error()}
+			}
+			return results, attempt
+		case <-time.After(delay):
+		}
+
+		pending = retry
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+// isRetryable reports whether a failed batch request should be retried: the
+// API throttled it (429) or failed server-side (5xx).
+func isRetryable(err error) bool {
+	var apiErr *api.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == 429 || apiErr.StatusCode >= 500
+}
+
+// rateLimiter paces batches so the average event rate stays at or below rate
+// events per second. A zero rate disables pacing.
+type rateLimiter struct {
+	rate float64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{rate: rate}
+}
+
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,360 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func init() {
+	retryBaseDelay = time.Millisecond
+	retryMaxDelay = time.Millisecond
+}
+
+// importRecorder accepts every batch and records the events it received. It is
+// safe for the concurrent requests made by import workers.
+type importRecorder struct {
+	mu     sync.Mutex
+	events []map[string]any
+	sizes  []int
+}
+
+func (rec *importRecorder) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/batch/test-dataset" {
+			t.Errorf("path = %q, want /1/batch/test-dataset", r.URL.Path)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var batch []map[string]any
+		if err := json.Unmarshal(body, &batch); err != nil {
+			t.Errorf("unmarshal request: %v", err)
+			return
+		}
+
+		rec.mu.Lock()
+		rec.events = append(rec.events, batch...)
+		rec.sizes = append(rec.sizes, len(batch))
+		rec.mu.Unlock()
+
+		statuses := make([]map[string]any, len(batch))
+		for i := range batch {
+			statuses[i] = map[string]any{"status": 202}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(statuses)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func decodeSummary(t *testing.T, data []byte) importSummary {
+	t.Helper()
+	var summary importSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("unmarshal summary: %v\n%s", err, data)
+	}
+	return summary
+}
+
+func TestImport_NDJSONTimestamp(t *testing.T) {
+	rec := &importRecorder{}
+	opts, ts := setupTest(t, rec.handler(t))
+
+	path := writeFile(t, "events.ndjson",
+		`{"ts":1704164645,"name":"a"}`+"\n"+
+			`{"ts":1704164646,"name":"b"}`+"\n"+
+			`{"ts":1704164647,"name":"c"}`+"\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{
+		"import", "--dataset", "test-dataset",
+		"--timestamp-field", "ts", "--timestamp-format", "unix",
+		"--batch-size", "2", "--concurrency", "1",
+		path,
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.sizes) != 2 || rec.sizes[0] != 2 || rec.sizes[1] != 1 {
+		t.Errorf("batch sizes = %v, want [2 1]", rec.sizes)
+	}
+	first := rec.events[0]
+	if first["time"] != "2024-01-02T03:04:05Z" {
+		t.Errorf("time = %v, want 2024-01-02T03:04:05Z", first["time"])
+	}
+	data := first["data"].(map[string]any)
+	if _, ok := data["ts"]; ok {
+		t.Error("timestamp field should be removed from event data")
+	}
+
+	summary := decodeSummary(t, ts.OutBuf.Bytes())
+	if summary.Events != 3 || summary.Accepted != 3 || summary.Batches != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestImport_CSVFromStdin(t *testing.T) {
+	rec := &importRecorder{}
+	opts, ts := setupTest(t, rec.handler(t))
+	ts.InBuf.WriteString("service,duration_ms\napi,42\nweb,7\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--input-format", "csv"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.events) != 2 {
+		t.Fatalf("got %d events, want 2", len(rec.events))
+	}
+	data := rec.events[0]["data"].(map[string]any)
+	if data["service"] != "api" || data["duration_ms"] != float64(42) {
+		t.Errorf("data = %v", data)
+	}
+}
+
+func TestImport_RetriesThrottled(t *testing.T) {
+	rec := &importRecorder{}
+	var calls atomic.Int32
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"rate limited"}`))
+			return
+		}
+		rec.handler(t)(w, r)
+	}))
+	ts.InBuf.WriteString(`{"a":1}` + "\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	summary := decodeSummary(t, ts.OutBuf.Bytes())
+	if summary.Accepted != 1 || summary.Retries != 2 {
+		t.Errorf("summary = %+v, want 1 accepted after 2 retries", summary)
+	}
+}
+
+func TestImport_RetriesExhausted(t *testing.T) {
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	ts.InBuf.WriteString(`{"a":1}` + "\n" + `{"a":2}` + "\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--max-retries", "1"})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "2 of 2 events rejected" {
+		t.Fatalf("err = %v, want 2 of 2 events rejected", err)
+	}
+
+	summary := decodeSummary(t, ts.OutBuf.Bytes())
+	if summary.Retries != 1 || summary.Rejected != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestImport_LocalErrors(t *testing.T) {
+	rec := &importRecorder{}
+	opts, ts := setupTest(t, rec.handler(t))
+	ts.InBuf.WriteString(`{"a":1}` + "\n" + `not json` + "\n" + `{"ts":"yesterday"}` + "\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--timestamp-field", "ts"})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "2 of 3 events rejected" {
+		t.Fatalf("err = %v, want 2 of 3 events rejected", err)
+	}
+
+	if len(rec.events) != 1 {
+		t.Errorf("sent %d events, want 1", len(rec.events))
+	}
+
+	summary := decodeSummary(t, ts.OutBuf.Bytes())
+	if summary.Errors["invalid JSON"] != 1 || summary.Errors["invalid timestamp"] != 1 {
+		t.Errorf("errors = %v", summary.Errors)
+	}
+
+	stderr := ts.ErrBuf.String()
+	if !strings.Contains(stderr, "warning: stdin:2: invalid JSON") {
+		t.Errorf("stderr = %q, want warning for line 2", stderr)
+	}
+}
+
+func TestImport_BatchBytes(t *testing.T) {
+	rec := &importRecorder{}
+	opts, ts := setupTest(t, rec.handler(t))
+	for range 4 {
+		ts.InBuf.WriteString(`{"message":"` + strings.Repeat("x", 100) + `"}` + "\n")
+	}
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--batch-bytes", "300", "--concurrency", "1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.sizes) != 2 || rec.sizes[0] != 2 || rec.sizes[1] != 2 {
+		t.Errorf("batch sizes = %v, want [2 2]", rec.sizes)
+	}
+}
+
+func TestImport_InvalidInputFormat(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--input-format", "xml"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--input-format") {
+		t.Fatalf("err = %v, want --input-format validation error", err)
+	}
+}
+
+func TestImport_ZeroSamplerate(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--samplerate", "0"})
+	if err := cmd.Execute(); err == nil || err.Error() != "--samplerate must be at least 1" {
+		t.Fatalf("err = %v, want --samplerate validation error", err)
+	}
+}
+
+func TestImport_FollowMultipleFiles(t *testing.T) {
+	followInterval = time.Millisecond
+	t.Cleanup(func() { followInterval = 250 * time.Millisecond })
+
+	a := writeFile(t, "a.ndjson", `{"file":"a"}`+"\n")
+	b := writeFile(t, "b.ndjson", `{"file":"b"}`+"\n")
+
+	imp := &importer{opts: &importOptions{follow: true}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	records := make(chan pendingRecord)
+	errc := make(chan error, 1)
+	go func() { errc <- imp.readFiles(ctx, []string{a, b}, records) }()
+
+	got := map[any]bool{}
+	for len(got) < 2 {
+		select {
+		case rec := <-records:
+			got[rec.data["file"]] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("read %v, want a record from each file", got)
+		}
+	}
+	cancel()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImport_ReadCanceled(t *testing.T) {
+	path := writeFile(t, "events.ndjson", `{"n":1}`+"\n"+`{"n":2}`+"\n")
+
+	imp := &importer{opts: &importOptions{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	records := make(chan pendingRecord)
+	errc := make(chan error, 1)
+	go func() { errc <- imp.readFiles(ctx, []string{path}, records) }()
+
+	<-records
+	cancel()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("reading did not stop when the context was canceled")
+	}
+}
+
+func TestImport_RetriesThrottledEvents(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("unmarshal request: %v", err)
+			return
+		}
+		mu.Lock()
+		first := len(sizes) == 0
+		sizes = append(sizes, len(batch))
+		mu.Unlock()
+
+		statuses := make([]map[string]any, len(batch))
+		for i := range batch {
+			statuses[i] = map[string]any{"status": 202}
+		}
+		if first {
+			statuses[1] = map[string]any{"status": 429, "error": "rate limited"}
+			statuses[2] = map[string]any{"status": 503, "error": "unavailable"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(statuses)
+	}))
+	ts.InBuf.WriteString(`{"a":1}` + "\n" + `{"a":2}` + "\n" + `{"a":3}` + "\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 2 {
+		t.Errorf("batch sizes = %v, want [3 2]", sizes)
+	}
+	summary := decodeSummary(t, ts.OutBuf.Bytes())
+	if summary.Accepted != 3 || summary.Rejected != 0 || summary.Retries != 1 {
+		t.Errorf("summary = %+v, want 3 accepted after 1 retry", summary)
+	}
+}
+
+func TestImport_BatchBytesTooSmall(t *testing.T) {
+	rec := &importRecorder{}
+	opts, ts := setupTest(t, rec.handler(t))
+	ts.InBuf.WriteString(`{"a":1}` + "\n" + `{"message":"` + strings.Repeat("x", 100) + `"}` + "\n")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--batch-bytes", "0"})
+	if err := cmd.Execute(); err == nil || err.Error() != "--batch-bytes must be at least 1" {
+		t.Fatalf("err = %v, want --batch-bytes error", err)
+	}
+
+	cmd = NewCmd(opts)
+	cmd.SetArgs([]string{"import", "--dataset", "test-dataset", "--batch-bytes", "50"})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "1 of 2 events rejected" {
+		t.Fatalf("err = %v, want 1 of 2 events rejected", err)
+	}
+	if len(rec.events) != 1 {
+		t.Errorf("sent %d events, want 1", len(rec.events))
+	}
+	summary := decodeSummary(t, ts.OutBuf.Bytes())
+	if summary.Errors["event larger than --batch-bytes"] != 1 {
+		t.Errorf("errors = %v", summary.Errors)
+	}
+}
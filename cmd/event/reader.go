@@ -0,0 +1,289 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+)
+
+const (
+	inputNDJSON = "ndjson"
+	inputCSV    = "csv"
+	inputLogfmt = "logfmt"
+)
+
+var inputFormats = []string{inputNDJSON, inputCSV, inputLogfmt}
+
+// detectInputFormat infers an input format from a file extension, defaulting
+// to NDJSON for stdin and unrecognized extensions.
+func detectInputFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return inputCSV
+	case ".logfmt", ".log":
+		return inputLogfmt
+	default:
+		return inputNDJSON
+	}
+}
+
+// recordFunc receives each record parsed from an input, along with the 1-based
+// line it started on. A record that fails to parse is passed with a non-nil
+// err so the caller can count it and keep reading; returning an error stops
+// the read.
+type recordFunc func(line int, data map[string]any, err error) error
+
+// readRecords parses r in the given input format, calling fn once per record.
+func readRecords(r io.Reader, format string, fn recordFunc) error {
+	switch format {
+	case inputNDJSON:
+		return readLines(r, fn, decodeObject)
+	case inputLogfmt:
+		return readLines(r, fn, parseLogfmt)
+	case inputCSV:
+		return readCSV(r, fn)
+	default:
+		return fmt.Errorf("unsupported input format: %s", format)
+	}
+}
+
+// readLines calls parse on every non-blank line. It reads with ReadBytes rather
+// than a Scanner so a single oversized line is not a fatal token-length error.
+func readLines(r io.Reader, fn recordFunc, parse func([]byte) (map[string]any, error)) error {
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("reading input: %w", err)
+		}
+
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			data, parseErr := parse(trimmed)
+			if cbErr := fn(n, data, parseErr); cbErr != nil {
+				return cbErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+func decodeObject(line []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+
+	var data map[string]any
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if data == nil {
+		return nil, fmt.Errorf("invalid JSON: expected an object")
+	}
+	return data, nil
+}
+
+// readCSV treats the first row as the header and maps every following row onto
+// it. Empty cells are omitted rather than sent as empty strings.
+func readCSV(r io.Reader, fn recordFunc) error {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+	header = append([]string(nil), header...)
+
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if cbErr := fn(parseErr.StartLine, nil, fmt.Errorf("invalid CSV: %w", parseErr.Err)); cbErr != nil {
+				return cbErr
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading CSV: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+		data := make(map[string]any, len(header))
+		for i, v := range row {
+			if v == "" {
+				continue
+			}
+			data[header[i]] = command.InferValue(v)
+		}
+		if cbErr := fn(line, data, nil); cbErr != nil {
+			return cbErr
+		}
+	}
+}
+
+// parseLogfmt parses a line of space-separated key=value pairs. Values may be
+// double-quoted with Go-style escapes; a bare key with no value is true.
+func parseLogfmt(line []byte) (map[string]any, error) {
+	data := make(map[string]any)
+	s := string(line)
+
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			break
+		}
+
+		end := strings.IndexAny(s, "= \t")
+		if end == 0 {
+			return nil, fmt.Errorf("invalid logfmt: missing key")
+		}
+		if end < 0 {
+			data[s] = true
+			break
+		}
+		key := s[:end]
+		if s[end] != '=' {
+			data[key] = true
+			s = s[end:]
+			continue
+		}
+		s = s[end+1:]
+
+		if strings.HasPrefix(s, `"`) {
+			value, rest, err := unquotePrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid logfmt: key %q: %w", key, err)
+			}
+			data[key] = value
+			s = rest
+			continue
+		}
+
+		end = strings.IndexAny(s, " \t")
+		if end < 0 {
+			end = len(s)
+		}
+		if value := s[:end]; value != "" {
+			data[key] = command.InferValue(value)
+		}
+		s = s[end:]
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("invalid logfmt: no fields")
+	}
+	return data, nil
+}
+
+// unquotePrefix unquotes the double-quoted string at the start of s and
+// returns the remainder after the closing quote.
+func unquotePrefix(s string) (string, string, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", err
+			}
+			return value, s[i+1:], nil
+		}
+	}
+	return "", "", fmt.Errorf("unterminated quoted value")
+}
+
+const (
+	timestampRFC3339 = "rfc3339"
+	timestampUnix    = "unix"
+	timestampUnixMS  = "unix_ms"
+	timestampUnixUS  = "unix_us"
+	timestampUnixNS  = "unix_ns"
+)
+
+// parseTimestamp interprets a timestamp field value. format is one of the
+// named formats (rfc3339, unix, unix_ms, unix_us, unix_ns) or a Go reference
+// time layout such as "2006-01-02 15:04:05".
+func parseTimestamp(v any, format string) (time.Time, error) {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case json.Number:
+		s = val.String()
+	case int64:
+		s = strconv.FormatInt(val, 10)
+	case float64:
+		s = strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp value %v", v)
+	}
+
+	var unit time.Duration
+	switch format {
+	case "", timestampRFC3339:
+		return time.Parse(time.RFC3339Nano, s)
+	case timestampUnix:
+		unit = time.Second
+	case timestampUnixMS:
+		unit = time.Millisecond
+	case timestampUnixUS:
+		unit = time.Microsecond
+	case timestampUnixNS:
+		unit = time.Nanosecond
+	default:
+		return time.Parse(format, s)
+	}
+
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(0, 0).Add(time.Duration(i) * unit).UTC(), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s timestamp %q", format, s)
+	}
+	return time.Unix(0, int64(f*float64(unit))).UTC(), nil
+}
+
+// followReader turns EOF into a wait for more data, like tail -f. Once ctx is
+// done it reports EOF so readers wind down and flush what they have.
+type followReader struct {
+	ctx      context.Context
+	r        io.Reader
+	interval time.Duration
+}
+
+func (f *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := f.r.Read(p)
+		if n > 0 || !errors.Is(err, io.EOF) {
+			return n, err
+		}
+
+		select {
+		case <-f.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(f.interval):
+		}
+	}
+}
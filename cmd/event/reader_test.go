@@ -0,0 +1,222 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type parsedRecord struct {
+	line int
+	data map[string]any
+	err  string
+}
+
+func collect(t *testing.T, input, format string) []parsedRecord {
+	t.Helper()
+	var got []parsedRecord
+	err := readRecords(strings.NewReader(input), format, func(line int, data map[string]any, err error) error {
+		rec := parsedRecord{line: line, data: data}
+		if err != nil {
+			rec.err = err.Error()
+		}
+		got = append(got, rec)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readRecords: %v", err)
+	}
+	return got
+}
+
+func TestReadRecords_NDJSON(t *testing.T) {
+	got := collect(t, "{\"a\":1}\n\n{bad}\n{\"b\":\"x\"}", inputNDJSON)
+	if len(got) != 3 {
+		t.Fatalf("got %d records, want 3", len(got))
+	}
+	if got[0].line != 1 || got[0].data["a"] != json.Number("1") {
+		t.Errorf("record 0 = %+v", got[0])
+	}
+	if got[1].line != 3 || !strings.HasPrefix(got[1].err, "invalid JSON") {
+		t.Errorf("record 1 = %+v, want invalid JSON on line 3", got[1])
+	}
+	if got[2].line != 4 || got[2].data["b"] != "x" {
+		t.Errorf("record 2 = %+v", got[2])
+	}
+}
+
+func TestReadRecords_CSV(t *testing.T) {
+	got := collect(t, "name,count,ok\napi,3,true\nweb,,false\nshort\n", inputCSV)
+	if len(got) != 3 {
+		t.Fatalf("got %d records, want 3", len(got))
+	}
+	want := map[string]any{"name": "api", "count": int64(3), "ok": true}
+	if !reflect.DeepEqual(got[0].data, want) {
+		t.Errorf("record 0 = %v, want %v", got[0].data, want)
+	}
+	if got[0].line != 2 {
+		t.Errorf("record 0 line = %d, want 2", got[0].line)
+	}
+	if _, ok := got[1].data["count"]; ok {
+		t.Errorf("empty cell should be omitted: %v", got[1].data)
+	}
+	if !strings.HasPrefix(got[2].err, "invalid CSV") {
+		t.Errorf("record 2 err = %q, want invalid CSV", got[2].err)
+	}
+}
+
+func TestParseLogfmt(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		line    string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			name: "bare values",
+			line: "level=info status=200 latency=1.5",
+			want: map[string]any{"level": "info", "status": int64(200), "latency": 1.5},
+		},
+		{
+			name: "quoted value with escapes",
+			line: `msg="hello \"world\"" svc=api`,
+			want: map[string]any{"msg": `hello "world"`, "svc": "api"},
+		},
+		{
+			name: "bare key is true",
+			line: "debug level=warn",
+			want: map[string]any{"debug": true, "level": "warn"},
+		},
+		{
+			name: "empty value omitted",
+			line: "a= b=2",
+			want: map[string]any{"b": int64(2)},
+		},
+		{
+			name:    "unterminated quote",
+			line:    `msg="oops`,
+			wantErr: "unterminated",
+		},
+		{
+			name:    "missing key",
+			line:    "=value",
+			wantErr: "missing key",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseLogfmt([]byte(tc.line))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name   string
+		value  any
+		format string
+	}{
+		{name: "rfc3339", value: "2024-01-02T03:04:05Z", format: timestampRFC3339},
+		{name: "default is rfc3339", value: "2024-01-02T03:04:05Z", format: ""},
+		{name: "unix string", value: "1704164645", format: timestampUnix},
+		{name: "unix number", value: json.Number("1704164645"), format: timestampUnix},
+		{name: "unix int64", value: int64(1704164645), format: timestampUnix},
+		{name: "unix_ms", value: json.Number("1704164645000"), format: timestampUnixMS},
+		{name: "unix fractional", value: 1704164645.0, format: timestampUnix},
+		{name: "go layout", value: "2024-01-02 03:04:05", format: "2006-01-02 15:04:05"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseTimestamp(tc.value, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(want) {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+
+	if _, err := parseTimestamp("yesterday", timestampUnix); err == nil {
+		t.Error("expected error for non-numeric unix timestamp")
+	}
+}
+
+func TestDetectInputFormat(t *testing.T) {
+	for path, want := range map[string]string{
+		"-":              inputNDJSON,
+		"events.ndjson":  inputNDJSON,
+		"export.CSV":     inputCSV,
+		"app.log":        inputLogfmt,
+		"app.logfmt":     inputLogfmt,
+		"unknown.events": inputNDJSON,
+	} {
+		if got := detectInputFormat(path); got != want {
+			t.Errorf("detectInputFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestFollowReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	src := &growingReader{}
+	go func() {
+		defer func() { _ = pw.Close() }()
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, _ = io.Copy(pw, &followReader{ctx: ctx, r: src, interval: time.Millisecond})
+	}()
+
+	src.append("first\n")
+	time.Sleep(20 * time.Millisecond)
+	src.append("second\n")
+
+	data, err := io.ReadAll(pr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "first\nsecond\n" {
+		t.Errorf("read %q, want both appended lines", data)
+	}
+}
+
+// growingReader returns EOF whenever it has no unread data, like a file that
+// is still being written.
+type growingReader struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+func (g *growingReader) append(s string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.data = append(g.data, s...)
+}
+
+func (g *growingReader) Read(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, g.data)
+	g.data = g.data[n:]
+	return n, nil
+}
@@ -0,0 +1,271 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/deref"
+	"github.com/bendrucker/honeycomb-cli/internal/fields"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultBatchSize is the number of events sent per createEvents request.
+const defaultBatchSize = 100
+
+type eventStatus struct {
+	Index  int    `json:"index" col:"Index"`
+	Status int    `json:"status" col:"Status"`
+	Error  string `json:"error,omitempty" col:"Error"`
+}
+
+var eventStatusTable = output.TableFromTags[eventStatus]()
+
+// accepted reports whether the batch endpoint accepted the event. Each event
+// carries its own HTTP-style status; anything outside 2xx, or any error
+// message, is a rejection.
+func (s eventStatus) accepted() bool {
+	return s.Error == "" && s.Status >= 200 && s.Status < 300
+}
+
+// retryable reports whether the API throttled the event (429) or failed it
+// server-side (5xx), so that sending it again may succeed.
+func (s eventStatus) retryable() bool {
+	return s.Status == 429 || s.Status >= 500
+}
+
+type sendOptions struct {
+	root    *options.RootOptions
+	dataset string
+
+	fieldFlags []string
+	typedFlags []string
+	input      string
+	timestamp  command.Time
+	samplerate int
+	batchSize  int
+}
+
+func NewSendCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	o := &sendOptions{root: opts}
+
+	cmd := &cobra.Command{
+		Use:   "send",
+		Short: "Send events to a dataset",
+		Long: "Send events to a dataset using an ingest key.\n\n" +
+			"Events are built from --field/--typed-field flags, or read from --input as a JSON " +
+			"object, a JSON array of objects, or newline-delimited JSON. Input events are sent " +
+			"in batches and the status of each event is reported.",
+		Example: `  # Send a single event from fields
+  honeycomb event send --dataset my-dataset -f service=api -F duration_ms=42
+
+  # Send NDJSON events from stdin in batches of 500
+  cat events.ndjson | honeycomb event send --dataset my-dataset --input - --batch-size 500
+
+  # Backdate a sampled event
+  honeycomb event send --dataset my-dataset -f name=checkout \
+    --timestamp 2024-01-01T00:00:00Z --samplerate 10`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			o.dataset = *dataset
+			return runSend(cmd.Context(), o)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.fieldFlags, "field", "f", nil, "String field: key=value (repeatable)")
+	cmd.Flags().StringArrayVarP(&o.typedFlags, "typed-field", "F", nil, "Typed field: bool/number/null/JSON object/array coercion, @file")
+	cmd.Flags().StringVar(&o.input, "input", "", "Read events as JSON or NDJSON from a file (- for stdin)")
+	cmd.Flags().Var(&o.timestamp, "timestamp", "Event time: "+command.TimeFormats+" (defaults to receive time)")
+	cmd.Flags().IntVar(&o.samplerate, "samplerate", 1, "Sample rate applied to every event")
+	cmd.Flags().IntVar(&o.batchSize, "batch-size", defaultBatchSize, "Maximum events per batch request")
+
+	cmd.MarkFlagsMutuallyExclusive("field", "input")
+	cmd.MarkFlagsMutuallyExclusive("typed-field", "input")
+
+	return cmd
+}
+
+func runSend(ctx context.Context, o *sendOptions) error {
+	hasFields := len(o.fieldFlags) > 0 || len(o.typedFlags) > 0
+	if !hasFields && o.input == "" {
+		return fmt.Errorf("either --field/--typed-field or --input is required")
+	}
+	if o.batchSize < 1 {
+		return fmt.Errorf("--batch-size must be at least 1")
+	}
+	if o.samplerate < 1 {
+		return fmt.Errorf("--samplerate must be at least 1")
+	}
+
+	client, err := o.root.ClientFor(nil, options.AuthIngest)
+	if err != nil {
+		return err
+	}
+
+	s := &sender{client: client, opts: o, statuses: []eventStatus{}}
+
+	if hasFields {
+		data, err := fields.Parse(o.fieldFlags, o.typedFlags, o.root.IOStreams.In)
+		if err != nil {
+			return err
+		}
+		if err := s.add(ctx, data); err != nil {
+			return err
+		}
+	} else if err := s.readInput(ctx); err != nil {
+		return err
+	}
+
+	if err := s.flush(ctx); err != nil {
+		return err
+	}
+
+	if err := o.root.OutputWriterList().WriteList(s.statuses, eventStatusTable, "No events sent."); err != nil {
+		return err
+	}
+
+	if s.rejected > 0 {
+		return fmt.Errorf("%d of %d events rejected", s.rejected, len(s.statuses))
+	}
+	return nil
+}
+
+// sender accumulates events into batches and records the per-event status the
+// batch endpoint returns, indexed by each event's position in the input.
+type sender struct {
+	client *api.ClientWithResponses
+	opts   *sendOptions
+
+	pending  []api.BatchEvent
+	statuses []eventStatus
+	rejected int
+}
+
+func (s *sender) readInput(ctx context.Context) error {
+	var r io.Reader
+	if s.opts.input == "-" {
+		r = s.opts.root.IOStreams.In
+	} else {
+		f, err := os.Open(s.opts.input)
+		if err != nil {
+			return fmt.Errorf("opening input file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	return decodeEvents(r, func(data map[string]any) error {
+		return s.add(ctx, data)
+	})
+}
+
+func (s *sender) add(ctx context.Context, data map[string]any) error {
+	ev := api.Event(data)
+	batch := api.BatchEvent{Data: &ev}
+	if !s.opts.timestamp.IsZero() {
+		ts := s.opts.timestamp.UTC().Format(time.RFC3339Nano)
+		batch.Time = &ts
+	}
+	if s.opts.samplerate > 1 {
+		batch.Samplerate = &s.opts.samplerate
+	}
+
+	s.pending = append(s.pending, batch)
+	if len(s.pending) >= s.opts.batchSize {
+		return s.flush(ctx)
+	}
+	return nil
+}
+
+func (s *sender) flush(ctx context.Context) error {
+	if len(s.pending) == 0 {
+		return nil
+	}
+
+	results, err := postBatch(ctx, s.client, s.opts.dataset, s.pending)
+	if err != nil {
+		return err
+	}
+
+	offset := len(s.statuses)
+	for _, status := range results {
+		status.Index += offset
+		if !status.accepted() {
+			s.rejected++
+		}
+		s.statuses = append(s.statuses, status)
+	}
+
+	s.pending = s.pending[:0]
+	return nil
+}
+
+// postBatch sends one batch to the batch endpoint and returns the status of
+// each event, indexed by its position within the batch.
+func postBatch(ctx context.Context, client *api.ClientWithResponses, dataset string, batch []api.BatchEvent) ([]eventStatus, error) {
+	resp, err := client.CreateEventsWithResponse(ctx, dataset, nil, batch)
+	if err != nil {
+		return nil, fmt.Errorf("sending events: %w", err)
+	}
+
+	results, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]eventStatus, len(*results))
+	for i, r := range *results {
+		statuses[i] = eventStatus{
+			Index:  i,
+			Status: int(deref.Val(r.Status)),
+			Error:  deref.String(r.Error),
+		}
+	}
+	return statuses, nil
+}
+
+// decodeEvents reads a stream of JSON values and calls fn once per event. Each
+// value may be a single object or an array of objects, so one decoder covers a
+// lone JSON object, a JSON array, and NDJSON. Numbers are kept as json.Number
+// so large integers survive the round trip unchanged.
+func decodeEvents(r io.Reader, fn func(map[string]any) error) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	for n := 1; ; n++ {
+		var v any
+		err := dec.Decode(&v)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parsing event %d: %w", n, err)
+		}
+
+		switch val := v.(type) {
+		case map[string]any:
+			if err := fn(val); err != nil {
+				return err
+			}
+		case []any:
+			for _, elem := range val {
+				obj, ok := elem.(map[string]any)
+				if !ok {
+					return fmt.Errorf("parsing event %d: array elements must be JSON objects", n)
+				}
+				if err := fn(obj); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("parsing event %d: expected a JSON object or array", n)
+		}
+	}
+}
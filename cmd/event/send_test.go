@@ -0,0 +1,280 @@
+package event
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/zalando/go-keyring"
+)
+
+func init() {
+	keyring.MockInit()
+}
+
+func setupTest(t *testing.T, handler http.Handler) (*options.RootOptions, *iostreams.TestStreams) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{
+		IOStreams: ts.IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    srv.URL,
+		Format:    output.FormatJSON,
+	}
+
+	if err := config.SetKey("default", config.KeyIngest, "ingest-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyIngest) })
+
+	return opts, ts
+}
+
+// acceptAll responds to a batch request with a 202 status per event and
+// records each decoded batch for inspection.
+func acceptAll(t *testing.T, batches *[][]map[string]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %q, want POST", r.Method)
+		}
+		if r.URL.Path != "/1/batch/test-dataset" {
+			t.Errorf("path = %q, want /1/batch/test-dataset", r.URL.Path)
+		}
+		if got := r.Header.Get("X-Honeycomb-Team"); got != "ingest-key" {
+			t.Errorf("X-Honeycomb-Team = %q, want ingest-key", got)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var batch []map[string]any
+		if err := json.Unmarshal(body, &batch); err != nil {
+			t.Fatalf("unmarshal request: %v", err)
+		}
+		*batches = append(*batches, batch)
+
+		statuses := make([]map[string]any, len(batch))
+		for i := range batch {
+			statuses[i] = map[string]any{"status": 202}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(statuses)
+	}
+}
+
+func TestSend_Fields(t *testing.T) {
+	var batches [][]map[string]any
+	opts, ts := setupTest(t, acceptAll(t, &batches))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{
+		"send", "--dataset", "test-dataset",
+		"-f", "service=api",
+		"-F", "duration_ms=42",
+		"--timestamp", "2024-01-01T00:00:00Z",
+		"--samplerate", "10",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 1 || len(batches[0]) != 1 {
+		t.Fatalf("batches = %v, want one batch of one event", batches)
+	}
+	ev := batches[0][0]
+	data, _ := ev["data"].(map[string]any)
+	if data["service"] != "api" {
+		t.Errorf("service = %v, want api", data["service"])
+	}
+	if data["duration_ms"] != float64(42) {
+		t.Errorf("duration_ms = %v, want 42", data["duration_ms"])
+	}
+	if ev["time"] != "2024-01-01T00:00:00Z" {
+		t.Errorf("time = %v, want 2024-01-01T00:00:00Z", ev["time"])
+	}
+	if ev["samplerate"] != float64(10) {
+		t.Errorf("samplerate = %v, want 10", ev["samplerate"])
+	}
+
+	var statuses []eventStatus
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &statuses); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].Status != 202 {
+		t.Errorf("statuses = %+v, want one 202", statuses)
+	}
+}
+
+func TestSend_EpochTimestamp(t *testing.T) {
+	var batches [][]map[string]any
+	opts, _ := setupTest(t, acceptAll(t, &batches))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"send", "--dataset", "test-dataset", "-f", "a=b", "--timestamp", "1704067200"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 1 || len(batches[0]) != 1 {
+		t.Fatalf("batches = %v, want one batch of one event", batches)
+	}
+	if got := batches[0][0]["time"]; got != "2024-01-01T00:00:00Z" {
+		t.Errorf("time = %v, want 2024-01-01T00:00:00Z", got)
+	}
+}
+
+func TestSend_NDJSONBatches(t *testing.T) {
+	var batches [][]map[string]any
+	opts, ts := setupTest(t, acceptAll(t, &batches))
+
+	ts.InBuf.WriteString(`{"n":1}
+{"n":2}
+[{"n":3},{"n":4}]
+{"n":5}
+`)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"send", "--dataset", "test-dataset", "--input", "-", "--batch-size", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(batches) != 3 {
+		t.Fatalf("got %d batches, want 3", len(batches))
+	}
+	if len(batches[2]) != 1 {
+		t.Errorf("final batch has %d events, want 1", len(batches[2]))
+	}
+	if _, ok := batches[0][0]["time"]; ok {
+		t.Error("time should be omitted when --timestamp is unset")
+	}
+	if _, ok := batches[0][0]["samplerate"]; ok {
+		t.Error("samplerate should be omitted when --samplerate is unset")
+	}
+
+	var statuses []eventStatus
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &statuses); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(statuses) != 5 {
+		t.Fatalf("got %d statuses, want 5", len(statuses))
+	}
+	for i, s := range statuses {
+		if s.Index != i {
+			t.Errorf("statuses[%d].Index = %d, want %d", i, s.Index, i)
+		}
+	}
+}
+
+func TestSend_Rejected(t *testing.T) {
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"status":202},{"status":400,"error":"event too large"}]`))
+	}))
+
+	ts.InBuf.WriteString(`[{"a":1},{"a":2}]`)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"send", "--dataset", "test-dataset", "--input", "-"})
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatal("expected error for rejected event")
+	}
+	if err.Error() != "1 of 2 events rejected" {
+		t.Errorf("error = %q, want rejection summary", err.Error())
+	}
+
+	var statuses []eventStatus
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &statuses); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if statuses[1].Error != "event too large" {
+		t.Errorf("statuses[1].Error = %q, want event too large", statuses[1].Error)
+	}
+}
+
+func TestSend_InvalidInput(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		input   string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "no events source",
+			wantErr: "either --field/--typed-field or --input is required",
+		},
+		{
+			name:    "scalar value",
+			input:   `42`,
+			args:    []string{"--input", "-"},
+			wantErr: "parsing event 1: expected a JSON object or array",
+		},
+		{
+			name:    "array of scalars",
+			input:   `[1, 2]`,
+			args:    []string{"--input", "-"},
+			wantErr: "parsing event 1: array elements must be JSON objects",
+		},
+		{
+			name:    "malformed line",
+			input:   "{\"a\":1}\n{oops}\n",
+			args:    []string{"--input", "-"},
+			wantErr: "parsing event 2",
+		},
+		{
+			name:    "zero batch size",
+			args:    []string{"-f", "a=b", "--batch-size", "0"},
+			wantErr: "--batch-size must be at least 1",
+		},
+		{
+			name:    "zero samplerate",
+			args:    []string{"-f", "a=b", "--samplerate", "0"},
+			wantErr: "--samplerate must be at least 1",
+		},
+		{
+			name:    "invalid timestamp",
+			args:    []string{"-f", "a=b", "--timestamp", "yesterday"},
+			wantErr: `invalid argument "yesterday" for "--timestamp"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, ts := setupTest(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				t.Error("API should not be called for invalid input")
+			}))
+			ts.InBuf.WriteString(tc.input)
+
+			cmd := NewCmd(opts)
+			cmd.SetArgs(append([]string{"send", "--dataset", "test-dataset"}, tc.args...))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestSend_NoIngestKey(t *testing.T) {
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{
+		IOStreams: ts.IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    "http://localhost",
+		Format:    output.FormatJSON,
+	}
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"send", "--dataset", "test-dataset", "-f", "a=b"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no ingest key configured") {
+		t.Errorf("error = %v, want missing ingest key", err)
+	}
+}
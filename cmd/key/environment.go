@@ -20,33 +20,29 @@ func resolveEnvironment(ctx context.Context, client *api.ClientWithResponses, te
 		return value, nil
 	}
 
-	params := &api.ListEnvironmentsParams{}
-	var cursor string
-	for {
+	pages := api.NewPages(func(ctx context.Context, cursor string, size int) ([]api.Environment, *api.PaginationLinks, error) {
+		params := &api.ListEnvironmentsParams{}
+		params.PageAfter, params.PageSize = api.PageParams(cursor, size)
 		resp, err := client.ListEnvironmentsWithResponse(ctx, api.TeamSlug(team), params)
 		if err != nil {
-			return "", fmt.Errorf("listing environments: %w", err)
+			return nil, nil, fmt.Errorf("listing environments: %w", err)
 		}
-
 		list, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.ApplicationvndApiJSON200)
 		if err != nil {
-			return "", err
-		}
-
-		for _, e := range list.Data {
-			if e.Attributes.Name == value {
-				return e.Id, nil
-			}
+			return nil, nil, err
 		}
+		return list.Data, list.Links, nil
+	}, 0)
 
-		cursor, err = api.NextPageCursor(list.Links, cursor)
+	for envs, err := range pages.All(ctx) {
 		if err != nil {
 			return "", err
 		}
-		if cursor == "" {
-			break
+		for _, e := range envs {
+			if e.Attributes.Name == value {
+				return e.Id, nil
+			}
 		}
-		params.PageAfter = &cursor
 	}
 
 	return "", fmt.Errorf("no environment found with name %q", value)
@@ -19,6 +19,7 @@ func NewListCmd(opts *options.RootOptions, team *string) *cobra.Command {
 	var (
 		keyType    string
 		legacyType string
+		limit      command.ListLimit
 	)
 
 	cmd := &cobra.Command{
@@ -28,17 +29,24 @@ func NewListCmd(opts *options.RootOptions, team *string) *cobra.Command {
   honeycomb key list
 
   # List only ingest keys
-  honeycomb key list --key-type ingest`,
+  honeycomb key list --key-type ingest
+
+  # List the first 10 keys
+  honeycomb key list --limit 10`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			filterType := keyType
 			if filterType == "" {
 				filterType = legacyType
 			}
+			n, err := limit.Max()
+			if err != nil {
+				return err
+			}
 			client, err := opts.ClientFor(team, options.AuthManagement)
 			if err != nil {
 				return err
 			}
-			return runKeyList(cmd.Context(), opts, client, *team, filterType)
+			return runKeyList(cmd.Context(), opts, client, *team, filterType, n)
 		},
 	}
 
@@ -46,35 +54,34 @@ func NewListCmd(opts *options.RootOptions, team *string) *cobra.Command {
 	cmd.Flags().StringVar(&legacyType, "type", "", "Filter by key type (ingest, configuration)")
 	_ = cmd.Flags().MarkHidden("type")
 	_ = cmd.Flags().MarkDeprecated("type", "use --key-type")
+	limit.AddFlags(cmd)
 
 	return cmd
 }
 
-func runKeyList(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, team, filterType string) error {
+func runKeyList(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, team, filterType string, limit int) error {
 	if err := command.ValidateEnum("key-type", filterType, keyTypes); err != nil {
 		return err
 	}
 
-	var params *api.ListApiKeysParams
-	if filterType != "" {
-		ft := api.ListApiKeysParamsFilterType(filterType)
-		params = &api.ListApiKeysParams{FilterType: &ft}
-	}
+	pages := api.NewPages(func(ctx context.Context, cursor string, size int) ([]api.ApiKeyObject, *api.PaginationLinks, error) {
+		params := &api.ListApiKeysParams{}
+		if filterType != "" {
+			ft := api.ListApiKeysParamsFilterType(filterType)
+			params.FilterType = &ft
+		}
+		params.PageAfter, params.PageSize = api.PageParams(cursor, size)
 
-	resp, err := client.ListApiKeysWithResponse(ctx, api.TeamSlug(team), params)
-	if err != nil {
-		return fmt.Errorf("listing API keys: %w", err)
-	}
-
-	list, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.ApplicationvndApiJSON200)
-	if err != nil {
-		return err
-	}
-
-	items := make([]keyItem, len(list.Data))
-	for i, obj := range list.Data {
-		items[i] = objectToItem(obj)
-	}
+		resp, err := client.ListApiKeysWithResponse(ctx, api.TeamSlug(team), params)
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing API keys: %w", err)
+		}
+		list, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.ApplicationvndApiJSON200)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Data, list.Links, nil
+	}, limit)
 
-	return opts.OutputWriterList().WriteList(items, keyListTable, "No keys found.")
+	return command.WritePages(ctx, opts.IOStreams, opts.OutputWriterList(), pages, keyListTable, "No keys found.", objectToItem)
 }
@@ -0,0 +1,120 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	schemalint "github.com/bendrucker/honeycomb-cli/internal/lint"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	reportText  = "text"
+	reportSARIF = "sarif"
+)
+
+var reportFormats = []string{reportText, reportSARIF}
+
+var diagnosticTable = output.TableFromTags[schemalint.Diagnostic]()
+
+type lintOptions struct {
+	files  []string
+	report string
+}
+
+func NewCmd(opts *options.RootOptions) *cobra.Command {
+	o := &lintOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check manifests against dataset schemas",
+		Long: "Check manifests, in the format honeycomb apply reads, against the datasets and " +
+			"recipients they refer to before creating them. Lint loads each dataset's columns " +
+			"and calculated fields and reports:\n\n" +
+			"  - columns that trigger queries, calculated field expressions, and the queries of " +
+			"board panels reference but the dataset lacks\n" +
+			"  - SLO SLIs that are not calculated fields of the dataset\n" +
+			"  - unknown ops, and ops that do not apply to the column's type\n" +
+			"  - trigger frequencies, time ranges, thresholds, and queries the API rejects\n" +
+			"  - trigger and burn alert recipients that do not exist\n\n" +
+			"Calculated fields and recipients defined by the manifests count as existing. Each " +
+			"diagnostic names the manifest file and the path within it. Exits non-zero when any " +
+			"diagnostic is an error. Diagnostics are written in the --format output format, or " +
+			"--report sarif writes a SARIF log for code scanning tools instead.",
+		Example: `  # Lint a directory of manifests
+  honeycomb lint -f defs/
+
+  # Write diagnostics as JSON
+  honeycomb lint -f defs/ --format json
+
+  # Write a SARIF report for code scanning
+  honeycomb lint -f defs/ --report sarif > lint.sarif`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := command.ValidateEnum("report", o.report, reportFormats); err != nil {
+				return err
+			}
+			return runLint(cmd.Context(), opts, o)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&o.files, "file", "f", nil, "Manifest file or directory (repeatable)")
+	cmd.Flags().StringVar(&o.report, "report", reportText, "Report format, where text uses --format: "+command.EnumUsage(reportFormats))
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runLint(ctx context.Context, opts *options.RootOptions, o *lintOptions) error {
+	var manifests []manifest.Manifest
+	for _, f := range o.files {
+		loaded, err := manifest.Load(f)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, loaded...)
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no manifests found in %s", strings.Join(o.files, ", "))
+	}
+
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	diags, err := schemalint.Lint(ctx, client, manifests)
+	if err != nil {
+		return err
+	}
+
+	ios := opts.IOStreams
+	if o.report == reportSARIF {
+		if err := schemalint.WriteSARIF(ios.Out, diags); err != nil {
+			return err
+		}
+	} else {
+		if diags == nil {
+			diags = []schemalint.Diagnostic{}
+		}
+		empty := fmt.Sprintf("No problems found in %d manifests.", len(manifests))
+		if err := opts.OutputWriterList().WriteList(diags, diagnosticTable, empty); err != nil {
+			return err
+		}
+	}
+
+	errs := schemalint.Errors(diags)
+	if len(diags) > 0 {
+		_, _ = fmt.Fprintf(ios.Err, "%d problems (%d errors, %d warnings) in %d manifests.\n",
+			len(diags), errs, len(diags)-errs, len(manifests))
+	}
+	if errs > 0 {
+		return fmt.Errorf("lint found %d errors", errs)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+	"github.com/zalando/go-keyring"
+)
+
+func init() {
+	keyring.MockInit()
+}
+
+func setupTest(t *testing.T) (*options.RootOptions, *iostreams.TestStreams) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/1/columns/production":
+			_, _ = w.Write([]byte(`[{"key_name": "duration_ms", "type": "float"}]`))
+		case "/1/derived_columns/production":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ts := iostreams.Test(t)
+	opts := &options.RootOptions{
+		IOStreams: ts.IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    srv.URL,
+		Format:    "json",
+	}
+
+	if err := config.SetKey("default", config.KeyConfig, "test-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyConfig) })
+
+	return opts, ts
+}
+
+func writeManifest(t *testing.T, column string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "trigger.yaml")
+	content := `kind: Trigger
+dataset: production
+spec:
+  name: Slow
+  query:
+    calculations: [{op: P99, column: ` + column + `}]
+  threshold: {op: ">", value: 500}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLint_NoProblems(t *testing.T) {
+	opts, ts := setupTest(t)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, "duration_ms")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(ts.OutBuf.String()); got != "[]" {
+		t.Errorf("output = %q, want []", got)
+	}
+}
+
+func TestLint_NoProblems_Table(t *testing.T) {
+	opts, ts := setupTest(t)
+	opts.Format = "table"
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, "duration_ms")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := ts.OutBuf.String(); got != "No problems found in 1 manifests.\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestLint_JSON(t *testing.T) {
+	opts, ts := setupTest(t)
+	path := writeManifest(t, "latency")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", path})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "lint found 1 errors" {
+		t.Fatalf("err = %v, want lint error", err)
+	}
+
+	var diags []map[string]string
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &diags); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, ts.OutBuf.String())
+	}
+	want := map[string]string{
+		"source":   path,
+		"path":     "spec.query.calculations[0].column",
+		"severity": "error",
+		"rule":     "unknown-column",
+		"message":  `column "latency" not found`,
+	}
+	if len(diags) != 1 || !maps.Equal(diags[0], want) {
+		t.Errorf("diagnostics = %v, want [%v]", diags, want)
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "1 problems (1 errors, 0 warnings) in 1 manifests.") {
+		t.Errorf("stderr = %q", ts.ErrBuf.String())
+	}
+}
+
+func TestLint_Table(t *testing.T) {
+	opts, ts := setupTest(t)
+	opts.Format = "table"
+	path := writeManifest(t, "latency")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", path})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+
+	out := ts.OutBuf.String()
+	for _, want := range []string{path, "spec.query.calculations[0].column", "unknown-column", `column "latency" not found`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLint_SARIF(t *testing.T) {
+	opts, ts := setupTest(t)
+	path := writeManifest(t, "latency")
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", path, "--report", "sarif"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &log); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, ts.OutBuf.String())
+	}
+	if len(log.Runs[0].Results) != 1 || log.Runs[0].Results[0].RuleID != "unknown-column" {
+		t.Errorf("results = %+v", log.Runs[0].Results)
+	}
+}
+
+func TestLint_InvalidReport(t *testing.T) {
+	opts, _ := setupTest(t)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"-f", writeManifest(t, "duration_ms"), "--report", "xml"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -18,8 +18,8 @@ func NewCreateCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
 		markerType string
 		message    string
 		url        string
-		startTime  int
-		endTime    int
+		startTime  command.Time
+		endTime    command.Time
 		color      string
 	)
 
@@ -49,20 +49,22 @@ func NewCreateCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
 				return err
 			}
 
-			if !cmd.Flags().Changed("start-time") {
-				startTime = int(time.Now().Unix())
+			start := int(time.Now().Unix())
+			if cmd.Flags().Changed("start-time") {
+				start = startTime.UnixSeconds()
 			}
 
 			body := api.CreateMarkerJSONRequestBody{
 				Type:      &markerType,
 				Message:   &message,
-				StartTime: &startTime,
+				StartTime: &start,
 			}
 			if url != "" {
 				body.Url = &url
 			}
 			if cmd.Flags().Changed("end-time") {
-				body.EndTime = &endTime
+				end := endTime.UnixSeconds()
+				body.EndTime = &end
 			}
 			if color != "" {
 				body.Color = &color
@@ -76,8 +78,8 @@ func NewCreateCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
 	cmd.Flags().StringVar(&markerType, "type", "", "Marker type (e.g., deploy)")
 	cmd.Flags().StringVar(&message, "message", "", "Marker message")
 	cmd.Flags().StringVar(&url, "url", "", "URL associated with the marker")
-	cmd.Flags().IntVar(&startTime, "start-time", 0, "Start time as Unix timestamp (defaults to now)")
-	cmd.Flags().IntVar(&endTime, "end-time", 0, "End time as Unix timestamp")
+	cmd.Flags().Var(&startTime, "start-time", "Start time: "+command.TimeFormats+" (defaults to now)")
+	cmd.Flags().Var(&endTime, "end-time", "End time: "+command.TimeFormats)
 	cmd.Flags().StringVar(&color, "color", "", "Marker color")
 
 	for _, name := range []string{"type", "message", "url", "start-time", "end-time", "color"} {
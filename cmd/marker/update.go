@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
 	"github.com/spf13/cobra"
@@ -14,8 +15,8 @@ func NewUpdateCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
 		markerType string
 		message    string
 		url        string
-		startTime  int
-		endTime    int
+		startTime  command.Time
+		endTime    command.Time
 		color      string
 	)
 
@@ -24,15 +25,15 @@ func NewUpdateCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
 		Short: "Update a marker",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runMarkerUpdate(cmd, opts, *dataset, args[0], markerType, message, url, startTime, endTime, color)
+			return runMarkerUpdate(cmd, opts, *dataset, args[0], markerType, message, url, startTime.UnixSeconds(), endTime.UnixSeconds(), color)
 		},
 	}
 
 	cmd.Flags().StringVar(&markerType, "type", "", "Marker type")
 	cmd.Flags().StringVar(&message, "message", "", "Marker message")
 	cmd.Flags().StringVar(&url, "url", "", "URL associated with the marker")
-	cmd.Flags().IntVar(&startTime, "start-time", 0, "Start time as Unix timestamp")
-	cmd.Flags().IntVar(&endTime, "end-time", 0, "End time as Unix timestamp")
+	cmd.Flags().Var(&startTime, "start-time", "Start time: "+command.TimeFormats)
+	cmd.Flags().Var(&endTime, "end-time", "End time: "+command.TimeFormats)
 	cmd.Flags().StringVar(&color, "color", "", "Marker color")
 
 	return cmd
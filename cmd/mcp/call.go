@@ -91,21 +91,27 @@ func runCall(cmd *cobra.Command, o *callOptions, toolName string) error {
 	}
 
 	if result.IsError {
-		var msgs []string
-		for _, c := range result.Content {
-			if tc, ok := mcp.AsTextContent(c); ok {
-				msgs = append(msgs, tc.Text)
-			}
-		}
-		if len(msgs) > 0 {
-			return fmt.Errorf("tool %q returned error: %s", toolName, strings.Join(msgs, "\n"))
-		}
-		return fmt.Errorf("tool %q returned error", toolName)
+		return toolResultError(toolName, result)
 	}
 
 	return writeCallResult(o, result)
 }
 
+// toolResultError converts a tool result flagged as an error to an error
+// carrying its text content.
+func toolResultError(toolName string, result *mcp.CallToolResult) error {
+	var msgs []string
+	for _, c := range result.Content {
+		if tc, ok := mcp.AsTextContent(c); ok {
+			msgs = append(msgs, tc.Text)
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("tool %q returned error: %s", toolName, strings.Join(msgs, "\n"))
+	}
+	return fmt.Errorf("tool %q returned error", toolName)
+}
+
 func resolveArgs(o *callOptions) (map[string]any, error) {
 	if o.input != "" {
 		return readInputArgs(o.input, o.root.IOStreams.In)
@@ -150,7 +150,10 @@ Authentication:
   For non-interactive use (CI), set HONEYCOMB_MCP_TOKEN (or pass --token) with a
   pre-issued MCP access token to skip the browser flow.
 
-  Run 'honeycomb auth logout' to clear stored MCP tokens along with API keys.`
+  Run 'honeycomb auth logout' to clear stored MCP tokens along with API keys.
+
+  'honeycomb mcp serve' is the exception: it runs a local MCP server whose tools
+  call the Honeycomb API with your config API key, without OAuth.`
 
 // derefToken returns the value behind a token flag pointer, or empty when nil.
 func derefToken(token *string) string {
@@ -241,6 +244,10 @@ func NewCmd(opts *options.RootOptions) *cobra.Command {
 
 	cmd.AddCommand(newToolsCmd(opts, &token, nil))
 	cmd.AddCommand(newCallCmd(opts, &token, nil))
+	cmd.AddCommand(newResourcesCmd(opts, &token, nil))
+	cmd.AddCommand(newPromptsCmd(opts, &token, nil))
+	cmd.AddCommand(newServeCmd(opts))
+	cmd.AddCommand(newShellCmd(opts, &token, nil))
 
 	return command.Group(cmd)
 }
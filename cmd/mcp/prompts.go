@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var promptsTable = output.TableDef{
+	Columns: []output.Column{
+		output.Col("Name", func(p mcp.Prompt) string { return p.Name }),
+		output.Col("Description", func(p mcp.Prompt) string { return p.Description }),
+		output.Col("Arguments", func(p mcp.Prompt) string {
+			names := make([]string, len(p.Arguments))
+			for i, a := range p.Arguments {
+				names[i] = a.Name
+				if a.Required {
+					names[i] += "*"
+				}
+			}
+			return strings.Join(names, ", ")
+		}),
+	},
+}
+
+func newPromptsCmd(opts *options.RootOptions, token *string, factory clientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompts",
+		Short: "List and get MCP prompts",
+	}
+
+	cmd.AddCommand(newPromptsListCmd(opts, token, factory))
+	cmd.AddCommand(newPromptsGetCmd(opts, token, factory))
+
+	return command.Group(cmd)
+}
+
+func newPromptsListCmd(opts *options.RootOptions, token *string, factory clientFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List available MCP prompts",
+		Long:  "List the prompts the MCP server offers. Required arguments are marked with *.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			c, err := connect(ctx, opts, derefToken(token), factory)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = c.Close() }()
+
+			result, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+			if err != nil {
+				return fmt.Errorf("listing prompts: %w", err)
+			}
+			return opts.OutputWriterList().WriteList(result.Prompts, promptsTable, "The server has no prompts.")
+		},
+	}
+}
+
+func newPromptsGetCmd(opts *options.RootOptions, token *string, factory clientFactory) *cobra.Command {
+	var fieldFlags []string
+
+	cmd := &cobra.Command{
+		Use:   "get <name>",
+		Short: "Get an MCP prompt with its arguments filled in",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPromptsGet(cmd.Context(), opts, derefToken(token), factory, args[0], fieldFlags)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&fieldFlags, "field", "f", nil, "Prompt argument: key=value (repeatable)")
+
+	return cmd
+}
+
+type promptResult struct {
+	Description string              `json:"description,omitempty"`
+	Messages    []mcp.PromptMessage `json:"messages"`
+}
+
+func runPromptsGet(ctx context.Context, opts *options.RootOptions, token string, factory clientFactory, name string, fieldFlags []string) error {
+	// Prompt arguments are strings in the protocol, so they are not coerced.
+	args := map[string]string{}
+	for _, f := range fieldFlags {
+		key, val, ok := strings.Cut(f, "=")
+		if !ok {
+			return fmt.Errorf("invalid field %q (must be key=value)", f)
+		}
+		args[key] = val
+	}
+
+	c, err := connect(ctx, opts, token, factory)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	prompt, err := findPrompt(ctx, c, name)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, a := range prompt.Arguments {
+		if _, ok := args[a.Name]; a.Required && !ok {
+			missing = append(missing, a.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("prompt %q requires arguments: %s", name, strings.Join(missing, ", "))
+	}
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := c.GetPrompt(ctx, request)
+	if err != nil {
+		return fmt.Errorf("getting prompt %q: %w", name, err)
+	}
+
+	td := output.DynamicTableDef{
+		Headers: []string{"Index", "Role", "Type", "Content"},
+		Rows:    make([][]string, len(result.Messages)),
+	}
+	for i, m := range result.Messages {
+		td.Rows[i] = []string{strconv.Itoa(i), string(m.Role), contentType(m.Content), contentSummary(m.Content)}
+	}
+
+	return opts.OutputWriter().WriteDynamic(promptResult{Description: result.Description, Messages: result.Messages}, td)
+}
+
+// findPrompt returns the prompt with the given name, so an unknown name and
+// missing required arguments fail before the prompt is requested.
+func findPrompt(ctx context.Context, c *mcpclient.Client, name string) (mcp.Prompt, error) {
+	result, err := c.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		return mcp.Prompt{}, fmt.Errorf("listing prompts: %w", err)
+	}
+	for _, p := range result.Prompts {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return mcp.Prompt{}, fmt.Errorf("unknown prompt %q", name)
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func addInvestigatePrompt(srv *server.MCPServer) {
+	srv.AddPrompt(
+		mcp.NewPrompt("investigate",
+			mcp.WithPromptDescription("Investigate a trigger"),
+			mcp.WithArgument("trigger", mcp.RequiredArgument()),
+			mcp.WithArgument("window"),
+		),
+		func(_ context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			text := "Investigate " + req.Params.Arguments["trigger"] + " over " + req.Params.Arguments["window"]
+			return mcp.NewGetPromptResult("Investigation", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+			}), nil
+		},
+	)
+}
+
+func TestPromptsList(t *testing.T) {
+	srv, opts, ts := setupMCPTest(t)
+	addInvestigatePrompt(srv)
+	opts.Format = "table"
+
+	cmd := newPromptsCmd(opts, nil, testFactory(srv))
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if out := ts.OutBuf.String(); !strings.Contains(out, "investigate") || !strings.Contains(out, "trigger*, window") {
+		t.Errorf("output = %s", out)
+	}
+}
+
+func TestPromptsGet(t *testing.T) {
+	srv, opts, ts := setupMCPTest(t)
+	addInvestigatePrompt(srv)
+
+	cmd := newPromptsCmd(opts, nil, testFactory(srv))
+	cmd.SetArgs([]string{"get", "investigate", "-f", "trigger=High latency", "-f", "window=1h"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		Description string `json:"description"`
+		Messages    []struct {
+			Role    string `json:"role"`
+			Content struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, ts.OutBuf.String())
+	}
+	if result.Description != "Investigation" || len(result.Messages) != 1 {
+		t.Fatalf("result = %+v", result)
+	}
+	if m := result.Messages[0]; m.Role != "user" || m.Content.Text != "Investigate High latency over 1h" {
+		t.Errorf("message = %+v", m)
+	}
+}
+
+func TestPromptsGet_Invalid(t *testing.T) {
+	srv, opts, _ := setupMCPTest(t)
+	addInvestigatePrompt(srv)
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"get", "investigate"}, `prompt "investigate" requires arguments: trigger`},
+		{[]string{"get", "missing"}, `unknown prompt "missing"`},
+		{[]string{"get", "investigate", "-f", "trigger"}, `invalid field "trigger"`},
+	} {
+		cmd := newPromptsCmd(opts, nil, testFactory(srv))
+		cmd.SetArgs(tc.args)
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: err = %v, want containing %q", tc.args, err, tc.want)
+		}
+	}
+}
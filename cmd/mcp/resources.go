@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var resourcesTable = output.TableDef{
+	Columns: []output.Column{
+		output.Col("URI", func(r mcp.Resource) string { return r.URI }),
+		output.Col("Name", func(r mcp.Resource) string { return r.Name }),
+		output.Col("MIME Type", func(r mcp.Resource) string { return r.MIMEType }),
+	},
+}
+
+func newResourcesCmd(opts *options.RootOptions, token *string, factory clientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resources",
+		Short: "List and read MCP resources",
+	}
+
+	cmd.AddCommand(newResourcesListCmd(opts, token, factory))
+	cmd.AddCommand(newResourcesReadCmd(opts, token, factory))
+
+	return command.Group(cmd)
+}
+
+func newResourcesListCmd(opts *options.RootOptions, token *string, factory clientFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List available MCP resources",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			c, err := connect(ctx, opts, derefToken(token), factory)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = c.Close() }()
+
+			result, err := c.ListResources(ctx, mcp.ListResourcesRequest{})
+			if err != nil {
+				return fmt.Errorf("listing resources: %w", err)
+			}
+			return opts.OutputWriterList().WriteList(result.Resources, resourcesTable, "The server has no resources.")
+		},
+	}
+}
+
+func newResourcesReadCmd(opts *options.RootOptions, token *string, factory clientFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "read <uri>",
+		Short: "Read an MCP resource",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runResourcesRead(cmd.Context(), opts, derefToken(token), factory, args[0])
+		},
+	}
+}
+
+type resourceResult struct {
+	Contents []mcp.ResourceContents `json:"contents"`
+}
+
+func runResourcesRead(ctx context.Context, opts *options.RootOptions, token string, factory clientFactory, uri string) error {
+	c, err := connect(ctx, opts, token, factory)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	result, err := c.ReadResource(ctx, request)
+	if err != nil {
+		return fmt.Errorf("reading resource %q: %w", uri, err)
+	}
+
+	td := output.DynamicTableDef{
+		Headers: []string{"Index", "URI", "MIME Type", "Content"},
+		Rows:    make([][]string, len(result.Contents)),
+	}
+	for i, rc := range result.Contents {
+		switch v := rc.(type) {
+		case mcp.TextResourceContents:
+			td.Rows[i] = []string{strconv.Itoa(i), v.URI, v.MIMEType, v.Text}
+		case mcp.BlobResourceContents:
+			td.Rows[i] = []string{strconv.Itoa(i), v.URI, v.MIMEType, fmt.Sprintf("[blob %d bytes base64]", len(v.Blob))}
+		default:
+			td.Rows[i] = []string{strconv.Itoa(i), "", "", fmt.Sprintf("[%T]", rc)}
+		}
+	}
+
+	return opts.OutputWriter().WriteDynamic(resourceResult{Contents: result.Contents}, td)
+}
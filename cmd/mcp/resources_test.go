@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResourcesList(t *testing.T) {
+	srv, opts, ts := setupMCPTest(t)
+	srv.AddResource(mcp.NewResource("honeycomb://docs/query", "Query reference", mcp.WithMIMEType("text/markdown")), nil)
+
+	cmd := newResourcesCmd(opts, nil, testFactory(srv))
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var resources []mcp.Resource
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &resources); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, ts.OutBuf.String())
+	}
+	if len(resources) != 1 || resources[0].URI != "honeycomb://docs/query" || resources[0].MIMEType != "text/markdown" {
+		t.Errorf("resources = %+v", resources)
+	}
+}
+
+func TestResourcesRead(t *testing.T) {
+	srv, opts, ts := setupMCPTest(t)
+	srv.AddResource(mcp.NewResource("honeycomb://docs/query", "Query reference"),
+		func(_ context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "text/markdown", Text: "# Queries"},
+			}, nil
+		},
+	)
+
+	cmd := newResourcesCmd(opts, nil, testFactory(srv))
+	cmd.SetArgs([]string{"read", "honeycomb://docs/query"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result struct {
+		Contents []struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, ts.OutBuf.String())
+	}
+	if len(result.Contents) != 1 || result.Contents[0].Text != "# Queries" {
+		t.Errorf("contents = %+v", result.Contents)
+	}
+
+	ts.OutBuf.Reset()
+	opts.Format = "table"
+	cmd = newResourcesCmd(opts, nil, testFactory(srv))
+	cmd.SetArgs([]string{"read", "honeycomb://docs/query"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ts.OutBuf.String(), "# Queries") {
+		t.Errorf("table output = %s", ts.OutBuf.String())
+	}
+
+	cmd = newResourcesCmd(opts, nil, testFactory(srv))
+	cmd.SetArgs([]string{"read", "honeycomb://missing"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "honeycomb://missing") {
+		t.Errorf("err = %v, want an error naming the URI", err)
+	}
+}
+
+func TestResources_Token(t *testing.T) {
+	srv, opts, _ := setupMCPTest(t)
+	srv.AddResource(mcp.NewResource("honeycomb://docs/query", "Query reference"), nil)
+	t.Setenv(tokenEnvVar, "env-token")
+
+	var got string
+	factory := func(ctx context.Context, conn connectOptions) (*mcpclient.Client, error) {
+		got = conn.token
+		return testFactory(srv)(ctx, conn)
+	}
+
+	cmd := newResourcesCmd(opts, nil, factory)
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got != "env-token" {
+		t.Errorf("token = %q, want the %s value", got, tokenEnvVar)
+	}
+}
@@ -55,6 +55,15 @@ func coerceArgs(args map[string]any, schema mcp.ToolInputSchema) error {
 	return nil
 }
 
+// secretProperty reports whether a JSON Schema property holds a secret, marked
+// writeOnly or with the password format.
+func secretProperty(prop map[string]any) bool {
+	if writeOnly, _ := prop["writeOnly"].(bool); writeOnly {
+		return true
+	}
+	return prop["format"] == "password"
+}
+
 // schemaType returns the JSON Schema type for a property. A plain string type
 // is returned as-is; a type expressed as a list (e.g. ["string", "null"] for a
 // nullable property) yields the first non-null entry. Anything else (a type
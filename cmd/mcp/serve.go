@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/spf13/cobra"
+)
+
+// serveInstructions tells agents how the local server's tools behave.
+const serveInstructions = "Tools run honeycomb CLI commands against the Honeycomb API and return their JSON output. " +
+	"Create tools take a definition object in the shape of the command's --file JSON."
+
+type serveOptions struct {
+	readOnly    bool
+	httpAddr    string
+	allowRemote bool
+}
+
+func newServeCmd(opts *options.RootOptions) *cobra.Command {
+	var o serveOptions
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a local MCP server exposing CLI commands as tools",
+		Long: "Run a local MCP server whose tools are CLI commands: list, get, and create for " +
+			"boards, triggers, and SLOs, list and create for markers, and query run. Each tool's " +
+			"input schema is derived from the command's flags and arguments.\n\n" +
+			"Unlike the other mcp commands, serve does not use OAuth or the Honeycomb MCP server. " +
+			"Tools call the Honeycomb API with the configuration API key of the active profile, " +
+			"as the commands themselves do.\n\n" +
+			"The server speaks MCP over stdin and stdout, or over streamable HTTP with --http. " +
+			"The HTTP server has no authentication, so it only listens on a loopback address " +
+			"and rejects requests from web pages on other origins, unless --allow-remote is set. " +
+			"With --read-only, only tools that do not change anything are served.",
+		Example: `  # Serve over stdio, as configured in an MCP client
+  honeycomb mcp serve
+
+  # Serve only read-only tools for another profile
+  honeycomb mcp serve --read-only --profile production
+
+  # Serve over streamable HTTP at http://localhost:8080/mcp
+  honeycomb mcp serve --http localhost:8080`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runServe(cmd.Context(), opts, o, cmd.Root().Version)
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.readOnly, "read-only", false, "Only serve tools that do not change anything")
+	cmd.Flags().StringVar(&o.httpAddr, "http", "", "Serve streamable HTTP on this address instead of stdio")
+	cmd.Flags().BoolVar(&o.allowRemote, "allow-remote", false, "Allow --http to listen on a non-loopback address, serving anyone who can reach it")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, opts *options.RootOptions, o serveOptions, version string) error {
+	warnExperimental(opts)
+
+	if o.httpAddr != "" {
+		if err := checkListenAddr(o.httpAddr, o.allowRemote); err != nil {
+			return err
+		}
+	}
+
+	// Fail before serving when the profile has no API key, rather than on
+	// every tool call.
+	if _, err := opts.ClientFor(nil, options.AuthConfig); err != nil {
+		return err
+	}
+
+	srv, err := newLocalServer(opts, o.readOnly, version)
+	if err != nil {
+		return err
+	}
+
+	if o.httpAddr == "" {
+		return server.NewStdioServer(srv).Listen(ctx, opts.IOStreams.In, opts.IOStreams.Out)
+	}
+	hsrv := &http.Server{Addr: o.httpAddr}
+	hs := server.NewStreamableHTTPServer(srv, server.WithStreamableHTTPServer(hsrv))
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", localOnly(hs, o.allowRemote))
+	hsrv.Handler = mux
+	return serveHTTP(ctx, opts, hs, o.httpAddr)
+}
+
+// checkListenAddr rejects an address other than a loopback one, which would
+// let anyone who can reach it call tools with the profile's API key.
+func checkListenAddr(addr string, allowRemote bool) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid --http address %q: %w", addr, err)
+	}
+	if !allowRemote && !isLoopback(host) {
+		return fmt.Errorf("--http %s is not a loopback address; use --allow-remote to serve tools to other hosts", addr)
+	}
+	return nil
+}
+
+// localOnly rejects requests that could come from a web page the user is
+// visiting rather than an MCP client, as the MCP specification recommends
+// for local servers: a Host header other than loopback, which DNS rebinding
+// produces, and an Origin other than loopback or the server itself.
+func localOnly(next http.Handler, allowRemote bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !allowRemote && !isLoopback(host) {
+			http.Error(w, fmt.Sprintf("Forbidden: invalid Host header %q", r.Host), http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || (!isLoopback(u.Hostname()) && u.Host != r.Host) {
+				http.Error(w, fmt.Sprintf("Forbidden: invalid Origin header %q", origin), http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// newLocalServer returns an MCP server with a tool for each local command,
+// leaving out the tools that change resources when readOnly is set.
+func newLocalServer(opts *options.RootOptions, readOnly bool, version string) (*server.MCPServer, error) {
+	if version == "" {
+		version = "dev"
+	}
+	srv := server.NewMCPServer(clientName, version,
+		server.WithToolCapabilities(false),
+		server.WithInstructions(serveInstructions),
+	)
+
+	for _, t := range localTools {
+		if readOnly && !t.readOnly {
+			continue
+		}
+		tc, err := t.describe(opts)
+		if err != nil {
+			return nil, err
+		}
+		srv.AddTool(tc.tool, t.handler(opts, tc))
+	}
+	return srv, nil
+}
+
+func serveHTTP(ctx context.Context, opts *options.RootOptions, hs *server.StreamableHTTPServer, addr string) error {
+	errc := make(chan error, 1)
+	go func() { errc <- hs.Start(addr) }()
+	_, _ = fmt.Fprintf(opts.IOStreams.Err, "Serving MCP at http://%s/mcp. Press Ctrl+C to stop.\n", addr)
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := hs.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+)
+
+func setupServeTest(t *testing.T, handler http.Handler) *options.RootOptions {
+	t.Helper()
+	api := httptest.NewServer(handler)
+	t.Cleanup(api.Close)
+
+	if err := config.SetKey("default", config.KeyConfig, "test-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyConfig) })
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	return &options.RootOptions{
+		IOStreams: iostreams.Test(t).IOStreams,
+		Config:    &config.Config{},
+		APIUrl:    api.URL,
+	}
+}
+
+func listLocalTools(t *testing.T, opts *options.RootOptions, readOnly bool) map[string]mcp.Tool {
+	t.Helper()
+	srv, err := newLocalServer(opts, readOnly, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := testFactory(srv)(t.Context(), connectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+
+	result, err := c.ListTools(t.Context(), mcp.ListToolsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tools := map[string]mcp.Tool{}
+	for _, tool := range result.Tools {
+		tools[tool.Name] = tool
+	}
+	return tools
+}
+
+func TestServe_Tools(t *testing.T) {
+	opts := setupServeTest(t, http.NotFoundHandler())
+
+	tools := listLocalTools(t, opts, false)
+	if len(tools) != len(localTools) {
+		t.Errorf("tools = %d, want %d", len(tools), len(localTools))
+	}
+
+	get := tools["get_trigger"]
+	if !slices.Equal(get.InputSchema.Required, []string{"dataset", "trigger_id"}) {
+		t.Errorf("get_trigger required = %v", get.InputSchema.Required)
+	}
+	if get.Annotations.ReadOnlyHint == nil || !*get.Annotations.ReadOnlyHint {
+		t.Error("get_trigger is not annotated read-only")
+	}
+
+	run := tools["run_query"].InputSchema.Properties
+	for name, want := range map[string]string{"calc": "array", "limit": "integer", "start_time": "string", "definition": "object"} {
+		prop, _ := run[name].(map[string]any)
+		if prop["type"] != want {
+			t.Errorf("run_query %s = %v, want type %s", name, run[name], want)
+		}
+	}
+	if _, ok := run["watch"]; ok {
+		t.Error("run_query exposes a skipped flag")
+	}
+
+	readOnly := listLocalTools(t, opts, true)
+	for name := range readOnly {
+		if strings.HasPrefix(name, "create_") {
+			t.Errorf("read-only server has %s", name)
+		}
+	}
+	if _, ok := readOnly["list_boards"]; !ok {
+		t.Error("read-only server is missing list_boards")
+	}
+}
+
+func TestServe_Call(t *testing.T) {
+	var marker map[string]any
+	opts := setupServeTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Honeycomb-Team"); got != "test-key" {
+			t.Errorf("X-Honeycomb-Team = %q, want the config key", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/1/triggers/production/t-1":
+			_, _ = w.Write([]byte(`{"id": "t-1", "name": "High latency"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/1/triggers/production/--dataset=other":
+			_, _ = w.Write([]byte(`{"id": "--dataset=other", "name": "Flag-like"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/1/triggers/production/--help":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "trigger not found"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/1/markers/production":
+			body, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(body, &marker)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "m-1", "message": "v2"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	srv, err := newLocalServer(opts, false, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := testFactory(srv)(t.Context(), connectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		result, err := c.CallTool(t.Context(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	result := call("get_trigger", map[string]any{"dataset": "production", "trigger_id": "t-1"})
+	if result.IsError {
+		t.Fatalf("get_trigger failed: %v", result.Content)
+	}
+	var trigger map[string]any
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &trigger); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if trigger["name"] != "High latency" {
+		t.Errorf("trigger = %v", trigger)
+	}
+
+	result = call("create_marker", map[string]any{
+		"dataset":    "production",
+		"definition": map[string]any{"message": "v2", "type": "deploy"},
+	})
+	if result.IsError {
+		t.Fatalf("create_marker failed: %v", result.Content)
+	}
+	if marker["message"] != "v2" || marker["type"] != "deploy" {
+		t.Errorf("marker = %v", marker)
+	}
+
+	// Positional values that look like flags are passed through as values.
+	result = call("get_trigger", map[string]any{"dataset": "production", "trigger_id": "--dataset=other"})
+	if result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "Flag-like") {
+		t.Errorf("get_trigger with a flag-like ID = %+v, want the trigger", result)
+	}
+	result = call("get_trigger", map[string]any{"dataset": "production", "trigger_id": "--help"})
+	if text := result.Content[0].(mcp.TextContent).Text; !result.IsError || strings.Contains(text, "Usage:") {
+		t.Errorf("get_trigger with --help as the ID = %s, want a not found error", text)
+	}
+
+	result = call("get_trigger", map[string]any{"dataset": "production", "trigger_id": "t-1", "bogus": true})
+	if !result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, `unknown argument "bogus"`) {
+		t.Errorf("result = %+v, want an unknown argument error", result)
+	}
+}
+
+func TestServe_RequiresKey(t *testing.T) {
+	opts := &options.RootOptions{IOStreams: iostreams.Test(t).IOStreams, Config: &config.Config{}}
+	cmd := newServeCmd(opts)
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error without a config key")
+	}
+}
+
+func TestServe_RemoteAddress(t *testing.T) {
+	opts := setupServeTest(t, http.NotFoundHandler())
+	for _, addr := range []string{":8080", "0.0.0.0:8080", "192.168.1.5:8080"} {
+		cmd := newServeCmd(opts)
+		cmd.SetArgs([]string{"--http", addr})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--allow-remote") {
+			t.Errorf("%s: err = %v, want a loopback error", addr, err)
+		}
+	}
+	for _, addr := range []string{"localhost:8080", "127.0.0.1:8080", "[::1]:8080"} {
+		if err := checkListenAddr(addr, false); err != nil {
+			t.Errorf("%s: %v", addr, err)
+		}
+	}
+	if err := checkListenAddr(":8080", true); err != nil {
+		t.Errorf("--allow-remote: %v", err)
+	}
+}
+
+func TestLocalOnly(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		host        string
+		origin      string
+		allowRemote bool
+		want        int
+	}{
+		{name: "no origin", host: "localhost:8080", want: http.StatusOK},
+		{name: "loopback origin", host: "127.0.0.1:8080", origin: "http://localhost:3000", want: http.StatusOK},
+		{name: "foreign origin", host: "localhost:8080", origin: "https://evil.example", want: http.StatusForbidden},
+		{name: "dns rebinding", host: "evil.example:8080", origin: "http://evil.example:8080", want: http.StatusForbidden},
+		{name: "remote same origin", host: "mcp.internal:8080", origin: "http://mcp.internal:8080", allowRemote: true, want: http.StatusOK},
+		{name: "remote foreign origin", host: "mcp.internal:8080", origin: "https://evil.example", allowRemote: true, want: http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := localOnly(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), tc.allowRemote)
+			req := httptest.NewRequest(http.MethodPost, "http://"+tc.host+"/mcp", nil)
+			if tc.origin != "" {
+				req.Header.Set("Origin", tc.origin)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d", rec.Code, tc.want)
+			}
+		})
+	}
+}
+
+func TestServe_HTTP(t *testing.T) {
+	opts := setupServeTest(t, http.NotFoundHandler())
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+	go func() { done <- runServe(ctx, opts, serveOptions{httpAddr: addr}, "test") }()
+
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`
+	post := func(origin string) int {
+		t.Helper()
+		for range 100 {
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, "http://"+addr+"/mcp", strings.NewReader(initialize))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "application/json, text/event-stream")
+			if origin != "" {
+				req.Header.Set("Origin", origin)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			_ = resp.Body.Close()
+			return resp.StatusCode
+		}
+		t.Fatal("server did not start")
+		return 0
+	}
+
+	if code := post(""); code != http.StatusOK {
+		t.Errorf("status = %d, want 200", code)
+	}
+	if code := post("https://evil.example"); code != http.StatusForbidden {
+		t.Errorf("foreign origin status = %d, want 403", code)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("serve: %v", err)
+	}
+}
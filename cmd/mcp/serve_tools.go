@@ -0,0 +1,262 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/board"
+	"github.com/bendrucker/honeycomb-cli/cmd/marker"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/cmd/query"
+	"github.com/bendrucker/honeycomb-cli/cmd/slo"
+	"github.com/bendrucker/honeycomb-cli/cmd/trigger"
+	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
+)
+
+// localTool exposes a CLI command as an MCP tool. Its input schema is derived
+// from the command's flags and positional arguments, and a call runs the
+// command in process with JSON output.
+type localTool struct {
+	name string
+	// group builds the command group the tool's command belongs to.
+	group func(*options.RootOptions) *cobra.Command
+	// path is the command's path within the group, such as {"list"}.
+	path []string
+	// readOnly tools are the only ones served in read-only mode.
+	readOnly bool
+}
+
+var localTools = []localTool{
+	{name: "list_boards", group: board.NewCmd, path: []string{"list"}, readOnly: true},
+	{name: "get_board", group: board.NewCmd, path: []string{"get"}, readOnly: true},
+	{name: "create_board", group: board.NewCmd, path: []string{"create"}},
+	{name: "list_triggers", group: trigger.NewCmd, path: []string{"list"}, readOnly: true},
+	{name: "get_trigger", group: trigger.NewCmd, path: []string{"get"}, readOnly: true},
+	{name: "create_trigger", group: trigger.NewCmd, path: []string{"create"}},
+	{name: "list_slos", group: slo.NewCmd, path: []string{"list"}, readOnly: true},
+	{name: "get_slo", group: slo.NewCmd, path: []string{"get"}, readOnly: true},
+	{name: "create_slo", group: slo.NewCmd, path: []string{"create"}},
+	{name: "run_query", group: query.NewCmd, path: []string{"run"}, readOnly: true},
+	{name: "list_markers", group: marker.NewCmd, path: []string{"list"}, readOnly: true},
+	{name: "create_marker", group: marker.NewCmd, path: []string{"create"}},
+}
+
+// skippedFlags are flags that make no sense for a tool call: they block,
+// prompt, or only change how a terminal renders the output.
+var skippedFlags = []string{"help", "watch", "from-cache"}
+
+// definitionProperty replaces a command's --file flag: its value is sent to
+// the command as the JSON file on stdin.
+const definitionProperty = "definition"
+
+// toolCommand is a tool's command with the mapping from its input schema
+// properties back to command-line arguments.
+type toolCommand struct {
+	tool       mcp.Tool
+	flags      map[string]*pflag.Flag
+	positional []string
+}
+
+// describe builds the tool's command and derives its MCP definition.
+func (t localTool) describe(opts *options.RootOptions) (toolCommand, error) {
+	leaf, _, err := t.group(opts).Find(t.path)
+	if err != nil {
+		return toolCommand{}, fmt.Errorf("tool %s: %w", t.name, err)
+	}
+
+	tc := toolCommand{flags: map[string]*pflag.Flag{}}
+	schema := mcp.ToolInputSchema{Type: "object", Properties: map[string]any{}}
+
+	for _, arg := range strings.Fields(leaf.Use)[1:] {
+		name, required := positionalName(arg)
+		if name == "" {
+			continue
+		}
+		tc.positional = append(tc.positional, name)
+		schema.Properties[name] = map[string]any{"type": "string", "description": "The " + strings.ReplaceAll(name, "_", " ")}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	add := func(f *pflag.Flag) {
+		if f.Hidden || slices.Contains(skippedFlags, f.Name) {
+			return
+		}
+		if f.Name == "file" {
+			tc.flags[definitionProperty] = f
+			schema.Properties[definitionProperty] = map[string]any{
+				"type":        "object",
+				"description": "Definition in the JSON shape the command's --file flag accepts",
+			}
+			return
+		}
+		name := strings.ReplaceAll(f.Name, "-", "_")
+		tc.flags[name] = f
+		schema.Properties[name] = flagSchema(f)
+		if ann := f.Annotations[cobra.BashCompOneRequiredFlag]; len(ann) > 0 && ann[0] == "true" {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	leaf.InheritedFlags().VisitAll(add)
+	leaf.LocalFlags().VisitAll(add)
+	slices.Sort(schema.Required)
+
+	tc.tool = mcp.Tool{
+		Name:        t.name,
+		Description: leaf.Short,
+		InputSchema: schema,
+		Annotations: mcp.ToolAnnotation{
+			ReadOnlyHint:    mcp.ToBoolPtr(t.readOnly),
+			DestructiveHint: mcp.ToBoolPtr(false),
+			OpenWorldHint:   mcp.ToBoolPtr(true),
+		},
+	}
+	return tc, nil
+}
+
+// positionalName converts a <required> or [optional] argument of a command's
+// Use line to a property name.
+func positionalName(arg string) (string, bool) {
+	required := strings.HasPrefix(arg, "<")
+	if !required && !strings.HasPrefix(arg, "[") {
+		return "", false
+	}
+	name := strings.Trim(arg, "<>[].")
+	return strings.ReplaceAll(name, "-", "_"), required
+}
+
+// flagSchema maps a flag's value type to JSON Schema.
+func flagSchema(f *pflag.Flag) map[string]any {
+	prop := map[string]any{"description": f.Usage}
+	switch f.Value.Type() {
+	case "bool":
+		prop["type"] = "boolean"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		prop["type"] = "integer"
+	case "float32", "float64":
+		prop["type"] = "number"
+	case "stringArray", "stringSlice":
+		prop["type"] = "array"
+		prop["items"] = map[string]any{"type": "string"}
+	default:
+		prop["type"] = "string"
+	}
+	return prop
+}
+
+// args converts tool call arguments to the command line of the tool's
+// command, returning the JSON definition to send on stdin, if any. Flags come
+// first, then a -- terminator and the positional values, so a value such as
+// "--help" is never parsed as a flag.
+func (tc toolCommand) args(input map[string]any) ([]string, []byte, error) {
+	var argv []string
+	var stdin []byte
+
+	names := make([]string, 0, len(input))
+	for name := range input {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		v := input[name]
+		if slices.Contains(tc.positional, name) {
+			continue
+		}
+		f, ok := tc.flags[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown argument %q", name)
+		}
+		if name == definitionProperty {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, nil, fmt.Errorf("encoding %s: %w", name, err)
+			}
+			stdin = data
+			argv = append(argv, "--"+f.Name+"=-")
+			continue
+		}
+		if values, ok := v.([]any); ok {
+			for _, item := range values {
+				argv = append(argv, "--"+f.Name+"="+argValue(item))
+			}
+			continue
+		}
+		argv = append(argv, "--"+f.Name+"="+argValue(v))
+	}
+
+	argv = append(argv, "--")
+	for _, name := range tc.positional {
+		if v, ok := input[name]; ok {
+			argv = append(argv, argValue(v))
+		}
+	}
+	return argv, stdin, nil
+}
+
+func argValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+		return fmt.Sprint(v)
+	}
+}
+
+// handler runs the tool's command with the caller's arguments, returning its
+// JSON output, or its error as a tool error.
+func (t localTool) handler(opts *options.RootOptions, tc toolCommand) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		argv, stdin, err := tc.args(req.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var stdout, stderr bytes.Buffer
+		ios := &iostreams.IOStreams{
+			In:  io.NopCloser(bytes.NewReader(stdin)),
+			Out: &stdout,
+			Err: &stderr,
+		}
+		ios.SetNeverPrompt(true)
+		callOpts := &options.RootOptions{
+			IOStreams:     ios,
+			Config:        opts.Config,
+			ConfigPath:    opts.ConfigPath,
+			NoInteractive: true,
+			Format:        "json",
+			APIUrl:        opts.APIUrl,
+			Profile:       opts.Profile,
+		}
+
+		cmd := t.group(callOpts)
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		cmd.SetOut(&stderr)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(append(slices.Clone(t.path), argv...))
+		if err := cmd.ExecuteContext(ctx); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(stdout.String()), nil
+	}
+}
@@ -0,0 +1,533 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/term"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+	"github.com/bendrucker/honeycomb-cli/internal/fields"
+	"github.com/spf13/cobra"
+)
+
+const shellPrompt = "mcp> "
+
+// maxShellHistory bounds the lines kept in the shell history file.
+const maxShellHistory = 500
+
+var shellBuiltins = []string{"call", "exit", "help", "prompts", "quit", "resources", "tools"}
+
+const shellHelp = `Commands:
+  <tool> [key=value ...]    Call a tool; values are coerced to the tool's schema types
+  <tool> -F key=value       Pass a typed field: bool, number, null, JSON, or @file
+  call <tool> [...]         Call a tool whose name is also a command
+  tools                     List the server's tools
+  resources                 List the server's resources
+  prompts                   List the server's prompts
+  help                      Show this help
+  exit, quit                End the session (or press Ctrl+D)
+
+Quote values with spaces: query='{"calculations": [{"op": "COUNT"}]}'
+`
+
+func newShellCmd(opts *options.RootOptions, token *string, factory clientFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Start an interactive MCP session",
+		Long: "Start a long-lived MCP session and call tools from a prompt, connecting and " +
+			"authorizing once instead of on every mcp call.\n\n" +
+			"Enter a tool name followed by arguments in the key=value form of mcp call --field, " +
+			"or -F key=value for --typed-field. Tab completes tool names and the argument names " +
+			"of a tool's input schema, and history is kept across sessions. Lines passing an " +
+			"argument the schema marks secret (writeOnly, or format password) are left out of " +
+			"history. Results are written in the --format of the other commands. Enter help to " +
+			"list the shell's commands.\n\n" +
+			"When stdin is not a terminal, the shell reads one command per line, so a session " +
+			"can be scripted.",
+		Example: `  # Start a session
+  honeycomb mcp shell
+
+  # Run a scripted session
+  printf 'tools\nget_environment\n' | honeycomb mcp shell`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runShell(cmd.Context(), opts, derefToken(token), factory)
+		},
+	}
+}
+
+func runShell(ctx context.Context, opts *options.RootOptions, token string, factory clientFactory) error {
+	c, err := connect(ctx, opts, token, factory)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	s := &shell{root: opts, client: c}
+	if err := s.loadTools(ctx); err != nil {
+		return err
+	}
+
+	if f, ok := opts.IOStreams.In.(interface{ Fd() uintptr }); ok && opts.IOStreams.CanPrompt() {
+		return s.runTerminal(ctx, int(f.Fd()))
+	}
+	return s.runLines(ctx)
+}
+
+type shell struct {
+	root   *options.RootOptions
+	client *mcpclient.Client
+	tools  []mcp.Tool
+	// out receives results and errors: the terminal in interactive sessions,
+	// which translates newlines while it is in raw mode.
+	out io.Writer
+}
+
+func (s *shell) loadTools(ctx context.Context) error {
+	result, err := s.client.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return fmt.Errorf("listing tools: %w", err)
+	}
+	s.tools = result.Tools
+	return nil
+}
+
+// runTerminal reads commands from a terminal in raw mode, with line editing,
+// history, and completion.
+func (s *shell) runTerminal(ctx context.Context, fd int) error {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("configuring terminal: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{s.root.IOStreams.In, s.root.IOStreams.Out}, shellPrompt)
+	t.AutoCompleteCallback = s.complete
+	if width, height, err := term.GetSize(fd); err == nil {
+		_ = t.SetSize(width, height)
+	}
+
+	history, err := loadShellHistory(filepath.Join(config.DefaultDir(), "mcp_history"))
+	if err != nil {
+		_, _ = fmt.Fprintf(t, "warning: %v\n", err)
+	}
+	history.skip = s.passesSecret
+	t.History = history
+	s.out = t
+
+	_, _ = fmt.Fprintf(t, "Connected with %d tools. Enter help for commands, exit to quit.\n", len(s.tools))
+	for {
+		line, err := t.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if s.exec(ctx, line) {
+			return nil
+		}
+	}
+}
+
+// runLines reads one command per line, for scripted sessions.
+func (s *shell) runLines(ctx context.Context) error {
+	s.out = s.root.IOStreams.Out
+	scanner := bufio.NewScanner(s.root.IOStreams.In)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if s.exec(ctx, scanner.Text()) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// exec runs one line of input, printing any error, and reports whether the
+// session should end.
+func (s *shell) exec(ctx context.Context, line string) bool {
+	words, err := splitShellLine(line)
+	if err != nil {
+		s.printError(err)
+		return false
+	}
+	if len(words) == 0 {
+		return false
+	}
+
+	switch words[0] {
+	case "exit", "quit":
+		return true
+	case "help":
+		_, _ = io.WriteString(s.out, shellHelp)
+	case "tools":
+		err = s.listTools(ctx)
+	case "resources":
+		err = s.listResources(ctx)
+	case "prompts":
+		err = s.listPrompts(ctx)
+	case "call":
+		if len(words) < 2 {
+			err = fmt.Errorf("usage: call <tool> [key=value ...]")
+			break
+		}
+		err = s.call(ctx, words[1], words[2:])
+	default:
+		err = s.call(ctx, words[0], words[1:])
+	}
+	if err != nil {
+		s.printError(err)
+	}
+	return false
+}
+
+func (s *shell) printError(err error) {
+	_, _ = fmt.Fprintf(s.out, "error: %v\n", err)
+}
+
+// opts returns the root options with output sent to the shell.
+func (s *shell) opts() *options.RootOptions {
+	o := *s.root
+	ios := *s.root.IOStreams
+	ios.Out = s.out
+	o.IOStreams = &ios
+	return &o
+}
+
+func (s *shell) listTools(ctx context.Context) error {
+	if err := s.loadTools(ctx); err != nil {
+		return err
+	}
+	return s.opts().OutputWriter().Write(s.tools, toolsTable)
+}
+
+func (s *shell) listResources(ctx context.Context) error {
+	result, err := s.client.ListResources(ctx, mcp.ListResourcesRequest{})
+	if err != nil {
+		return fmt.Errorf("listing resources: %w", err)
+	}
+	return s.opts().OutputWriterList().WriteList(result.Resources, resourcesTable, "The server has no resources.")
+}
+
+func (s *shell) listPrompts(ctx context.Context) error {
+	result, err := s.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+	if err != nil {
+		return fmt.Errorf("listing prompts: %w", err)
+	}
+	return s.opts().OutputWriterList().WriteList(result.Prompts, promptsTable, "The server has no prompts.")
+}
+
+func (s *shell) call(ctx context.Context, name string, words []string) error {
+	tool, ok := s.tool(name)
+	if !ok {
+		return fmt.Errorf("unknown tool %q; enter tools to list them", name)
+	}
+
+	raw, typed, err := splitCallWords(words)
+	if err != nil {
+		return err
+	}
+
+	// -F @- would read the shell's own input, so it reads nothing instead.
+	args, err := fields.Parse(raw, typed, strings.NewReader(""))
+	if err != nil {
+		return err
+	}
+	if err := coerceArgs(args, tool.InputSchema); err != nil {
+		return err
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := s.client.CallTool(ctx, request)
+	if err != nil {
+		return fmt.Errorf("calling tool %q: %w", name, err)
+	}
+	if result.IsError {
+		return toolResultError(name, result)
+	}
+	return writeCallResult(&callOptions{root: s.opts()}, result)
+}
+
+// splitCallWords separates a tool call's arguments into --field and
+// --typed-field values: -F marks the next word as typed, and -f or a bare
+// key=value word as raw.
+func splitCallWords(words []string) (raw, typed []string, err error) {
+	for i := 0; i < len(words); i++ {
+		switch words[i] {
+		case "-f", "-F":
+			if i+1 == len(words) {
+				return nil, nil, fmt.Errorf("%s requires key=value", words[i])
+			}
+			if words[i] == "-F" {
+				typed = append(typed, words[i+1])
+			} else {
+				raw = append(raw, words[i+1])
+			}
+			i++
+		default:
+			raw = append(raw, words[i])
+		}
+	}
+	return raw, typed, nil
+}
+
+// passesSecret reports whether line calls a tool with an argument that the
+// tool's input schema marks secret, so the line can be kept out of history.
+func (s *shell) passesSecret(line string) bool {
+	words, err := splitShellLine(line)
+	if err != nil || len(words) == 0 {
+		return false
+	}
+	if words[0] == "call" {
+		words = words[1:]
+	} else if slices.Contains(shellBuiltins, words[0]) {
+		return false
+	}
+	if len(words) == 0 {
+		return false
+	}
+	tool, ok := s.tool(words[0])
+	if !ok {
+		return false
+	}
+	raw, typed, err := splitCallWords(words[1:])
+	if err != nil {
+		return false
+	}
+	for _, f := range append(raw, typed...) {
+		key, _, _ := strings.Cut(f, "=")
+		key, _, _ = strings.Cut(key, "[")
+		if prop, ok := tool.InputSchema.Properties[key].(map[string]any); ok && secretProperty(prop) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *shell) tool(name string) (mcp.Tool, bool) {
+	for _, t := range s.tools {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return mcp.Tool{}, false
+}
+
+// complete is the terminal's AutoCompleteCallback. On tab, it completes the
+// word before the cursor: a command or tool name first, then the argument
+// names of the tool. Ambiguous words are extended to the longest common
+// prefix of the candidates.
+func (s *shell) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	before := line[:pos]
+	words := strings.Fields(before)
+	word := ""
+	if len(words) > 0 && !strings.HasSuffix(before, " ") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	if len(words) > 0 && words[0] == "call" {
+		words = words[1:]
+	}
+
+	var candidates []string
+	suffix := " "
+	switch {
+	case len(words) == 0:
+		candidates = append(candidates, shellBuiltins...)
+		for _, t := range s.tools {
+			candidates = append(candidates, t.Name)
+		}
+	case strings.Contains(word, "="):
+		return "", 0, false
+	default:
+		tool, ok := s.tool(words[0])
+		if !ok {
+			return "", 0, false
+		}
+		for name := range tool.InputSchema.Properties {
+			if !slices.ContainsFunc(words[1:], func(w string) bool { return strings.HasPrefix(w, name+"=") }) {
+				candidates = append(candidates, name)
+			}
+		}
+		suffix = "="
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		completion += suffix
+	}
+	if completion == word {
+		return "", 0, false
+	}
+	newBefore := before[:len(before)-len(word)] + completion
+	return newBefore + line[pos:], len(newBefore), true
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// splitShellLine splits a line into words on spaces, honoring single and
+// double quotes and backslash escapes outside single quotes.
+func splitShellLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(runes):
+			i++
+			word.WriteRune(runes[i])
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// shellHistory is a term.History kept in a file, so that history carries over
+// between sessions.
+type shellHistory struct {
+	path    string
+	entries []string
+	// skip reports lines to leave out of history, such as those passing a
+	// secret argument.
+	skip func(string) bool
+}
+
+func loadShellHistory(path string) (*shellHistory, error) {
+	h := &shellHistory{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, fmt.Errorf("reading shell history: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if len(h.entries) > maxShellHistory {
+		// Rewrite the file with only the kept lines, so it stays bounded.
+		h.entries = h.entries[len(h.entries)-maxShellHistory:]
+		if err := h.rewrite(); err != nil {
+			return h, fmt.Errorf("writing shell history: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// Add records a line, skipping blank lines, repeats of the last one, and
+// lines that skip reports, and appends it to the history file. Once the
+// history is full, the file is rewritten with only the kept lines. Failing to
+// write the file only loses the line from later sessions.
+func (h *shellHistory) Add(entry string) {
+	if strings.TrimSpace(entry) == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	if h.skip != nil && h.skip(entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return
+	}
+	if len(h.entries) > maxShellHistory {
+		h.entries = h.entries[len(h.entries)-maxShellHistory:]
+		_ = h.rewrite()
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintln(f, entry)
+	_ = f.Close()
+}
+
+// rewrite replaces the history file with the kept entries.
+func (h *shellHistory) rewrite() error {
+	return os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0o600)
+}
+
+func (h *shellHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns the entry idx lines back, with 0 the most recent.
+func (h *shellHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+var _ term.History = (*shellHistory)(nil)
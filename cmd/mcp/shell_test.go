@@ -0,0 +1,235 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func addShellTools(srv *server.MCPServer, got *map[string]any) {
+	srv.AddTool(
+		mcp.NewTool("run_query",
+			mcp.WithDescription("Run a query"),
+			mcp.WithString("dataset"),
+			mcp.WithObject("query_json"),
+			mcp.WithNumber("limit"),
+		),
+		func(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			*got = req.GetArguments()
+			return mcp.NewToolResultText("ran"), nil
+		},
+	)
+	srv.AddTool(mcp.NewTool("run_fail"), func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("dataset not found"), nil
+	})
+}
+
+func TestShell(t *testing.T) {
+	srv, opts, ts := setupMCPTest(t)
+	var got map[string]any
+	addShellTools(srv, &got)
+	srv.AddPrompt(mcp.NewPrompt("investigate", mcp.WithPromptDescription("Investigate an alert"), mcp.WithArgument("trigger")), nil)
+	opts.Format = "table"
+
+	ts.InBuf.WriteString(strings.Join([]string{
+		"tools",
+		`run_query dataset=api 'query_json={"calculations": [{"op": "COUNT"}]}' limit=5`,
+		"run_fail",
+		"missing",
+		"prompts",
+		"exit",
+		"tools",
+	}, "\n"))
+
+	cmd := newShellCmd(opts, nil, testFactory(srv))
+	cmd.SetArgs([]string{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["dataset"] != "api" || got["limit"] != float64(5) {
+		t.Errorf("arguments = %v", got)
+	}
+	if _, ok := got["query_json"].(map[string]any); !ok {
+		t.Errorf("query_json = %#v, want an object", got["query_json"])
+	}
+
+	out := ts.OutBuf.String()
+	for _, want := range []string{
+		"run_fail",
+		"ran",
+		`error: tool "run_fail" returned error: dataset not found`,
+		`error: unknown tool "missing"`,
+		"investigate",
+		"trigger",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "Run a query"); n != 1 {
+		t.Errorf("tools listed %d times, want once before exit", n)
+	}
+}
+
+func TestShell_Complete(t *testing.T) {
+	srv, opts, _ := setupMCPTest(t)
+	var got map[string]any
+	addShellTools(srv, &got)
+
+	c, err := testFactory(srv)(t.Context(), connectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	s := &shell{root: opts, client: c}
+	if err := s.loadTools(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		line string
+		want string
+		ok   bool
+	}{
+		{line: "res", want: "resources ", ok: true},
+		{line: "ru", want: "run_", ok: true},
+		{line: "run_q", want: "run_query ", ok: true},
+		{line: "run_query d", want: "run_query dataset=", ok: true},
+		{line: "run_query dataset=api q", want: "run_query dataset=api query_json=", ok: true},
+		{line: "call run_query l", want: "call run_query limit=", ok: true},
+		{line: "run_query dataset=a", ok: false},
+		{line: "run_query x", ok: false},
+		{line: "unknown d", ok: false},
+	} {
+		line, pos, ok := s.complete(tc.line, len(tc.line), '\t')
+		if ok != tc.ok || (ok && (line != tc.want || pos != len(tc.want))) {
+			t.Errorf("complete(%q) = %q, %d, %t; want %q, %t", tc.line, line, pos, ok, tc.want, tc.ok)
+		}
+	}
+
+	if _, _, ok := s.complete("run_q", 5, 'x'); ok {
+		t.Error("completed on a key other than tab")
+	}
+}
+
+func TestSplitShellLine(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want []string
+	}{
+		{`run  a=1   b=2`, []string{"run", "a=1", "b=2"}},
+		{`run 'q={"a": "b c"}'`, []string{"run", `q={"a": "b c"}`}},
+		{`run msg="it's here" x=a\ b`, []string{"run", "msg=it's here", "x=a b"}},
+		{`run empty=''`, []string{"run", "empty="}},
+		{``, nil},
+	} {
+		got, err := splitShellLine(tc.line)
+		if err != nil {
+			t.Errorf("split(%q): %v", tc.line, err)
+			continue
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("split(%q) = %q, want %q", tc.line, got, tc.want)
+		}
+	}
+
+	if _, err := splitShellLine(`run "open`); err == nil {
+		t.Error("expected an error for an unterminated quote")
+	}
+}
+
+func TestShellHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+
+	h, err := loadShellHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"tools", "tools", " ", "run_query dataset=api"} {
+		h.Add(line)
+	}
+	if h.Len() != 2 || h.At(0) != "run_query dataset=api" || h.At(1) != "tools" {
+		t.Errorf("history = %q", h.entries)
+	}
+
+	loaded, err := loadShellHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.entries, h.entries) {
+		t.Errorf("loaded history = %q, want %q", loaded.entries, h.entries)
+	}
+
+	var lines []string
+	for i := range maxShellHistory + 10 {
+		lines = append(lines, strings.Repeat("x", i+1))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err = loadShellHistory(path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if loaded.Len() != maxShellHistory || strings.Count(string(data), "\n") != maxShellHistory {
+		t.Errorf("history has %d entries and %d lines on disk, want %d", loaded.Len(), strings.Count(string(data), "\n"), maxShellHistory)
+	}
+
+	loaded.Add("newest")
+	data, _ = os.ReadFile(path)
+	if loaded.Len() != maxShellHistory || strings.Count(string(data), "\n") != maxShellHistory {
+		t.Errorf("after Add, history has %d entries and %d lines on disk, want %d", loaded.Len(), strings.Count(string(data), "\n"), maxShellHistory)
+	}
+	if !strings.HasSuffix(string(data), "\nnewest\n") {
+		t.Error("history file should end with the added line")
+	}
+}
+
+func TestShellHistory_SkipsSecrets(t *testing.T) {
+	s := &shell{tools: []mcp.Tool{{
+		Name: "create_recipient",
+		InputSchema: mcp.ToolInputSchema{Properties: map[string]any{
+			"url":      map[string]any{"type": "string"},
+			"secret":   map[string]any{"type": "string", "writeOnly": true},
+			"password": map[string]any{"type": "string", "format": "password"},
+			"details":  map[string]any{"type": "object"},
+		}},
+	}}}
+
+	for _, tt := range []struct {
+		line string
+		want bool
+	}{
+		{line: "create_recipient url=https://example.com", want: false},
+		{line: "create_recipient url=https://example.com secret=abc", want: true},
+		{line: "call create_recipient -F password=abc", want: true},
+		{line: "create_recipient -f 'secret=a b'", want: true},
+		{line: "create_recipient details[secret]=abc", want: false},
+		{line: "help secret=abc", want: false},
+		{line: "missing secret=abc", want: false},
+	} {
+		if got := s.passesSecret(tt.line); got != tt.want {
+			t.Errorf("passesSecret(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "history")
+	h, err := loadShellHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.skip = s.passesSecret
+	h.Add("create_recipient url=https://example.com secret=abc")
+	h.Add("tools")
+	data, _ := os.ReadFile(path)
+	if h.Len() != 1 || string(data) != "tools\n" {
+		t.Errorf("history = %q, file = %q; want only tools", h.entries, data)
+	}
+}
@@ -182,3 +182,45 @@ func TestAuthKindsEnumeration(t *testing.T) {
 		}
 	}
 }
+
+func TestClientReplay(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"team":{"slug":"recorded"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	if err := config.SetKey("default", config.KeyConfig, "cfg-secret"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("default", config.KeyConfig) })
+
+	t.Setenv("HONEYCOMB_RECORD", dir)
+	client, err := newTestOptions(t, srv.URL, &config.Config{}).Client(config.KeyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetAuthWithResponse(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HONEYCOMB_REPLAY", dir)
+	if _, err := newTestOptions(t, srv.URL, &config.Config{}).Client(config.KeyConfig); err == nil {
+		t.Error("expected an error with both HONEYCOMB_RECORD and HONEYCOMB_REPLAY set")
+	}
+
+	t.Setenv("HONEYCOMB_RECORD", "")
+	srv.Close()
+	client, err = newTestOptions(t, srv.URL, &config.Config{}).Client(config.KeyConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.GetAuthWithResponse(t.Context())
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if resp.JSON200 == nil || resp.JSON200.Team.Slug == nil || *resp.JSON200.Team.Slug != "recorded" {
+		t.Errorf("replayed response = %s", resp.Body)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/cassette"
 	"github.com/bendrucker/honeycomb-cli/internal/config"
 	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
 	"github.com/bendrucker/honeycomb-cli/internal/output"
@@ -19,6 +20,10 @@ type RootOptions struct {
 
 	NoInteractive bool
 	Format        string
+	JQ            string
+	Template      string
+	Columns       []string
+	Sort          string
 	APIUrl        string
 	MCPUrl        string
 	Profile       string
@@ -38,6 +43,16 @@ func (o *RootOptions) ActiveProfile() string {
 	return "default"
 }
 
+// WithProfile returns a copy of o that resolves keys and URLs from another
+// profile, for commands that work across two environments. An --api-url
+// override belongs to the active profile, so the copy does not inherit it.
+func (o *RootOptions) WithProfile(profile string) *RootOptions {
+	c := *o
+	c.Profile = profile
+	c.APIUrl = ""
+	return &c
+}
+
 func (o *RootOptions) ResolveConfigPath() string {
 	if o.ConfigPath != "" {
 		return o.ConfigPath
@@ -133,19 +148,50 @@ func (o *RootOptions) Client(kt config.KeyType) (*api.ClientWithResponses, error
 	if err != nil {
 		return nil, err
 	}
-	client, err := api.NewClientWithResponses(o.ResolveAPIUrl(), api.WithRequestEditorFn(editor))
+	httpClient, err := o.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	client, err := api.NewClientWithResponses(o.ResolveAPIUrl(), api.WithHTTPClient(httpClient), api.WithRequestEditorFn(editor))
 	if err != nil {
 		return nil, fmt.Errorf("creating API client: %w", err)
 	}
 	return client, nil
 }
 
+// HTTPClient returns the client for API requests. It records to or replays
+// from the directory in HONEYCOMB_RECORD or HONEYCOMB_REPLAY when one is set.
+func (o *RootOptions) HTTPClient() (*http.Client, error) {
+	transport, err := cassette.FromEnv(http.DefaultTransport)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
 func (o *RootOptions) OutputWriter() *output.Writer {
-	return output.New(o.IOStreams.Out, o.resolveFormat(detailOutput))
+	return o.newWriter(detailOutput)
 }
 
 func (o *RootOptions) OutputWriterList() *output.Writer {
-	return output.New(o.IOStreams.Out, o.resolveFormat(listOutput))
+	return o.newWriter(listOutput)
+}
+
+func (o *RootOptions) newWriter(kind outputKind) *output.Writer {
+	return output.New(o.IOStreams.Out, o.resolveFormat(kind)).
+		WithFilter(o.OutputFilter()).
+		WithView(o.OutputView())
+}
+
+// OutputView returns the --columns and --sort settings applied to lists.
+func (o *RootOptions) OutputView() output.View {
+	return output.View{Columns: o.Columns, Sort: o.Sort}
+}
+
+// OutputFilter returns the --jq or --template filter applied by output
+// writers in place of --format.
+func (o *RootOptions) OutputFilter() output.Filter {
+	return output.Filter{JQ: o.JQ, Template: o.Template}
 }
 
 func (o *RootOptions) ResolveMCPUrl() string {
@@ -3,6 +3,7 @@ package options
 import (
 	"testing"
 
+	"github.com/bendrucker/honeycomb-cli/internal/config"
 	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
 	"github.com/bendrucker/honeycomb-cli/internal/output"
 )
@@ -68,3 +69,24 @@ func TestResolveFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestWithProfile(t *testing.T) {
+	opts := &RootOptions{
+		Profile: "staging",
+		APIUrl:  "https://staging.example.com",
+		Config: &config.Config{Profiles: map[string]*config.Profile{
+			"prod": {APIUrl: "https://prod.example.com"},
+		}},
+	}
+
+	prod := opts.WithProfile("prod")
+	if got := prod.ActiveProfile(); got != "prod" {
+		t.Errorf("ActiveProfile() = %q, want prod", got)
+	}
+	if got := prod.ResolveAPIUrl(); got != "https://prod.example.com" {
+		t.Errorf("ResolveAPIUrl() = %q, want the prod profile URL", got)
+	}
+	if got := opts.ResolveAPIUrl(); got != "https://staging.example.com" {
+		t.Errorf("original ResolveAPIUrl() = %q, want it unchanged", got)
+	}
+}
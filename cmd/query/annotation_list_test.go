@@ -22,6 +22,7 @@ func setupTest(t *testing.T, handler http.Handler) (*options.RootOptions, *iostr
 	t.Helper()
 	srv := httptest.NewServer(handler)
 	t.Cleanup(srv.Close)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 
 	ts := iostreams.Test(t)
 	opts := &options.RootOptions{
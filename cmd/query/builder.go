@@ -0,0 +1,284 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/oapi-codegen/nullable"
+	"github.com/spf13/cobra"
+)
+
+// The generated api.Query declares its list elements as anonymous structs.
+// These aliases name them so the builder can construct elements directly.
+type (
+	queryCalculation = struct {
+		Column            nullable.Nullable[string] `json:"column,omitempty"`
+		FilterCombination *api.FilterCombination    `json:"filter_combination,omitempty"`
+		Filters           *[]queryFilter            `json:"filters,omitempty"`
+		Name              nullable.Nullable[string] `json:"name,omitempty"`
+		Op                api.QueryOp               `json:"op"`
+	}
+	queryFilter = struct {
+		Column nullable.Nullable[api.FilterColumn] `json:"column"`
+		Op     api.FilterOp                        `json:"op"`
+		Value  *api.FilterValue                    `json:"value,omitempty"`
+	}
+	queryOrder = struct {
+		Column *string               `json:"column,omitempty"`
+		Op     *api.QueryOp          `json:"op,omitempty"`
+		Order  *api.QueryOrdersOrder `json:"order,omitempty"`
+	}
+)
+
+var queryOps = []string{
+	string(api.QueryOpCOUNT), string(api.QueryOpCONCURRENCY), string(api.QueryOpSUM),
+	string(api.QueryOpAVG), string(api.QueryOpCOUNTDISTINCT), string(api.QueryOpHEATMAP),
+	string(api.QueryOpMAX), string(api.QueryOpMIN),
+	string(api.QueryOpP001), string(api.QueryOpP01), string(api.QueryOpP05), string(api.QueryOpP10),
+	string(api.QueryOpP20), string(api.QueryOpP25), string(api.QueryOpP50), string(api.QueryOpP75),
+	string(api.QueryOpP80), string(api.QueryOpP90), string(api.QueryOpP95), string(api.QueryOpP99),
+	string(api.QueryOpP999),
+	string(api.QueryOpRATEAVG), string(api.QueryOpRATEMAX), string(api.QueryOpRATESUM),
+}
+
+var filterOps = []string{
+	string(api.FilterOpEqual), string(api.FilterOpEmpty),
+	string(api.FilterOpGreaterThan), string(api.FilterOpGreaterThanEqual),
+	string(api.FilterOpLessThan), string(api.FilterOpLessThanEqual),
+	string(api.FilterOpStartsWith), string(api.FilterOpDoesNotStartWith),
+	string(api.FilterOpEndsWith), string(api.FilterOpDoesNotEndWith),
+	string(api.FilterOpContains), string(api.FilterOpDoesNotContain),
+	string(api.FilterOpExists), string(api.FilterOpDoesNotExist),
+	string(api.FilterOpIn), string(api.FilterOpNotIn),
+}
+
+// columnlessOps are the calculations that do not take a column.
+var columnlessOps = []api.QueryOp{api.QueryOpCOUNT, api.QueryOpCONCURRENCY}
+
+// builderFlags holds the query builder flags of query run.
+type builderFlags struct {
+	calcs       []string
+	wheres      []string
+	breakdowns  []string
+	orders      []string
+	limit       int
+	granularity int
+}
+
+var builderFlagNames = []string{"calc", "where", "breakdown", "order", "limit", "granularity"}
+
+func (b *builderFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&b.calcs, "calc", nil, "Calculation such as COUNT or P99(duration_ms) (repeatable)")
+	cmd.Flags().StringArrayVar(&b.wheres, "where", nil, "Filter such as 'service.name = api' or 'error exists' (repeatable)")
+	cmd.Flags().StringArrayVar(&b.breakdowns, "breakdown", nil, "Column to group results by (repeatable)")
+	cmd.Flags().StringArrayVar(&b.orders, "order", nil, "Order by a breakdown or calculation; prefix with - for descending (repeatable)")
+	cmd.Flags().IntVar(&b.limit, "limit", 0, "Maximum number of result groups")
+	cmd.Flags().IntVar(&b.granularity, "granularity", 0, "Time resolution of the series, in seconds")
+
+	for _, name := range builderFlagNames {
+		cmd.MarkFlagsMutuallyExclusive("file", name)
+		cmd.MarkFlagsMutuallyExclusive("annotation", name)
+	}
+}
+
+func (b *builderFlags) isSet(cmd *cobra.Command) bool {
+	return command.AnyChanged(cmd, builderFlagNames...)
+}
+
+// build assembles a query spec from the builder flags.
+func (b *builderFlags) build() (api.Query, error) {
+	var q api.Query
+
+	if len(b.calcs) > 0 {
+		calcs := make([]queryCalculation, 0, len(b.calcs))
+		for _, raw := range b.calcs {
+			op, column, err := parseCalc(raw)
+			if err != nil {
+				return q, err
+			}
+			calc := queryCalculation{Op: op}
+			if column != "" {
+				calc.Column = nullable.NewNullableWithValue(column)
+			}
+			calcs = append(calcs, calc)
+		}
+		q.Calculations = &calcs
+	}
+
+	if len(b.wheres) > 0 {
+		filters := make([]queryFilter, 0, len(b.wheres))
+		for _, raw := range b.wheres {
+			f, err := parseWhere(raw)
+			if err != nil {
+				return q, err
+			}
+			filters = append(filters, f)
+		}
+		q.Filters = &filters
+	}
+
+	if len(b.breakdowns) > 0 {
+		breakdowns := slices.Clone(b.breakdowns)
+		q.Breakdowns = &breakdowns
+	}
+
+	if len(b.orders) > 0 {
+		orders := make([]queryOrder, 0, len(b.orders))
+		for _, raw := range b.orders {
+			o, err := parseOrder(raw)
+			if err != nil {
+				return q, err
+			}
+			orders = append(orders, o)
+		}
+		q.Orders = &orders
+	}
+
+	if b.limit < 0 {
+		return q, fmt.Errorf("--limit must be positive")
+	}
+	if b.limit > 0 {
+		q.Limit = &b.limit
+	}
+	if b.granularity < 0 {
+		return q, fmt.Errorf("--granularity must be positive")
+	}
+	if b.granularity > 0 {
+		q.Granularity = &b.granularity
+	}
+
+	return q, nil
+}
+
+var calcPattern = regexp.MustCompile(`^([A-Za-z_0-9]+)(?:\((.*)\))?$`)
+
+// parseCalc parses OP or OP(column). The op is case-insensitive.
+func parseCalc(raw string) (api.QueryOp, string, error) {
+	m := calcPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if m == nil {
+		return "", "", fmt.Errorf("invalid --calc %q: expected OP or OP(column)", raw)
+	}
+
+	op := api.QueryOp(strings.ToUpper(m[1]))
+	if !op.Valid() {
+		return "", "", fmt.Errorf("invalid --calc %q: unknown op %q, must be one of %s", raw, m[1], command.EnumUsage(queryOps))
+	}
+
+	column := strings.TrimSpace(m[2])
+	switch {
+	case slices.Contains(columnlessOps, op) && column != "":
+		return "", "", fmt.Errorf("invalid --calc %q: %s does not take a column", raw, op)
+	case !slices.Contains(columnlessOps, op) && column == "":
+		return "", "", fmt.Errorf("invalid --calc %q: %s requires a column, as in %s(duration_ms)", raw, op, op)
+	}
+	return op, column, nil
+}
+
+// parseWhere parses "column op [value]". Values for in and not-in are
+// comma-separated; exists and does-not-exist take no value. Numbers and
+// booleans are sent as such unless the value is double-quoted.
+func parseWhere(raw string) (queryFilter, error) {
+	fields := strings.Fields(raw)
+	if len(fields) < 2 {
+		return queryFilter{}, fmt.Errorf("invalid --where %q: expected 'column op value'", raw)
+	}
+
+	column := fields[0]
+	op := api.FilterOp(strings.ToLower(fields[1]))
+	if !op.Valid() {
+		return queryFilter{}, fmt.Errorf("invalid --where %q: unknown operator %q, must be one of %s", raw, fields[1], command.EnumUsage(filterOps))
+	}
+
+	// Re-slice the original string rather than joining fields so that
+	// whitespace inside a value is preserved.
+	rest := strings.TrimSpace(raw)
+	for _, f := range fields[:2] {
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, f))
+	}
+
+	filter := queryFilter{
+		Column: nullable.NewNullableWithValue(column),
+		Op:     op,
+	}
+
+	var value any
+	switch op {
+	case api.FilterOpExists, api.FilterOpDoesNotExist:
+		if rest != "" {
+			return queryFilter{}, fmt.Errorf("invalid --where %q: %s does not take a value", raw, op)
+		}
+		return filter, nil
+	case api.FilterOpIn, api.FilterOpNotIn:
+		if rest == "" {
+			return queryFilter{}, fmt.Errorf("invalid --where %q: %s requires a comma-separated list", raw, op)
+		}
+		var values []any
+		for _, v := range strings.Split(rest, ",") {
+			values = append(values, filterValue(strings.TrimSpace(v)))
+		}
+		value = values
+	default:
+		if rest == "" {
+			return queryFilter{}, fmt.Errorf("invalid --where %q: %s requires a value", raw, op)
+		}
+		value = filterValue(rest)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return queryFilter{}, fmt.Errorf("encoding --where value: %w", err)
+	}
+	filter.Value = &api.FilterValue{}
+	if err := filter.Value.UnmarshalJSON(data); err != nil {
+		return queryFilter{}, fmt.Errorf("encoding --where value: %w", err)
+	}
+	return filter, nil
+}
+
+// filterValue converts a --where value: a quoted string as it is, or
+// otherwise a number or boolean when it is one.
+func filterValue(s string) any {
+	if unquoted, err := strconv.Unquote(s); err == nil && strings.HasPrefix(s, `"`) {
+		return unquoted
+	}
+	return command.InferValue(s)
+}
+
+// parseOrder parses an order term: a calculation such as P99(duration_ms) or
+// COUNT, or a breakdown column. A leading - sorts descending and a leading +
+// ascending.
+func parseOrder(raw string) (queryOrder, error) {
+	term := strings.TrimSpace(raw)
+	var order queryOrder
+
+	switch {
+	case strings.HasPrefix(term, "-"):
+		order.Order = ptr(api.Descending)
+		term = term[1:]
+	case strings.HasPrefix(term, "+"):
+		order.Order = ptr(api.Ascending)
+		term = term[1:]
+	}
+	if term == "" {
+		return queryOrder{}, fmt.Errorf("invalid --order %q: expected a column or calculation", raw)
+	}
+
+	if m := calcPattern.FindStringSubmatch(term); m != nil {
+		if op := api.QueryOp(strings.ToUpper(m[1])); op.Valid() && (m[2] != "" || slices.Contains(columnlessOps, op)) {
+			order.Op = &op
+			if column := strings.TrimSpace(m[2]); column != "" {
+				order.Column = &column
+			}
+			return order, nil
+		}
+	}
+
+	order.Column = &term
+	return order, nil
+}
@@ -0,0 +1,175 @@
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRun_BuilderDryRun(t *testing.T) {
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected API call: %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{
+		"run", "--dataset", "test-dataset", "--dry-run",
+		"--calc", "P99(duration_ms)", "--calc", "count",
+		"--where", "service.name = api",
+		"--where", "http.status_code in 500, 502",
+		"--where", "error exists",
+		"--where", `user.id = "42"`,
+		"--breakdown", "http.route",
+		"--order", "-P99(duration_ms)",
+		"--order", "http.route",
+		"--limit", "20",
+		"--granularity", "60",
+		"--time-range", "2h",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "breakdowns": ["http.route"],
+  "calculations": [{"column": "duration_ms", "op": "P99"}, {"op": "COUNT"}],
+  "filters": [
+    {"column": "service.name", "op": "=", "value": "api"},
+    {"column": "http.status_code", "op": "in", "value": [500, 502]},
+    {"column": "error", "op": "exists"},
+    {"column": "user.id", "op": "=", "value": "42"}
+  ],
+  "granularity": 60,
+  "limit": 20,
+  "orders": [
+    {"column": "duration_ms", "op": "P99", "order": "descending"},
+    {"column": "http.route"}
+  ],
+  "time_range": 7200
+}`
+	assertJSONEqual(t, ts.OutBuf.Bytes(), want)
+}
+
+func TestRun_Builder(t *testing.T) {
+	var created map[string]any
+	opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/1/queries/test-dataset":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("decode request body: %v", err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "qry-1"})
+		case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/test-dataset":
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "result-1"})
+		case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/test-dataset/result-1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "result-1", "complete": true})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--calc", "COUNT", "--breakdown", "service.name"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(created)
+	assertJSONEqual(t, data, `{"calculations": [{"op": "COUNT"}], "breakdowns": ["service.name"]}`)
+}
+
+func TestRun_BuilderInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "unknown op", args: []string{"--calc", "P42(duration_ms)"}, want: `unknown op "P42"`},
+		{name: "missing column", args: []string{"--calc", "AVG"}, want: "AVG requires a column"},
+		{name: "unexpected column", args: []string{"--calc", "COUNT(x)"}, want: "COUNT does not take a column"},
+		{name: "malformed calc", args: []string{"--calc", "P99(duration_ms"}, want: "expected OP or OP(column)"},
+		{name: "unknown operator", args: []string{"--where", "a ~ b"}, want: `unknown operator "~"`},
+		{name: "missing operator", args: []string{"--where", "a"}, want: "expected 'column op value'"},
+		{name: "missing value", args: []string{"--where", "a ="}, want: "= requires a value"},
+		{name: "exists with value", args: []string{"--where", "a exists b"}, want: "exists does not take a value"},
+		{name: "empty order", args: []string{"--order", "-"}, want: "expected a column or calculation"},
+		{name: "negative limit", args: []string{"--calc", "COUNT", "--limit", "-1"}, want: "--limit must be positive"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				t.Error("unexpected API call")
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+
+			cmd := NewCmd(opts)
+			cmd.SetArgs(append([]string{"run", "--dataset", "test-dataset", "--dry-run"}, tc.args...))
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestRun_BuilderExclusiveWithFile(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--file", "query.json", "--calc", "COUNT"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for --file with builder flags")
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("unmarshal output: %v\n%s", err, got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("unmarshal want: %v", err)
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	if string(gb) != string(wb) {
+		t.Errorf("JSON mismatch:\ngot:  %s\nwant: %s", gb, wb)
+	}
+}
+
+func TestParseWhere_Values(t *testing.T) {
+	for _, tc := range []struct {
+		where string
+		want  string
+	}{
+		{"zip = 00123", `"00123"`},
+		{"x = nan", `"nan"`},
+		{"x = inf", `"inf"`},
+		{"x = 1e3", `1000`},
+		{"x = 42", `42`},
+		{"x = 1.5", `1.5`},
+		{"x = true", `true`},
+		{`x = "42"`, `"42"`},
+		{"x in 007, 8", `["007",8]`},
+	} {
+		t.Run(tc.where, func(t *testing.T) {
+			filter, err := parseWhere(tc.where)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := filter.Value.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("value = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
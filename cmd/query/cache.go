@@ -0,0 +1,114 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/bendrucker/honeycomb-cli/internal/querycache"
+	"github.com/spf13/cobra"
+)
+
+type cacheItem struct {
+	ID        string `json:"id" col:"ID"`
+	Profile   string `json:"profile" col:"Profile"`
+	APIUrl    string `json:"api_url"`
+	Dataset   string `json:"dataset" col:"Dataset"`
+	StartTime string `json:"start_time" col:"Start"`
+	EndTime   string `json:"end_time" col:"End"`
+	Series    bool   `json:"series" col:"Series"`
+	CreatedAt string `json:"created_at" col:"Cached At"`
+	Spec      string `json:"spec" col:"Query"`
+}
+
+var cacheListTable = output.TableFromTags[cacheItem]()
+
+// NewCacheCmd manages the query result cache. Its --dataset flag filters
+// entries rather than being required, as it is for the rest of query, and an
+// explicit --profile limits it to the results of that profile.
+func NewCacheCmd(opts *options.RootOptions) *cobra.Command {
+	var dataset string
+
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage cached query results",
+		Example: `  # List cached results
+  honeycomb query cache list
+
+  # Remove the cached results of a dataset
+  honeycomb query cache clear --dataset my-dataset
+
+  # List the cached results of a profile
+  honeycomb query cache list --profile prod`,
+	}
+
+	cmd.PersistentFlags().StringVar(&dataset, "dataset", "", "Only include results for this dataset")
+
+	cmd.AddCommand(NewCacheListCmd(opts, &dataset))
+	cmd.AddCommand(NewCacheClearCmd(opts, &dataset))
+
+	return command.Group(cmd)
+}
+
+func NewCacheListCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List cached query results",
+		Example: `  # List cached results
+  honeycomb query cache list
+
+  # Re-render one of them
+  honeycomb query run --dataset my-dataset --from-cache 3f2a9c1b7d4e`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			entries, err := querycache.New(querycache.DefaultDir()).List()
+			if err != nil {
+				return err
+			}
+
+			filter := cacheFilter(opts, *dataset)
+			items := []cacheItem{}
+			for _, e := range entries {
+				if !filter.Match(e) {
+					continue
+				}
+				items = append(items, cacheItem{
+					ID:        e.ID,
+					Profile:   e.Profile,
+					APIUrl:    e.APIUrl,
+					Dataset:   e.Dataset,
+					StartTime: e.StartTime.Format(time.RFC3339),
+					EndTime:   e.EndTime.Format(time.RFC3339),
+					Series:    e.Series,
+					CreatedAt: e.CreatedAt.Format(time.RFC3339),
+					Spec:      output.Truncate(string(e.Spec), 60),
+				})
+			}
+
+			return opts.OutputWriterList().WriteList(items, cacheListTable, "No cached query results.")
+		},
+	}
+}
+
+func NewCacheClearCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove cached query results",
+		Example: `  # Remove every cached result
+  honeycomb query cache clear`,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			removed, err := querycache.New(querycache.DefaultDir()).Clear(cacheFilter(opts, *dataset))
+			if err != nil {
+				return err
+			}
+			return opts.OutputWriter().WriteMessage(map[string]int{"removed": removed}, fmt.Sprintf("Removed %d cached query results.", removed))
+		},
+	}
+}
+
+// cacheFilter selects the entries of dataset and, when --profile is given,
+// of that profile. Without it every profile's entries are included.
+func cacheFilter(opts *options.RootOptions, dataset string) querycache.Filter {
+	return querycache.Filter{Profile: opts.Profile, Dataset: dataset}
+}
@@ -0,0 +1,190 @@
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+)
+
+func cachedQueryHandler(t *testing.T, creates *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/1/queries/test-dataset":
+			creates.Add(1)
+			_, _ = w.Write([]byte(`{"id": "qry-1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/test-dataset":
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "result-1"}`))
+		case r.URL.Path == "/1/query_results/test-dataset/result-1":
+			_, _ = w.Write([]byte(`{
+				"id": "result-1",
+				"complete": true,
+				"data": {"results": [{"data": {"service.name": "api", "COUNT": 10}}]},
+				"query": {"breakdowns": ["service.name"], "calculations": [{"op": "COUNT"}]}
+			}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestRun_Cache(t *testing.T) {
+	var creates atomic.Int32
+	opts, ts := setupTest(t, cachedQueryHandler(t, &creates))
+	args := []string{"run", "--dataset", "test-dataset", "--calc", "COUNT", "--breakdown", "service.name"}
+
+	run := func(extra ...string) string {
+		t.Helper()
+		ts.OutBuf.Reset()
+		ts.ErrBuf.Reset()
+		cmd := NewCmd(opts)
+		cmd.SetArgs(append(append([]string{}, args...), extra...))
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		return ts.OutBuf.String()
+	}
+
+	first := run()
+	second := run()
+	if n := creates.Load(); n != 1 {
+		t.Errorf("queries created = %d, want 1", n)
+	}
+	if second != first {
+		t.Errorf("cached output = %s, want %s", second, first)
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "Using result") {
+		t.Errorf("stderr = %q, want a cache notice", ts.ErrBuf.String())
+	}
+
+	run("--no-cache")
+	run("--cache-ttl", "0s")
+	if n := creates.Load(); n != 3 {
+		t.Errorf("queries created = %d, want 3 after --no-cache and an expired TTL", n)
+	}
+
+	cmd := NewCmd(opts)
+	ts.OutBuf.Reset()
+	cmd.SetArgs([]string{"cache", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var items []cacheItem
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &items); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(items) != 1 || items[0].Dataset != "test-dataset" {
+		t.Fatalf("cache entries = %+v, want one for test-dataset", items)
+	}
+
+	if got := run("--from-cache", items[0].ID); got != first {
+		t.Errorf("--from-cache output = %s, want %s", got, first)
+	}
+}
+
+func TestRun_FromCache(t *testing.T) {
+	var creates atomic.Int32
+	opts, ts := setupTest(t, cachedQueryHandler(t, &creates))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--calc", "COUNT"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd = NewCmd(opts)
+	ts.OutBuf.Reset()
+	cmd.SetArgs([]string{"cache", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var items []cacheItem
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &items); err != nil || len(items) != 1 {
+		t.Fatalf("cache entries = %s", ts.OutBuf.String())
+	}
+	id := items[0].ID
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--dataset", "other"}, "is for dataset test-dataset"},
+		{[]string{"--dataset", "test-dataset", "--series"}, "has no time series"},
+		{[]string{"--dataset", "test-dataset", "--no-cache"}, "none of the others can be"},
+	} {
+		cmd := NewCmd(opts)
+		cmd.SetArgs(append([]string{"run", "--from-cache", id}, tc.args...))
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: err = %v, want containing %q", tc.args, err, tc.want)
+		}
+	}
+
+	cmd = NewCmd(opts)
+	ts.OutBuf.Reset()
+	cmd.SetArgs([]string{"cache", "clear", "--dataset", "test-dataset"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(ts.OutBuf.String()); got != `{
+  "removed": 1
+}` {
+		t.Errorf("clear output = %s", got)
+	}
+
+	cmd = NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--from-cache", id})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("err = %v, want not found after clear", err)
+	}
+	if n := creates.Load(); n != 1 {
+		t.Errorf("queries created = %d, want 1", n)
+	}
+}
+
+func TestRun_CacheProfiles(t *testing.T) {
+	var creates atomic.Int32
+	opts, ts := setupTest(t, cachedQueryHandler(t, &creates))
+	if err := config.SetKey("prod", config.KeyConfig, "prod-key"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.DeleteKey("prod", config.KeyConfig) })
+
+	for _, profile := range []string{"", "prod", "prod"} {
+		opts.Profile = profile
+		cmd := NewCmd(opts)
+		cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--calc", "COUNT"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := creates.Load(); n != 2 {
+		t.Errorf("queries created = %d, want one per profile", n)
+	}
+
+	ts.OutBuf.Reset()
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"cache", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var items []cacheItem
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &items); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(items) != 1 || items[0].Profile != "prod" || items[0].APIUrl != opts.APIUrl {
+		t.Fatalf("cache entries = %+v, want the prod entry", items)
+	}
+
+	opts.Profile = ""
+	cmd = NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--from-cache", items[0].ID})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "is for profile prod, not default") {
+		t.Errorf("err = %v, want a profile mismatch", err)
+	}
+}
@@ -0,0 +1,162 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+// Clauses of a query composed in honeycomb browse are named after the query
+// run builder flags and take the same syntax, one clause per line, such as
+// "calc P99(duration_ms)" or "where service.name = api".
+const (
+	clauseCalc        = "calc"
+	clauseWhere       = "where"
+	clauseBreakdown   = "breakdown"
+	clauseOrder       = "order"
+	clauseLimit       = "limit"
+	clauseGranularity = "granularity"
+	clauseTimeRange   = "time-range"
+)
+
+var clauseKeywords = []string{clauseCalc, clauseWhere, clauseBreakdown, clauseOrder, clauseLimit, clauseGranularity, clauseTimeRange}
+
+// defaultBrowseTimeRange is the time range of a composed query without a
+// time-range clause.
+const defaultBrowseTimeRange = "2h"
+
+var timeRangeSuggestions = []string{"10m", "30m", "1h", "2h", "6h", "1d", "7d"}
+
+// ComposedQuery is a query spec assembled from browse clauses.
+type ComposedQuery struct {
+	builder   builderFlags
+	timeRange command.Duration
+}
+
+// ComposeQuery parses clauses into a query. Each clause is validated as the
+// matching builder flag would be.
+func ComposeQuery(clauses []string) (ComposedQuery, error) {
+	var c ComposedQuery
+	if err := c.timeRange.Set(defaultBrowseTimeRange); err != nil {
+		return c, err
+	}
+
+	for _, clause := range clauses {
+		keyword, arg, _ := strings.Cut(strings.TrimSpace(clause), " ")
+		arg = strings.TrimSpace(arg)
+		if arg == "" {
+			return c, fmt.Errorf("%s requires a value", keyword)
+		}
+
+		switch keyword {
+		case clauseCalc:
+			c.builder.calcs = append(c.builder.calcs, arg)
+		case clauseWhere:
+			c.builder.wheres = append(c.builder.wheres, arg)
+		case clauseBreakdown:
+			c.builder.breakdowns = append(c.builder.breakdowns, arg)
+		case clauseOrder:
+			c.builder.orders = append(c.builder.orders, arg)
+		case clauseLimit, clauseGranularity:
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return c, fmt.Errorf("invalid %s %q: expected a number", keyword, arg)
+			}
+			if keyword == clauseLimit {
+				c.builder.limit = n
+			} else {
+				c.builder.granularity = n
+			}
+		case clauseTimeRange:
+			if err := c.timeRange.Set(arg); err != nil {
+				return c, err
+			}
+		default:
+			return c, fmt.Errorf("unknown clause %q, must be one of %s", keyword, command.EnumUsage(clauseKeywords))
+		}
+	}
+
+	if _, err := c.builder.build(); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Spec returns the JSON query spec, with the time range applied.
+func (c ComposedQuery) Spec() ([]byte, error) {
+	q, err := c.builder.build()
+	if err != nil {
+		return nil, err
+	}
+	spec, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("encoding query spec: %w", err)
+	}
+	return timeOverrides{timeRange: c.timeRange}.apply(spec)
+}
+
+// ClauseSuggestions completes the clause being typed. Suggestions are whole
+// clauses, since the input matches them by prefix: keywords, then column
+// names and ops in the positions each clause takes them.
+func ClauseSuggestions(input string, columns []string, clauses []string) []string {
+	keyword, arg, ok := strings.Cut(input, " ")
+	if !ok {
+		return prefixed("", clauseKeywords, " ")
+	}
+
+	prefix := keyword + " "
+	switch keyword {
+	case clauseCalc:
+		if op, _, ok := strings.Cut(arg, "("); ok {
+			return prefixed(prefix+op+"(", columns, ")")
+		}
+		var ops []string
+		for _, op := range queryOps {
+			if slices.Contains(columnlessOps, api.QueryOp(op)) {
+				ops = append(ops, op)
+			} else {
+				ops = append(ops, op+"(")
+			}
+		}
+		return prefixed(prefix, ops, "")
+	case clauseWhere:
+		column, rest, ok := strings.Cut(arg, " ")
+		if !ok {
+			return prefixed(prefix, columns, " ")
+		}
+		if strings.Contains(rest, " ") {
+			return nil
+		}
+		return prefixed(prefix+column+" ", filterOps, " ")
+	case clauseBreakdown:
+		return prefixed(prefix, columns, "")
+	case clauseOrder:
+		if strings.HasPrefix(arg, "-") {
+			prefix += "-"
+		}
+		var terms []string
+		for _, clause := range clauses {
+			k, a, _ := strings.Cut(clause, " ")
+			if k == clauseCalc || k == clauseBreakdown {
+				terms = append(terms, strings.TrimSpace(a))
+			}
+		}
+		return prefixed(prefix, terms, "")
+	case clauseTimeRange:
+		return prefixed(prefix, timeRangeSuggestions, "")
+	}
+	return nil
+}
+
+func prefixed(prefix string, values []string, suffix string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = prefix + v + suffix
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package query
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestComposeQuery(t *testing.T) {
+	q, err := ComposeQuery([]string{
+		"calc P99(duration_ms)",
+		"where service.name = api",
+		"breakdown http.route",
+		"order -P99(duration_ms)",
+		"limit 10",
+		"time-range 1d",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := q.Spec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, spec, `{
+		"calculations": [{"op": "P99", "column": "duration_ms"}],
+		"filters": [{"column": "service.name", "op": "=", "value": "api"}],
+		"breakdowns": ["http.route"],
+		"orders": [{"op": "P99", "column": "duration_ms", "order": "descending"}],
+		"limit": 10,
+		"time_range": 86400
+	}`)
+}
+
+func TestComposeQuery_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		clause string
+		want   string
+	}{
+		{"calc AVG", "AVG requires a column"},
+		{"where a", "expected 'column op value'"},
+		{"limit ten", `invalid limit "ten"`},
+		{"select x", `unknown clause "select"`},
+		{"breakdown", "breakdown requires a value"},
+	} {
+		_, err := ComposeQuery([]string{tc.clause})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q: err = %v, want containing %q", tc.clause, err, tc.want)
+		}
+	}
+}
+
+func TestClauseSuggestions(t *testing.T) {
+	columns := []string{"duration_ms", "service.name"}
+	for _, tc := range []struct {
+		input   string
+		clauses []string
+		want    string
+	}{
+		{input: "", want: "where "},
+		{input: "calc ", want: "calc COUNT"},
+		{input: "calc ", want: "calc P99("},
+		{input: "calc P99(", want: "calc P99(duration_ms)"},
+		{input: "where ser", want: "where service.name "},
+		{input: "where service.name ", want: "where service.name starts-with "},
+		{input: "breakdown ", want: "breakdown service.name"},
+		{input: "order -", clauses: []string{"calc COUNT"}, want: "order -COUNT"},
+		{input: "time-range ", want: "time-range 2h"},
+	} {
+		got := ClauseSuggestions(tc.input, columns, tc.clauses)
+		if !slices.Contains(got, tc.want) {
+			t.Errorf("suggestions for %q = %v, want %q", tc.input, got, tc.want)
+		}
+	}
+
+	if got := ClauseSuggestions("where service.name = a", columns, nil); got != nil {
+		t.Errorf("suggestions for a value = %v, want none", got)
+	}
+}
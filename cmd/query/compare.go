@@ -0,0 +1,457 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/bendrucker/honeycomb-cli/internal/poll"
+	"github.com/spf13/cobra"
+)
+
+const (
+	directionIncrease = "increase"
+	directionDecrease = "decrease"
+	directionAny      = "any"
+)
+
+var regressionDirections = []string{directionIncrease, directionDecrease, directionAny}
+
+func NewCompareCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	var (
+		file       string
+		annotation string
+		builder    builderFlags
+		baseline   timeWindow
+		current    timeWindow
+		threshold  float64
+		direction  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "compare",
+		Short: "Compare a query's results over two time windows",
+		Long: "Run the same query over a baseline and a current window and compare the results.\n\n" +
+			"Windows are written as START..END, where each end takes the formats of " +
+			"--start-time in query run. Results are joined on their breakdown values, and " +
+			"each calculation is shown with its baseline and current values and the absolute " +
+			"and percent change between them. A group present only in the baseline window counts " +
+			"as a 100% decrease, and one present only in the current window has no change.\n\n" +
+			"With --threshold, a calculation that changed by more than that percentage in the " +
+			"--direction of a regression is listed in the Regressed column, and the command " +
+			"exits non-zero if any group regressed. A calculation that rises from zero has no " +
+			"percent change and always counts as an increase.",
+		Example: `  # Compare the hour before a deploy to the hour after it
+  honeycomb query compare --dataset my-dataset --file query.json \
+    --baseline -2h..-1h --current -1h..now
+
+  # Fail a CI gate if p99 latency of any route rose by more than 10%
+  honeycomb query compare --dataset my-dataset \
+    --calc 'P99(duration_ms)' --breakdown http.route \
+    --baseline -2h..-1h --current -1h..now --threshold 10
+
+  # Flag routes whose throughput dropped by more than 25%
+  honeycomb query compare --dataset my-dataset --annotation q-abc \
+    --baseline -1d..-23h --current -1h..now --threshold 25 --direction decrease`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := command.ValidateEnum("direction", direction, regressionDirections); err != nil {
+				return err
+			}
+
+			var spec []byte
+			if builder.isSet(cmd) {
+				q, err := builder.build()
+				if err != nil {
+					return err
+				}
+				if spec, err = json.Marshal(q); err != nil {
+					return fmt.Errorf("encoding query spec: %w", err)
+				}
+			}
+			if spec == nil && file == "" && annotation == "" {
+				return fmt.Errorf("either --file, --annotation, or builder flags such as --calc are required")
+			}
+
+			o := compareOptions{
+				dataset:    *dataset,
+				file:       file,
+				annotation: annotation,
+				spec:       spec,
+				baseline:   baseline,
+				current:    current,
+				direction:  direction,
+			}
+			if cmd.Flags().Changed("threshold") {
+				o.threshold = &threshold
+			}
+			return runQueryCompare(cmd.Context(), opts, o)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Path to query spec JSON file (- for stdin)")
+	cmd.Flags().StringVarP(&annotation, "annotation", "a", "", "Annotation ID of the query to compare")
+	cmd.Flags().Var(&baseline, "baseline", "Baseline window as START..END, e.g. -2h..-1h (required)")
+	cmd.Flags().Var(&current, "current", "Current window as START..END, e.g. -1h..now (required)")
+	cmd.Flags().Float64Var(&threshold, "threshold", 0, "Percent change that counts as a regression and fails the command")
+	cmd.Flags().StringVar(&direction, "direction", directionIncrease, "Change that counts as a regression: "+command.EnumUsage(regressionDirections))
+	_ = cmd.MarkFlagRequired("baseline")
+	_ = cmd.MarkFlagRequired("current")
+	cmd.MarkFlagsMutuallyExclusive("file", "annotation")
+	builder.register(cmd)
+
+	return cmd
+}
+
+// timeWindow is a pflag.Value for START..END flags. Both ends take the
+// formats of command.Time and resolve against the same now.
+type timeWindow struct {
+	start command.Time
+	end   command.Time
+}
+
+func (w *timeWindow) Set(s string) error {
+	start, end, ok := strings.Cut(s, "..")
+	if !ok {
+		return fmt.Errorf("invalid window %q: expected START..END", s)
+	}
+	now := command.Now()
+	var parsed timeWindow
+	var err error
+	if parsed.start.Time, err = command.ParseTime(start, now); err != nil {
+		return err
+	}
+	if parsed.end.Time, err = command.ParseTime(end, now); err != nil {
+		return err
+	}
+	if !parsed.end.After(parsed.start.Time) {
+		return fmt.Errorf("invalid window %q: end must be after start", s)
+	}
+	*w = parsed
+	return nil
+}
+
+func (w *timeWindow) String() string {
+	if w.start.IsZero() {
+		return ""
+	}
+	return w.start.String() + ".." + w.end.String()
+}
+
+func (w *timeWindow) Type() string {
+	return "window"
+}
+
+func (w timeWindow) overrides() timeOverrides {
+	return timeOverrides{start: w.start, end: w.end}
+}
+
+type compareOptions struct {
+	dataset    string
+	file       string
+	annotation string
+	spec       []byte
+	baseline   timeWindow
+	current    timeWindow
+	// threshold is the percent change of a regression; nil to not check.
+	threshold *float64
+	direction string
+}
+
+// comparison is the JSON output of query compare.
+type comparison struct {
+	Baseline    windowBounds `json:"baseline"`
+	Current     windowBounds `json:"current"`
+	Threshold   *float64     `json:"threshold,omitempty"`
+	Direction   string       `json:"direction,omitempty"`
+	Breakdowns  []string     `json:"breakdowns"`
+	Calcs       []string     `json:"calculations"`
+	Groups      []groupDelta `json:"groups"`
+	Regressions int          `json:"regressions"`
+}
+
+type windowBounds struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// groupDelta is the comparison of one result group, keyed by its breakdown
+// values.
+type groupDelta struct {
+	Breakdowns   map[string]any       `json:"breakdowns"`
+	Calculations map[string]calcDelta `json:"calculations"`
+	Regressed    []string             `json:"regressed,omitempty"`
+}
+
+// calcDelta is the change of a calculation between windows. Values missing
+// from either window leave the change nil, as does a zero baseline for the
+// percent change.
+type calcDelta struct {
+	Baseline *float64 `json:"baseline"`
+	Current  *float64 `json:"current"`
+	Delta    *float64 `json:"delta"`
+	Percent  *float64 `json:"percent"`
+}
+
+func runQueryCompare(ctx context.Context, opts *options.RootOptions, o compareOptions) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	spec := o.spec
+	if spec == nil && o.file != "" {
+		if spec, err = command.ReadDefinitionFile(opts.IOStreams, o.file); err != nil {
+			return err
+		}
+	}
+
+	run := func(name string, w timeWindow) (*api.QueryResultDetails, error) {
+		var queryID string
+		var err error
+		if spec != nil {
+			queryID, err = createQuery(ctx, client, o.dataset, spec, w.overrides())
+		} else {
+			queryID, err = queryIDFromAnnotation(ctx, client, o.dataset, o.annotation, w.overrides())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s query: %w", name, err)
+		}
+		details, err := RunQueryResult(ctx, client, o.dataset, queryID, false, poll.Config{
+			Title:       fmt.Sprintf("Running %s query...", name),
+			Interactive: opts.IOStreams.CanPrompt(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s query: %w", name, err)
+		}
+		return details, nil
+	}
+
+	baseline, err := run("baseline", o.baseline)
+	if err != nil {
+		return err
+	}
+	current, err := run("current", o.current)
+	if err != nil {
+		return err
+	}
+
+	c := compareResults(baseline, current, o.threshold, o.direction)
+	c.Baseline = windowBounds{StartTime: o.baseline.start.Time, EndTime: o.baseline.end.Time}
+	c.Current = windowBounds{StartTime: o.current.start.Time, EndTime: o.current.end.Time}
+
+	if current.Links != nil && current.Links.QueryUrl != nil {
+		_, _ = fmt.Fprintf(opts.IOStreams.Err, "%s\n", *current.Links.QueryUrl)
+	}
+	if err := opts.OutputWriter().WriteDynamic(c, buildCompareTable(c)); err != nil {
+		return err
+	}
+
+	if c.Regressions > 0 {
+		return fmt.Errorf("%d of %d groups regressed by more than %g%%", c.Regressions, len(c.Groups), *o.threshold)
+	}
+	return nil
+}
+
+// compareResults joins the results of two runs of a query on their breakdown
+// values, in the order of the current results followed by groups only in the
+// baseline.
+func compareResults(baseline, current *api.QueryResultDetails, threshold *float64, direction string) comparison {
+	breakdowns, calcs := resultColumns(current)
+	c := comparison{
+		Threshold:  threshold,
+		Breakdowns: breakdowns,
+		Calcs:      calcs,
+		Groups:     []groupDelta{},
+	}
+	if threshold != nil {
+		c.Direction = direction
+	}
+
+	type pair struct {
+		breakdowns     map[string]any
+		baseline, curr map[string]any
+	}
+	var order []string
+	groups := map[string]*pair{}
+	add := func(details *api.QueryResultDetails, set func(*pair, map[string]any)) {
+		if details.Data == nil || details.Data.Results == nil {
+			return
+		}
+		for _, r := range *details.Data.Results {
+			if r.Data == nil {
+				continue
+			}
+			key := groupKey(*r.Data, breakdowns)
+			p, ok := groups[key]
+			if !ok {
+				p = &pair{breakdowns: map[string]any{}}
+				for _, b := range breakdowns {
+					p.breakdowns[b] = (*r.Data)[b]
+				}
+				groups[key] = p
+				order = append(order, key)
+			}
+			set(p, *r.Data)
+		}
+	}
+	add(current, func(p *pair, data map[string]any) { p.curr = data })
+	add(baseline, func(p *pair, data map[string]any) { p.baseline = data })
+
+	for _, key := range order {
+		p := groups[key]
+		g := groupDelta{Breakdowns: p.breakdowns, Calculations: map[string]calcDelta{}}
+		for _, calc := range calcs {
+			d := newCalcDelta(numericValue(p.baseline, calc), numericValue(p.curr, calc))
+			if p.curr == nil {
+				d = newDroppedDelta(d.Baseline)
+			}
+			g.Calculations[calc] = d
+			if threshold != nil && d.regressed(*threshold, direction) {
+				g.Regressed = append(g.Regressed, calc)
+			}
+		}
+		if len(g.Regressed) > 0 {
+			c.Regressions++
+		}
+		c.Groups = append(c.Groups, g)
+	}
+	return c
+}
+
+// groupKey encodes the breakdown values of a result, which are compared as
+// JSON so that numbers and strings with the same text stay distinct.
+func groupKey(data map[string]any, breakdowns []string) string {
+	values := make([]any, len(breakdowns))
+	for i, b := range breakdowns {
+		values[i] = data[b]
+	}
+	key, _ := json.Marshal(values)
+	return string(key)
+}
+
+func numericValue(data map[string]any, name string) *float64 {
+	var v float64
+	switch n := data[name].(type) {
+	case float64:
+		v = n
+	case int:
+		v = float64(n)
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return nil
+		}
+		v = f
+	default:
+		return nil
+	}
+	return &v
+}
+
+func newCalcDelta(baseline, current *float64) calcDelta {
+	d := calcDelta{Baseline: baseline, Current: current}
+	if baseline == nil || current == nil {
+		return d
+	}
+	delta := *current - *baseline
+	d.Delta = &delta
+	if *baseline != 0 {
+		pct := delta / math.Abs(*baseline) * 100
+		d.Percent = &pct
+	}
+	return d
+}
+
+// newDroppedDelta returns the change of a calculation whose group is missing
+// from the current window, which counts as a 100% decrease: a route whose
+// throughput drops to nothing has no result at all.
+func newDroppedDelta(baseline *float64) calcDelta {
+	d := newCalcDelta(baseline, ptr(0.0))
+	d.Current = nil
+	return d
+}
+
+func (d calcDelta) regressed(threshold float64, direction string) bool {
+	if d.Delta == nil || *d.Delta == 0 {
+		return false
+	}
+	increase := *d.Delta > 0
+	switch direction {
+	case directionIncrease:
+		if !increase {
+			return false
+		}
+	case directionDecrease:
+		if increase {
+			return false
+		}
+	}
+	if d.Percent == nil {
+		return true
+	}
+	return math.Abs(*d.Percent) > threshold
+}
+
+func buildCompareTable(c comparison) output.DynamicTableDef {
+	headers := append([]string{}, c.Breakdowns...)
+	for _, calc := range c.Calcs {
+		headers = append(headers, calc+" Baseline", calc+" Current", calc+" Change", calc+" Change %")
+	}
+	if c.Threshold != nil {
+		headers = append(headers, "Regressed")
+	}
+
+	rows := make([][]string, 0, len(c.Groups))
+	for _, g := range c.Groups {
+		row := make([]string, 0, len(headers))
+		for _, b := range c.Breakdowns {
+			if v := g.Breakdowns[b]; v != nil {
+				row = append(row, fmt.Sprintf("%v", v))
+			} else {
+				row = append(row, "")
+			}
+		}
+		for _, calc := range c.Calcs {
+			d := g.Calculations[calc]
+			row = append(row,
+				formatCompareValue(d.Baseline, ""),
+				formatCompareValue(d.Current, ""),
+				formatCompareValue(d.Delta, "+"),
+				formatComparePercent(d.Percent),
+			)
+		}
+		if c.Threshold != nil {
+			row = append(row, strings.Join(g.Regressed, ", "))
+		}
+		rows = append(rows, row)
+	}
+
+	return output.DynamicTableDef{Headers: headers, Rows: rows}
+}
+
+func formatCompareValue(v *float64, plus string) string {
+	if v == nil {
+		return "-"
+	}
+	// Rounding hides the float noise of subtracting values such as 0.1 and 0.3.
+	s := strconv.FormatFloat(math.Round(*v*1e6)/1e6, 'f', -1, 64)
+	if *v > 0 {
+		s = plus + s
+	}
+	return s
+}
+
+func formatComparePercent(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", *v)
+}
@@ -0,0 +1,237 @@
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+// compareHandler answers the query created for the window starting at
+// baseline with the baseline results, and any other with the current ones.
+func compareHandler(t *testing.T, baseline time.Time, baseResults, currResults string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/1/queries/test-dataset":
+			var spec map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&spec)
+			if _, ok := spec["time_range"]; ok {
+				t.Errorf("spec has time_range alongside the window: %v", spec)
+			}
+			id := "qry-curr"
+			if spec["start_time"] == float64(baseline.Unix()) {
+				id = "qry-base"
+			}
+			_, _ = w.Write([]byte(`{"id": "` + id + `"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/test-dataset":
+			var req api.CreateQueryResultRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "` + strings.Replace(*req.QueryId, "qry", "res", 1) + `"}`))
+		case strings.HasPrefix(r.URL.Path, "/1/query_results/test-dataset/"):
+			results := currResults
+			if strings.HasSuffix(r.URL.Path, "/res-base") {
+				results = baseResults
+			}
+			_, _ = w.Write([]byte(`{
+				"complete": true,
+				"data": {"results": ` + results + `},
+				"query": {"breakdowns": ["route"], "calculations": [{"op": "COUNT"}, {"op": "P99", "column": "duration_ms"}]}
+			}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+const (
+	compareBaseline = `[
+		{"data": {"route": "/a", "COUNT": 100, "P99(duration_ms)": 200}},
+		{"data": {"route": "/b", "COUNT": 50, "P99(duration_ms)": 100}},
+		{"data": {"route": "/gone", "COUNT": 5, "P99(duration_ms)": 10}}
+	]`
+	compareCurrent = `[
+		{"data": {"route": "/a", "COUNT": 90, "P99(duration_ms)": 260}},
+		{"data": {"route": "/b", "COUNT": 50, "P99(duration_ms)": 105}},
+		{"data": {"route": "/new", "COUNT": 1, "P99(duration_ms)": 3}}
+	]`
+)
+
+var compareWindows = []string{
+	"--baseline", "2025-01-02T13:00:00Z..2025-01-02T14:00:00Z",
+	"--current", "2025-01-02T14:00:00Z..2025-01-02T15:00:00Z",
+}
+
+func TestCompare(t *testing.T) {
+	baseline := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
+	opts, ts := setupTest(t, compareHandler(t, baseline, compareBaseline, compareCurrent))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs(append([]string{"compare", "--dataset", "test-dataset", "--calc", "COUNT", "--calc", "P99(duration_ms)", "--breakdown", "route"}, compareWindows...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got comparison
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if !got.Baseline.StartTime.Equal(baseline) {
+		t.Errorf("baseline start = %s, want %s", got.Baseline.StartTime, baseline)
+	}
+	if got.Threshold != nil || got.Regressions != 0 {
+		t.Errorf("threshold = %v, regressions = %d; want none without --threshold", got.Threshold, got.Regressions)
+	}
+
+	var routes []string
+	for _, g := range got.Groups {
+		routes = append(routes, g.Breakdowns["route"].(string))
+	}
+	if want := "/a,/b,/new,/gone"; strings.Join(routes, ",") != want {
+		t.Errorf("groups = %v, want %s", routes, want)
+	}
+
+	p99 := got.Groups[0].Calculations["P99(duration_ms)"]
+	if *p99.Delta != 60 || *p99.Percent != 30 {
+		t.Errorf("/a P99 delta = %v (%v%%), want 60 (30%%)", *p99.Delta, *p99.Percent)
+	}
+	if gone := got.Groups[3].Calculations["COUNT"]; gone.Current != nil || *gone.Baseline != 5 || *gone.Delta != -5 || *gone.Percent != -100 {
+		t.Errorf("/gone COUNT = %+v, want a 100%% decrease from 5", gone)
+	}
+	if added := got.Groups[2].Calculations["COUNT"]; added.Baseline != nil || added.Delta != nil {
+		t.Errorf("/new COUNT = %+v, want only a current value", added)
+	}
+}
+
+func TestCompare_Threshold(t *testing.T) {
+	baseline := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name      string
+		direction string
+		threshold string
+		want      string
+		regressed map[string][]string
+	}{
+		{
+			name:      "increase",
+			threshold: "10",
+			want:      "1 of 4 groups regressed by more than 10%",
+			regressed: map[string][]string{"/a": {"P99(duration_ms)"}},
+		},
+		{
+			name:      "decrease",
+			direction: "decrease",
+			threshold: "5",
+			want:      "2 of 4 groups regressed by more than 5%",
+			regressed: map[string][]string{"/a": {"COUNT"}, "/gone": {"COUNT", "P99(duration_ms)"}},
+		},
+		{
+			name:      "any",
+			direction: "any",
+			threshold: "1",
+			want:      "3 of 4 groups regressed by more than 1%",
+			regressed: map[string][]string{"/a": {"COUNT", "P99(duration_ms)"}, "/b": {"P99(duration_ms)"}, "/gone": {"COUNT", "P99(duration_ms)"}},
+		},
+		{
+			name:      "within threshold",
+			threshold: "50",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, ts := setupTest(t, compareHandler(t, baseline, compareBaseline, compareCurrent))
+
+			args := append([]string{"compare", "--dataset", "test-dataset", "--calc", "COUNT", "--threshold", tc.threshold}, compareWindows...)
+			if tc.direction != "" {
+				args = append(args, "--direction", tc.direction)
+			}
+			cmd := NewCmd(opts)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			if tc.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if err == nil || err.Error() != tc.want {
+				t.Fatalf("err = %v, want %q", err, tc.want)
+			}
+
+			var got comparison
+			if err := json.Unmarshal(ts.OutBuf.Bytes(), &got); err != nil {
+				t.Fatalf("unmarshal output: %v", err)
+			}
+			for _, g := range got.Groups {
+				route := g.Breakdowns["route"].(string)
+				if strings.Join(g.Regressed, ",") != strings.Join(tc.regressed[route], ",") {
+					t.Errorf("%s regressed = %v, want %v", route, g.Regressed, tc.regressed[route])
+				}
+			}
+		})
+	}
+}
+
+func TestCompare_Table(t *testing.T) {
+	baseline := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
+	opts, ts := setupTest(t, compareHandler(t, baseline, compareBaseline, compareCurrent))
+	opts.Format = "csv"
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs(append([]string{"compare", "--dataset", "test-dataset", "--calc", "COUNT", "--threshold", "100"}, compareWindows...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(ts.OutBuf.String()), "\n")
+	for _, want := range []string{
+		"route,COUNT Baseline,COUNT Current,COUNT Change,COUNT Change %,P99(duration_ms) Baseline,P99(duration_ms) Current,P99(duration_ms) Change,P99(duration_ms) Change %,Regressed",
+		"/a,100,90,-10,-10.0%,200,260,+60,+30.0%,",
+		"/new,-,1,-,-,-,3,-,-,",
+		"/gone,5,-,-5,-100.0%,10,-,-10,-100.0%,",
+	} {
+		found := false
+		for _, line := range lines {
+			if line == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("output missing line %q:\n%s", want, ts.OutBuf.String())
+		}
+	}
+}
+
+func TestCompare_FromZero(t *testing.T) {
+	d := newCalcDelta(ptr(0.0), ptr(3.0))
+	if d.Percent != nil || !d.regressed(1000, directionIncrease) || d.regressed(0, directionDecrease) {
+		t.Errorf("delta from zero = %+v, want an increase with no percent", d)
+	}
+}
+
+func TestTimeWindow(t *testing.T) {
+	for _, tc := range []struct {
+		value string
+		want  string
+	}{
+		{"-1h", "expected START..END"},
+		{"-1h..-2h", "end must be after start"},
+		{"yesterday..now", `invalid time "yesterday"`},
+	} {
+		var w timeWindow
+		if err := w.Set(tc.value); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Set(%q) = %v, want containing %q", tc.value, err, tc.want)
+		}
+	}
+
+	var w timeWindow
+	if err := w.Set("-2h..now"); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.end.Sub(w.start.Time); got != 2*time.Hour {
+		t.Errorf("window = %s, want 2h", got)
+	}
+}
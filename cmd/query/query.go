@@ -24,7 +24,9 @@ func NewCmd(opts *options.RootOptions) *cobra.Command {
 	_ = cmd.MarkPersistentFlagRequired("dataset")
 
 	cmd.AddCommand(NewRunCmd(opts, &dataset))
+	cmd.AddCommand(NewCompareCmd(opts, &dataset))
 	cmd.AddCommand(NewAnnotationCmd(opts, &dataset))
+	cmd.AddCommand(NewCacheCmd(opts))
 
 	return command.Group(cmd)
 }
@@ -3,7 +3,9 @@ package query
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
@@ -11,6 +13,7 @@ import (
 	"github.com/bendrucker/honeycomb-cli/internal/output"
 	"github.com/bendrucker/honeycomb-cli/internal/poll"
 	"github.com/bendrucker/honeycomb-cli/internal/prompt"
+	"github.com/bendrucker/honeycomb-cli/internal/querycache"
 	"github.com/spf13/cobra"
 )
 
@@ -18,11 +21,32 @@ func NewRunCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
 	var (
 		file       string
 		annotation string
+		times      timeOverrides
+		builder    builderFlags
+		dryRun     bool
+		series     string
+		noCache    bool
+		cacheTTL   = command.Duration{Duration: querycache.DefaultTTL}
+		fromCache  string
 	)
 
 	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run a query",
+		Long: "Run a query from a spec file, a saved query annotation, or builder flags.\n\n" +
+			"Builder flags assemble a query spec inline: --calc takes OP or OP(column), " +
+			"--where takes 'column op value' (values for in and not-in are comma-separated), " +
+			"and --order takes a breakdown or calculation, prefixed with - to sort descending. " +
+			"Use --dry-run to print the spec instead of running it.\n\n" +
+			"With --series, the time series is fetched along with the results and printed " +
+			"as a long-format table with one row per time bucket and group. --series=csv " +
+			"writes the same rows as CSV, and --series=sparkline draws a sparkline per group " +
+			"and calculation.\n\n" +
+			"Results of queries run from a spec file or builder flags are cached under the " +
+			"configuration directory, keyed by dataset, spec, and time window, and a cached " +
+			"result newer than --cache-ttl answers the same query again without the API. " +
+			"--from-cache renders a past result by the ID query cache list shows, in any " +
+			"output format and without the network.",
 		Example: `  # Run a query from a spec file
   honeycomb query run --dataset my-dataset --file query.json
 
@@ -30,52 +54,287 @@ func NewRunCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
   echo '{"calculations":[{"op":"COUNT"}]}' | \
     honeycomb query run --dataset my-dataset --file -
 
-  # Re-run a saved query annotation
-  honeycomb query run --dataset my-dataset --annotation q-abc`,
+  # Build a query inline
+  honeycomb query run --dataset my-dataset \
+    --calc 'P99(duration_ms)' --calc COUNT --where 'service.name = api' \
+    --breakdown http.route --order '-P99(duration_ms)' --limit 20 --time-range 2h
+
+  # Print the spec a set of builder flags produces
+  honeycomb query run --dataset my-dataset --calc COUNT --breakdown service.name --dry-run
+
+  # Chart p99 latency per route over the last day
+  honeycomb query run --dataset my-dataset --calc 'P99(duration_ms)' \
+    --breakdown http.route --time-range 1d --series=sparkline
+
+  # Re-run a saved query annotation over the last day
+  honeycomb query run --dataset my-dataset --annotation q-abc --time-range 1d
+
+  # Run a query over an absolute window
+  honeycomb query run --dataset my-dataset --file query.json \
+    --start-time 2024-01-02T15:00:00Z --end-time 2024-01-02T16:00:00Z
+
+  # Render a cached result again as CSV
+  honeycomb query run --dataset my-dataset --from-cache 3f2a9c1b7d4e --format csv`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return runQueryRun(cmd.Context(), opts, *dataset, file, annotation)
+			var spec []byte
+			if builder.isSet(cmd) {
+				q, err := builder.build()
+				if err != nil {
+					return err
+				}
+				if spec, err = json.Marshal(q); err != nil {
+					return fmt.Errorf("encoding query spec: %w", err)
+				}
+			}
+
+			if err := command.ValidateEnum("series", series, seriesStyles); err != nil {
+				return err
+			}
+
+			if dryRun {
+				return runQueryDryRun(opts, file, spec, times)
+			}
+			if fromCache != "" {
+				return runQueryFromCache(opts, *dataset, fromCache, series)
+			}
+
+			o := runOptions{
+				dataset:    *dataset,
+				file:       file,
+				annotation: annotation,
+				spec:       spec,
+				times:      times,
+				series:     series,
+				cacheTTL:   cacheTTL.Duration,
+			}
+			if !noCache {
+				o.cache = querycache.New(querycache.DefaultDir())
+			}
+			return runQueryRun(cmd.Context(), opts, o)
 		},
 	}
 
 	cmd.Flags().StringVarP(&file, "file", "f", "", "Path to query spec JSON file (- for stdin)")
 	cmd.Flags().StringVarP(&annotation, "annotation", "a", "", "Annotation ID to re-run")
+	cmd.Flags().Var(&times.start, "start-time", "Override the query start time: "+command.TimeFormats)
+	cmd.Flags().Var(&times.end, "end-time", "Override the query end time: "+command.TimeFormats)
+	cmd.Flags().Var(&times.timeRange, "time-range", "Override the query time range, e.g. 2h or 7d")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the query spec as JSON instead of running it")
+	cmd.Flags().StringVar(&series, "series", "", "Include the time series, rendered as "+command.EnumUsage(seriesStyles))
+	cmd.Flags().Lookup("series").NoOptDefVal = seriesTable
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Run the query without using or updating the result cache")
+	cmd.Flags().Var(&cacheTTL, "cache-ttl", "How old a cached result can be to answer the query")
+	cmd.Flags().StringVar(&fromCache, "from-cache", "", "Render the cached result with this ID instead of running a query")
 	cmd.MarkFlagsMutuallyExclusive("file", "annotation")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "annotation")
+	cmd.MarkFlagsMutuallyExclusive("no-cache", "cache-ttl")
+	for _, name := range []string{"file", "annotation", "dry-run", "no-cache", "cache-ttl", "start-time", "end-time", "time-range"} {
+		cmd.MarkFlagsMutuallyExclusive("from-cache", name)
+	}
+	builder.register(cmd)
 
 	return cmd
 }
 
-func runQueryRun(ctx context.Context, opts *options.RootOptions, dataset, file, annotation string) error {
+// runQueryDryRun prints the spec that would be created, without calling the
+// API. spec is the builder output, if any; otherwise it is read from file.
+func runQueryDryRun(opts *options.RootOptions, file string, spec []byte, times timeOverrides) error {
+	if spec == nil {
+		if file == "" {
+			return fmt.Errorf("--dry-run requires --file or builder flags")
+		}
+		var err error
+		if spec, err = command.ReadDefinitionFile(opts.IOStreams, file); err != nil {
+			return err
+		}
+	}
+
+	spec, err := times.apply(spec)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, spec, "", "  "); err != nil {
+		return fmt.Errorf("formatting query spec: %w", err)
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(opts.IOStreams.Out)
+	return err
+}
+
+type runOptions struct {
+	dataset    string
+	file       string
+	annotation string
+	// spec is a query spec assembled from builder flags, used instead of
+	// file or annotation when set.
+	spec   []byte
+	times  timeOverrides
+	series string
+	// cache holds results of queries run from a spec; nil with --no-cache.
+	cache    *querycache.Cache
+	cacheTTL time.Duration
+}
+
+func runQueryRun(ctx context.Context, opts *options.RootOptions, o runOptions) error {
+	dataset := o.dataset
+
 	client, err := opts.ClientFor(nil, options.AuthConfig)
 	if err != nil {
 		return err
 	}
 
-	queryID, err := resolveQueryID(ctx, opts, client, dataset, file, annotation)
+	spec := o.spec
+	if spec == nil && o.file != "" {
+		if spec, err = command.ReadDefinitionFile(opts.IOStreams, o.file); err != nil {
+			return err
+		}
+	}
+
+	var details *api.QueryResultDetails
+	if spec != nil {
+		details, err = runQuerySpec(ctx, opts, client, o, spec)
+	} else {
+		var queryID string
+		if queryID, err = resolveQueryID(ctx, opts, client, dataset, o.annotation, o.times); err != nil {
+			return err
+		}
+		details, err = RunQueryResult(ctx, client, dataset, queryID, o.series != "", runPollConfig(opts))
+	}
 	if err != nil {
 		return err
 	}
 
+	return writeQueryResult(opts, details, o.series)
+}
+
+// writeQueryResult prints the query URL to stderr and writes the results, or
+// the series in the style --series names.
+func writeQueryResult(opts *options.RootOptions, details *api.QueryResultDetails, series string) error {
+	if details.Links != nil && details.Links.QueryUrl != nil {
+		_, _ = fmt.Fprintf(opts.IOStreams.Err, "%s\n", *details.Links.QueryUrl)
+	}
+
+	switch series {
+	case "":
+		return opts.OutputWriter().WriteDynamic(details, BuildResultTable(details))
+	case seriesCSV:
+		return writeSeriesCSV(opts.IOStreams.Out, buildSeriesTable(details))
+	case seriesSparkline:
+		return output.New(opts.IOStreams.Out, output.FormatTable).WriteDynamic(details, BuildSparklineTable(details))
+	default:
+		return opts.OutputWriter().WriteDynamic(details, buildSeriesTable(details))
+	}
+}
+
+// runQuerySpec runs a query spec, with any time overrides applied. A result
+// of the same query cached within the TTL is used instead of the API, and a
+// new result is cached.
+func runQuerySpec(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, o runOptions, spec []byte) (*api.QueryResultDetails, error) {
+	spec, err := o.times.apply(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	q := querycache.Query{
+		Profile: opts.ActiveProfile(),
+		APIUrl:  opts.ResolveAPIUrl(),
+		Dataset: o.dataset,
+		Spec:    spec,
+		Series:  o.series != "",
+	}
+	if o.cache != nil {
+		entry, err := o.cache.Get(q, o.cacheTTL, time.Now())
+		if err != nil {
+			_, _ = fmt.Fprintf(opts.IOStreams.Err, "warning: reading query cache: %v\n", err)
+		}
+		if entry != nil {
+			_, _ = fmt.Fprintf(opts.IOStreams.Err, "Using result %s cached %s ago. Use --no-cache to run the query again.\n",
+				entry.ID, entry.Age(time.Now()).Round(time.Second))
+			return decodeCachedResult(entry)
+		}
+	}
+
+	queryID, err := createQuery(ctx, client, o.dataset, spec, timeOverrides{})
+	if err != nil {
+		return nil, err
+	}
+	details, err := RunQueryResult(ctx, client, o.dataset, queryID, o.series != "", runPollConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	if o.cache != nil {
+		result, err := json.Marshal(details)
+		if err == nil {
+			_, err = o.cache.Put(q, result, time.Now())
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(opts.IOStreams.Err, "warning: caching query result: %v\n", err)
+		}
+	}
+	return details, nil
+}
+
+// runQueryFromCache renders a cached result without calling the API.
+func runQueryFromCache(opts *options.RootOptions, dataset, id, series string) error {
+	entry, err := querycache.New(querycache.DefaultDir()).Load(id)
+	if err != nil {
+		return err
+	}
+	if entry.Dataset != dataset {
+		return fmt.Errorf("cached result %s is for dataset %s, not %s", id, entry.Dataset, dataset)
+	}
+	if profile := opts.ActiveProfile(); entry.Profile != "" && entry.Profile != profile {
+		return fmt.Errorf("cached result %s is for profile %s, not %s", id, entry.Profile, profile)
+	}
+	if series != "" && !entry.Series {
+		return fmt.Errorf("cached result %s has no time series; run the query with --series to cache one", id)
+	}
+
+	details, err := decodeCachedResult(entry)
+	if err != nil {
+		return err
+	}
+	return writeQueryResult(opts, details, series)
+}
+
+func decodeCachedResult(entry *querycache.Entry) (*api.QueryResultDetails, error) {
+	var details api.QueryResultDetails
+	if err := json.Unmarshal(entry.Result, &details); err != nil {
+		return nil, fmt.Errorf("parsing cached result %s: %w", entry.ID, err)
+	}
+	return &details, nil
+}
+
+func runPollConfig(opts *options.RootOptions) poll.Config {
+	return poll.Config{
+		Title:       "Running query...",
+		Interactive: opts.IOStreams.CanPrompt(),
+	}
+}
+
+// RunQueryResult runs a saved query and polls until its result is complete.
+// The time series is included only when series is set.
+func RunQueryResult(ctx context.Context, client *api.ClientWithResponses, dataset, queryID string, series bool, cfg poll.Config) (*api.QueryResultDetails, error) {
 	resultResp, err := client.CreateQueryResultWithResponse(ctx, dataset, api.CreateQueryResultRequest{
 		QueryId:       &queryID,
-		DisableSeries: ptr(true),
+		DisableSeries: ptr(!series),
 	})
 	if err != nil {
-		return fmt.Errorf("creating query result: %w", err)
+		return nil, fmt.Errorf("creating query result: %w", err)
 	}
 	result, err := api.Decode(resultResp.StatusCode(), resultResp.Status(), resultResp.Body, resultResp.JSON201)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if result.Id == nil {
-		return fmt.Errorf("query result ID missing from response")
+		return nil, fmt.Errorf("query result ID missing from response")
 	}
 	resultID := *result.Id
 
-	cfg := poll.Config{
-		Title:       "Running query...",
-		Interactive: opts.IOStreams.CanPrompt(),
-	}
-	details, err := poll.Poll(ctx, cfg, func(ctx context.Context) (*api.QueryResultDetails, bool, error) {
+	return poll.Poll(ctx, cfg, func(ctx context.Context) (*api.QueryResultDetails, bool, error) {
 		resp, err := client.GetQueryResultWithResponse(ctx, dataset, resultID)
 		if err != nil {
 			return nil, false, fmt.Errorf("getting query result: %w", err)
@@ -87,37 +346,41 @@ func runQueryRun(ctx context.Context, opts *options.RootOptions, dataset, file,
 		complete := result.Complete != nil && *result.Complete
 		return result, complete, nil
 	})
-	if err != nil {
-		return err
-	}
-
-	if details.Links != nil && details.Links.QueryUrl != nil {
-		_, _ = fmt.Fprintf(opts.IOStreams.Err, "%s\n", *details.Links.QueryUrl)
-	}
-
-	return opts.OutputWriter().WriteDynamic(details, buildResultTable(details))
 }
 
-func resolveQueryID(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, dataset string, file, annotation string) (string, error) {
+func resolveQueryID(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, dataset string, annotation string, times timeOverrides) (string, error) {
 	switch {
-	case file != "":
-		return createQueryFromFile(ctx, opts, client, dataset, file)
 	case annotation != "":
-		return queryIDFromAnnotation(ctx, client, dataset, annotation)
+		return queryIDFromAnnotation(ctx, client, dataset, annotation, times)
 	case opts.IOStreams.CanPrompt():
-		return promptQueryID(ctx, opts, client, dataset)
+		return promptQueryID(ctx, opts, client, dataset, times)
 	default:
-		return "", fmt.Errorf("either --file or --annotation is required")
+		return "", fmt.Errorf("either --file, --annotation, or builder flags such as --calc are required")
 	}
 }
 
-func createQueryFromFile(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, dataset string, file string) (string, error) {
+func createQueryFromFile(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, dataset string, file string, times timeOverrides) (string, error) {
 	data, err := command.ReadDefinitionFile(opts.IOStreams, file)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := client.CreateQueryWithBodyWithResponse(ctx, dataset, "application/json", bytes.NewReader(data))
+	return createQuery(ctx, client, dataset, data, times)
+}
+
+// createQuery creates a query from a JSON spec, applying any time overrides,
+// and returns its ID.
+func createQuery(ctx context.Context, client *api.ClientWithResponses, dataset string, spec []byte, times timeOverrides) (string, error) {
+	spec, err := times.apply(spec)
+	if err != nil {
+		return "", err
+	}
+	return CreateQuery(ctx, client, dataset, spec)
+}
+
+// CreateQuery creates a query from a JSON spec and returns its ID.
+func CreateQuery(ctx context.Context, client *api.ClientWithResponses, dataset string, spec []byte) (string, error) {
+	resp, err := client.CreateQueryWithBodyWithResponse(ctx, dataset, "application/json", bytes.NewReader(spec))
 	if err != nil {
 		return "", fmt.Errorf("creating query: %w", err)
 	}
@@ -131,7 +394,7 @@ func createQueryFromFile(ctx context.Context, opts *options.RootOptions, client
 	return *query.Id, nil
 }
 
-func queryIDFromAnnotation(ctx context.Context, client *api.ClientWithResponses, dataset string, annotationID string) (string, error) {
+func queryIDFromAnnotation(ctx context.Context, client *api.ClientWithResponses, dataset string, annotationID string, times timeOverrides) (string, error) {
 	resp, err := client.GetQueryAnnotationWithResponse(ctx, dataset, annotationID)
 	if err != nil {
 		return "", fmt.Errorf("getting query annotation: %w", err)
@@ -140,10 +403,28 @@ func queryIDFromAnnotation(ctx context.Context, client *api.ClientWithResponses,
 	if err != nil {
 		return "", err
 	}
-	return annotation.QueryId, nil
+	if !times.isSet() {
+		return annotation.QueryId, nil
+	}
+
+	// Saved queries are immutable, so re-running one over a different window
+	// creates a copy of its spec with the new time bounds.
+	queryResp, err := client.GetQueryWithResponse(ctx, dataset, annotation.QueryId)
+	if err != nil {
+		return "", fmt.Errorf("getting query: %w", err)
+	}
+	query, err := api.Decode(queryResp.StatusCode(), queryResp.Status(), queryResp.Body, queryResp.JSON200)
+	if err != nil {
+		return "", err
+	}
+	spec, err := api.MarshalStrippingReadOnly(query, "Query")
+	if err != nil {
+		return "", fmt.Errorf("encoding query: %w", err)
+	}
+	return createQuery(ctx, client, dataset, spec, times)
 }
 
-func promptQueryID(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, dataset string) (string, error) {
+func promptQueryID(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, dataset string, times timeOverrides) (string, error) {
 	mode, err := prompt.Choice(opts.IOStreams.Out, opts.IOStreams.In, "Query source (file, annotation): ", []string{"file", "annotation"})
 	if err != nil {
 		return "", err
@@ -155,34 +436,44 @@ func promptQueryID(ctx context.Context, opts *options.RootOptions, client *api.C
 		if err != nil {
 			return "", err
 		}
-		return createQueryFromFile(ctx, opts, client, dataset, path)
+		return createQueryFromFile(ctx, opts, client, dataset, path, times)
 	case "annotation":
 		id, err := prompt.Line(opts.IOStreams.Out, opts.IOStreams.In, "Annotation ID: ")
 		if err != nil {
 			return "", err
 		}
-		return queryIDFromAnnotation(ctx, client, dataset, id)
+		return queryIDFromAnnotation(ctx, client, dataset, id, times)
 	default:
 		return "", fmt.Errorf("unexpected mode: %s", mode)
 	}
 }
 
-func buildResultTable(details *api.QueryResultDetails) output.DynamicTableDef {
-	var headers []string
-	if details.Query != nil {
-		if details.Query.Breakdowns != nil {
-			headers = append(headers, *details.Query.Breakdowns...)
-		}
-		if details.Query.Calculations != nil {
-			for _, calc := range *details.Query.Calculations {
-				var col string
-				if calc.Column.IsSpecified() && !calc.Column.IsNull() {
-					col = calc.Column.MustGet()
-				}
-				headers = append(headers, calcColumnName(string(calc.Op), col))
+// resultColumns returns the breakdown and calculation column names that key
+// each result and series entry.
+func resultColumns(details *api.QueryResultDetails) (breakdowns, calcs []string) {
+	if details.Query == nil {
+		return nil, nil
+	}
+	if details.Query.Breakdowns != nil {
+		breakdowns = *details.Query.Breakdowns
+	}
+	if details.Query.Calculations != nil {
+		for _, calc := range *details.Query.Calculations {
+			var col string
+			if calc.Column.IsSpecified() && !calc.Column.IsNull() {
+				col = calc.Column.MustGet()
 			}
+			calcs = append(calcs, calcColumnName(string(calc.Op), col))
 		}
 	}
+	return breakdowns, calcs
+}
+
+// BuildResultTable lays out a query result with a column for each breakdown
+// and calculation.
+func BuildResultTable(details *api.QueryResultDetails) output.DynamicTableDef {
+	breakdowns, calcs := resultColumns(details)
+	headers := append(append([]string{}, breakdowns...), calcs...)
 
 	var rows [][]string
 	if details.Data != nil && details.Data.Results != nil {
@@ -212,3 +503,51 @@ func calcColumnName(op, col string) string {
 	}
 	return fmt.Sprintf("%s(%s)", op, col)
 }
+
+// timeOverrides replaces the time bounds of a query spec from flags.
+type timeOverrides struct {
+	start     command.Time
+	end       command.Time
+	timeRange command.Duration
+}
+
+func (t timeOverrides) isSet() bool {
+	return !t.start.IsZero() || !t.end.IsZero() || t.timeRange.Duration > 0
+}
+
+// apply rewrites the time fields of a JSON query spec. Any override replaces
+// all of the spec's time fields, so a spec written with an absolute window can
+// be re-run with only --time-range.
+func (t timeOverrides) apply(spec []byte) ([]byte, error) {
+	if !t.isSet() {
+		return spec, nil
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(spec, &m); err != nil {
+		return nil, fmt.Errorf("parsing query spec: %w", err)
+	}
+	delete(m, "start_time")
+	delete(m, "end_time")
+	delete(m, "time_range")
+
+	if !t.start.IsZero() {
+		m["start_time"] = t.start.UnixSeconds()
+	}
+	if !t.end.IsZero() {
+		m["end_time"] = t.end.UnixSeconds()
+	}
+	if !t.start.IsZero() && !t.end.IsZero() {
+		if t.timeRange.Duration > 0 {
+			return nil, fmt.Errorf("--time-range cannot be combined with both --start-time and --end-time")
+		}
+		if !t.end.After(t.start.Time) {
+			return nil, fmt.Errorf("--end-time must be after --start-time")
+		}
+	}
+	if t.timeRange.Duration > 0 {
+		m["time_range"] = int(t.timeRange.Seconds())
+	}
+
+	return json.Marshal(m)
+}
@@ -186,7 +186,7 @@ func TestRun_NoInput_NonInteractive(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for missing input")
 	}
-	if !strings.Contains(err.Error(), "either --file or --annotation is required") {
+	if !strings.Contains(err.Error(), "either --file, --annotation, or builder flags") {
 		t.Errorf("error = %q, want missing input message", err.Error())
 	}
 }
@@ -204,3 +204,99 @@ func TestRun_MutuallyExclusive(t *testing.T) {
 		t.Fatal("expected error for mutually exclusive flags")
 	}
 }
+
+func TestRun_TimeOverrides(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		want map[string]any
+	}{
+		{
+			name: "time range replaces absolute window",
+			args: []string{"--time-range", "1d"},
+			want: map[string]any{"time_range": float64(86400)},
+		},
+		{
+			name: "absolute window replaces time range",
+			args: []string{"--start-time", "2024-01-02T15:00:00Z", "--end-time", "1704211200"},
+			want: map[string]any{"start_time": float64(1704207600), "end_time": float64(1704211200)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var created map[string]any
+			opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/1/query_annotations/test-dataset/ann-1":
+					_ = json.NewEncoder(w).Encode(map[string]any{"id": "ann-1", "name": "My Query", "query_id": "qry-1"})
+
+				case r.Method == http.MethodGet && r.URL.Path == "/1/queries/test-dataset/qry-1":
+					_ = json.NewEncoder(w).Encode(map[string]any{
+						"id":           "qry-1",
+						"start_time":   1700000000,
+						"end_time":     1700003600,
+						"calculations": []map[string]any{{"op": "COUNT"}},
+					})
+
+				case r.Method == http.MethodPost && r.URL.Path == "/1/queries/test-dataset":
+					if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+						t.Errorf("decode request body: %v", err)
+					}
+					_ = json.NewEncoder(w).Encode(map[string]any{"id": "qry-2"})
+
+				case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/test-dataset":
+					var body api.CreateQueryResultRequest
+					if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+						t.Errorf("decode request body: %v", err)
+					}
+					if body.QueryId == nil || *body.QueryId != "qry-2" {
+						t.Errorf("query_id = %v, want %q", body.QueryId, "qry-2")
+					}
+					w.WriteHeader(http.StatusCreated)
+					_ = json.NewEncoder(w).Encode(map[string]any{"id": "result-1"})
+
+				case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/test-dataset/result-1":
+					_ = json.NewEncoder(w).Encode(map[string]any{"id": "result-1", "complete": true})
+
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			cmd := NewCmd(opts)
+			cmd.SetArgs(append([]string{"run", "--dataset", "test-dataset", "--annotation", "ann-1"}, tc.args...))
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := created["id"]; ok {
+				t.Error("read-only id should be stripped from the copied query")
+			}
+			for _, key := range []string{"start_time", "end_time", "time_range"} {
+				if got, want := created[key], tc.want[key]; got != want {
+					t.Errorf("%s = %v, want %v", key, got, want)
+				}
+			}
+			if calcs, _ := created["calculations"].([]any); len(calcs) != 1 {
+				t.Errorf("calculations = %v, want the original spec", created["calculations"])
+			}
+		})
+	}
+}
+
+func TestRun_TimeOverridesConflict(t *testing.T) {
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("unexpected API call")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	ts.InBuf.WriteString(`{"calculations": [{"op": "COUNT"}]}`)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--file", "-", "--start-time", "-2h", "--end-time", "-3h"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--end-time must be after --start-time") {
+		t.Fatalf("err = %v, want ordering error", err)
+	}
+}
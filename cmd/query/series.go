@@ -0,0 +1,210 @@
+package query
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/deref"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+)
+
+const (
+	seriesTable     = "table"
+	seriesCSV       = "csv"
+	seriesSparkline = "sparkline"
+)
+
+var seriesStyles = []string{seriesTable, seriesCSV, seriesSparkline}
+
+// sparkBlocks are the glyphs of a sparkline, from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// buildSeriesTable renders the series in long format: one row per time bucket
+// and breakdown group, with a column per calculation.
+func buildSeriesTable(details *api.QueryResultDetails) output.DynamicTableDef {
+	breakdowns, calcs := resultColumns(details)
+	headers := append(append([]string{"Time"}, breakdowns...), calcs...)
+
+	var rows [][]string
+	for _, s := range querySeries(details) {
+		row := make([]string, len(headers))
+		row[0] = deref.String(s.Time)
+		for i, h := range headers[1:] {
+			if val, ok := (*s.Data)[h]; ok && val != nil {
+				row[i+1] = fmt.Sprintf("%v", val)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return output.DynamicTableDef{
+		Headers: headers,
+		Rows:    rows,
+	}
+}
+
+func writeSeriesCSV(w io.Writer, td output.DynamicTableDef) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(td.Headers); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(td.Rows); err != nil {
+		return fmt.Errorf("writing CSV: %w", err)
+	}
+	return nil
+}
+
+// BuildSparklineTable draws one sparkline per breakdown group and
+// calculation. Groups appear in the order they first occur in the series.
+func BuildSparklineTable(details *api.QueryResultDetails) output.DynamicTableDef {
+	breakdowns, calcs := resultColumns(details)
+	headers := append(append([]string{}, breakdowns...), "Calculation", "Trend", "Min", "Max", "Last")
+
+	type group struct {
+		labels []string
+		values map[string][]*float64
+	}
+
+	var (
+		groups []*group
+		byKey  = map[string]*group{}
+		times  = map[string]int{}
+	)
+	for _, s := range querySeries(details) {
+		t := deref.String(s.Time)
+		idx, ok := times[t]
+		if !ok {
+			idx = len(times)
+			times[t] = idx
+		}
+
+		labels := make([]string, len(breakdowns))
+		for i, b := range breakdowns {
+			if val, ok := (*s.Data)[b]; ok && val != nil {
+				labels[i] = fmt.Sprintf("%v", val)
+			}
+		}
+		key := strings.Join(labels, "\x00")
+		g, ok := byKey[key]
+		if !ok {
+			g = &group{labels: labels, values: map[string][]*float64{}}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+
+		for _, c := range calcs {
+			v, ok := seriesNumber((*s.Data)[c])
+			if !ok {
+				continue
+			}
+			vals := g.values[c]
+			for len(vals) <= idx {
+				vals = append(vals, nil)
+			}
+			vals[idx] = &v
+			g.values[c] = vals
+		}
+	}
+
+	var rows [][]string
+	for _, g := range groups {
+		for _, c := range calcs {
+			vals := g.values[c]
+			for len(vals) < len(times) {
+				vals = append(vals, nil)
+			}
+			row := append(append([]string{}, g.labels...), c, sparkline(vals))
+			row = append(row, sparkStats(vals)...)
+			rows = append(rows, row)
+		}
+	}
+
+	return output.DynamicTableDef{
+		Headers: headers,
+		Rows:    rows,
+	}
+}
+
+// querySeries returns the series entries that carry data.
+func querySeries(details *api.QueryResultDetails) []api.QueryResultsSeries {
+	if details.Data == nil || details.Data.Series == nil {
+		return nil
+	}
+	var series []api.QueryResultsSeries
+	for _, s := range *details.Data.Series {
+		if s.Data != nil {
+			series = append(series, s)
+		}
+	}
+	return series
+}
+
+func seriesNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// sparkline scales values between their min and max. Missing buckets are
+// left blank so gaps remain visible.
+func sparkline(vals []*float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range vals {
+		if v != nil {
+			lo, hi = min(lo, *v), max(hi, *v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range vals {
+		switch {
+		case v == nil:
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			i := int((*v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+			b.WriteRune(sparkBlocks[i])
+		}
+	}
+	return b.String()
+}
+
+// sparkStats returns the min, max, and last value of a series.
+func sparkStats(vals []*float64) []string {
+	var (
+		lo, hi, last *float64
+	)
+	for _, v := range vals {
+		if v == nil {
+			continue
+		}
+		if lo == nil || *v < *lo {
+			lo = v
+		}
+		if hi == nil || *v > *hi {
+			hi = v
+		}
+		last = v
+	}
+
+	stats := make([]string, 3)
+	for i, v := range []*float64{lo, hi, last} {
+		if v != nil {
+			stats[i] = strconv.FormatFloat(*v, 'g', -1, 64)
+		}
+	}
+	return stats
+}
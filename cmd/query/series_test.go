@@ -0,0 +1,142 @@
+package query
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+func seriesHandler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/1/queries/test-dataset":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "qry-1"})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/1/query_results/test-dataset":
+			var body api.CreateQueryResultRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode request body: %v", err)
+			}
+			if body.DisableSeries == nil || *body.DisableSeries {
+				t.Errorf("disable_series = %v, want false", body.DisableSeries)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": "result-1"})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/1/query_results/test-dataset/result-1":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"complete": true,
+				"id":       "result-1",
+				"data": map[string]any{
+					"series": []map[string]any{
+						{"time": "2024-01-02T03:00:00Z", "data": map[string]any{"service.name": "api", "COUNT": 10}},
+						{"time": "2024-01-02T03:00:00Z", "data": map[string]any{"service.name": "web", "COUNT": 5}},
+						{"time": "2024-01-02T03:01:00Z", "data": map[string]any{"service.name": "api", "COUNT": 30}},
+						{"time": "2024-01-02T03:02:00Z", "data": map[string]any{"service.name": "api", "COUNT": 20}},
+						{"time": "2024-01-02T03:02:00Z", "data": map[string]any{"service.name": "web", "COUNT": 5}},
+					},
+				},
+				"query": map[string]any{
+					"breakdowns":   []string{"service.name"},
+					"calculations": []map[string]any{{"op": "COUNT"}},
+				},
+			})
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestRun_SeriesCSV(t *testing.T) {
+	opts, ts := setupTest(t, seriesHandler(t))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--calc", "COUNT", "--breakdown", "service.name", "--series=csv"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Time,service.name,COUNT\n" +
+		"2024-01-02T03:00:00Z,api,10\n" +
+		"2024-01-02T03:00:00Z,web,5\n" +
+		"2024-01-02T03:01:00Z,api,30\n" +
+		"2024-01-02T03:02:00Z,api,20\n" +
+		"2024-01-02T03:02:00Z,web,5\n"
+	if got := ts.OutBuf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRun_SeriesJSON(t *testing.T) {
+	opts, ts := setupTest(t, seriesHandler(t))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--calc", "COUNT", "--series"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result api.QueryResultDetails
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if result.Data == nil || result.Data.Series == nil || len(*result.Data.Series) != 5 {
+		t.Errorf("expected 5 series entries in output, got %+v", result.Data)
+	}
+}
+
+func TestRun_SeriesSparkline(t *testing.T) {
+	opts, ts := setupTest(t, seriesHandler(t))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--calc", "COUNT", "--breakdown", "service.name", "--series=sparkline"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := ts.OutBuf.String()
+	for _, want := range []string{"SERVICE.NAME", "TREND", "▁█▄", "▁ ▁"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRun_SeriesInvalid(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"run", "--dataset", "test-dataset", "--calc", "COUNT", "--series=chart"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--series") {
+		t.Fatalf("err = %v, want --series validation error", err)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	for _, tc := range []struct {
+		name string
+		vals []*float64
+		want string
+	}{
+		{"empty", nil, ""},
+		{"flat", []*float64{f(3), f(3)}, "▁▁"},
+		{"range", []*float64{f(0), f(7), f(14)}, "▁▄█"},
+		{"gap", []*float64{f(1), nil, f(2)}, "▁ █"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sparkline(tc.vals); got != tc.want {
+				t.Errorf("sparkline = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
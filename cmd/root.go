@@ -1,18 +1,28 @@
 package cmd
 
 import (
+	"strings"
+
 	apiCmd "github.com/bendrucker/honeycomb-cli/cmd/api"
+	"github.com/bendrucker/honeycomb-cli/cmd/apply"
 	"github.com/bendrucker/honeycomb-cli/cmd/auth"
+	"github.com/bendrucker/honeycomb-cli/cmd/backup"
 	"github.com/bendrucker/honeycomb-cli/cmd/board"
+	"github.com/bendrucker/honeycomb-cli/cmd/browse"
 	"github.com/bendrucker/honeycomb-cli/cmd/column"
 	"github.com/bendrucker/honeycomb-cli/cmd/dataset"
+	"github.com/bendrucker/honeycomb-cli/cmd/dev"
+	"github.com/bendrucker/honeycomb-cli/cmd/diff"
 	"github.com/bendrucker/honeycomb-cli/cmd/environment"
+	"github.com/bendrucker/honeycomb-cli/cmd/event"
 	"github.com/bendrucker/honeycomb-cli/cmd/key"
+	"github.com/bendrucker/honeycomb-cli/cmd/lint"
 	"github.com/bendrucker/honeycomb-cli/cmd/marker"
 	mcpCmd "github.com/bendrucker/honeycomb-cli/cmd/mcp"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/cmd/query"
 	"github.com/bendrucker/honeycomb-cli/cmd/recipient"
+	"github.com/bendrucker/honeycomb-cli/cmd/service"
 	"github.com/bendrucker/honeycomb-cli/cmd/signal"
 	"github.com/bendrucker/honeycomb-cli/cmd/slo"
 	"github.com/bendrucker/honeycomb-cli/cmd/trigger"
@@ -36,6 +46,12 @@ func NewRootCmd(ios *iostreams.IOStreams) *cobra.Command {
 			if err := output.ValidateFormat(opts.Format); err != nil {
 				return err
 			}
+			if err := opts.OutputFilter().Validate(); err != nil {
+				return err
+			}
+			if err := opts.OutputView().Validate(); err != nil {
+				return err
+			}
 
 			opts.ConfigPath = config.DefaultPath()
 			cfg, err := config.Load(opts.ConfigPath)
@@ -59,21 +75,34 @@ func NewRootCmd(ios *iostreams.IOStreams) *cobra.Command {
 	cmd.SetVersionTemplate("honeycomb {{.Version}}\n")
 
 	cmd.PersistentFlags().BoolVar(&opts.NoInteractive, "no-interactive", false, "Disable interactive prompts")
-	cmd.PersistentFlags().StringVar(&opts.Format, "format", "", "Output format: json, table")
+	cmd.PersistentFlags().StringVar(&opts.Format, "format", "", "Output format: "+strings.Join(output.ValidFormats, ", "))
+	cmd.PersistentFlags().StringVarP(&opts.JQ, "jq", "q", "", "Filter JSON output with a jq expression")
+	cmd.PersistentFlags().StringVarP(&opts.Template, "template", "t", "", "Format JSON output with a Go template")
+	cmd.PersistentFlags().StringSliceVar(&opts.Columns, "columns", nil, "Columns to show in list output, by JSON field name (e.g. id,name,updated_at)")
+	cmd.PersistentFlags().StringVar(&opts.Sort, "sort", "", "Sort list output by a JSON field; prefix with - for descending (e.g. -updated_at)")
 	cmd.PersistentFlags().StringVar(&opts.APIUrl, "api-url", "", "Honeycomb API URL")
 	cmd.PersistentFlags().StringVar(&opts.Profile, "profile", "", "Configuration profile to use")
 
 	cmd.AddCommand(apiCmd.NewCmd(opts))
+	cmd.AddCommand(apply.NewCmd(opts))
 	cmd.AddCommand(auth.NewCmd(opts))
 	cmd.AddCommand(board.NewCmd(opts))
+	cmd.AddCommand(browse.NewCmd(opts))
 	cmd.AddCommand(column.NewCmd(opts))
 	cmd.AddCommand(dataset.NewCmd(opts))
+	cmd.AddCommand(dev.NewCmd(opts))
+	cmd.AddCommand(diff.NewCmd(opts))
 	cmd.AddCommand(environment.NewCmd(opts))
+	cmd.AddCommand(backup.NewExportCmd(opts))
+	cmd.AddCommand(event.NewCmd(opts))
+	cmd.AddCommand(backup.NewImportCmd(opts))
 	cmd.AddCommand(key.NewCmd(opts))
+	cmd.AddCommand(lint.NewCmd(opts))
 	cmd.AddCommand(marker.NewCmd(opts))
 	cmd.AddCommand(mcpCmd.NewCmd(opts))
 	cmd.AddCommand(query.NewCmd(opts))
 	cmd.AddCommand(recipient.NewCmd(opts))
+	cmd.AddCommand(service.NewCmd(opts))
 	cmd.AddCommand(signal.NewCmd(opts))
 	cmd.AddCommand(slo.NewCmd(opts))
 	cmd.AddCommand(trigger.NewCmd(opts))
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/deref"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/bendrucker/honeycomb-cli/internal/poll"
+	"github.com/spf13/cobra"
+)
+
+const (
+	graphDOT     = "dot"
+	graphMermaid = "mermaid"
+)
+
+var graphFormats = []string{graphDOT, graphMermaid}
+
+type dependencyItem struct {
+	Parent string `json:"parent" col:"Parent"`
+	Child  string `json:"child" col:"Child"`
+	Calls  int    `json:"call_count" col:"Calls"`
+}
+
+var dependencyListTable = output.TableFromTags[dependencyItem]()
+
+type dependenciesOptions struct {
+	services  []string
+	timeRange command.Duration
+	startTime command.Time
+	endTime   command.Time
+	limit     int
+	graph     string
+}
+
+func NewDependenciesCmd(opts *options.RootOptions) *cobra.Command {
+	o := &dependenciesOptions{timeRange: command.Duration{Duration: 2 * time.Hour}}
+
+	cmd := &cobra.Command{
+		Use:     "dependencies",
+		Short:   "List dependencies between services",
+		Aliases: []string{"deps"},
+		Long: "List the calls between services observed in trace data.\n\n" +
+			"A dependency request is created and polled until its results are ready. " +
+			"Edges are printed as a table or JSON, or with --graph as a Graphviz DOT or " +
+			"Mermaid flowchart that can be pasted into documentation.",
+		Example: `  # Dependencies from the last day
+  honeycomb service dependencies --time-range 24h
+
+  # Only edges involving checkout or payments, as JSON
+  honeycomb service dependencies --service checkout --service payments --format json
+
+  # Render a PNG with Graphviz
+  honeycomb service dependencies --graph dot | dot -Tpng -o services.png`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := command.ValidateEnum("graph", o.graph, graphFormats); err != nil {
+				return err
+			}
+			return runDependencies(cmd.Context(), opts, o)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.services, "service", nil, "Only include dependencies involving this service (repeatable)")
+	cmd.Flags().Var(&o.timeRange, "time-range", "Time range to evaluate, relative to --start-time, --end-time, or now")
+	cmd.Flags().Var(&o.startTime, "start-time", "Start time: "+command.TimeFormats)
+	cmd.Flags().Var(&o.endTime, "end-time", "End time: "+command.TimeFormats)
+	cmd.Flags().IntVar(&o.limit, "limit", 0, "Maximum number of dependencies to return (default 10000, max 64000)")
+	cmd.Flags().StringVar(&o.graph, "graph", "", "Render as a graph instead of a table: "+command.EnumUsage(graphFormats))
+
+	return cmd
+}
+
+func runDependencies(ctx context.Context, opts *options.RootOptions, o *dependenciesOptions) error {
+	body, err := o.request()
+	if err != nil {
+		return err
+	}
+
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	var params *api.CreateMapDependencyRequestParams
+	if o.limit > 0 {
+		params = &api.CreateMapDependencyRequestParams{Limit: &o.limit}
+	}
+
+	resp, err := client.CreateMapDependencyRequestWithResponse(ctx, params, body)
+	if err != nil {
+		return fmt.Errorf("creating dependency request: %w", err)
+	}
+	typed := resp.JSON201
+	if typed == nil {
+		typed = resp.JSON200
+	}
+	created, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, typed)
+	if err != nil {
+		return err
+	}
+	if created.RequestId == nil {
+		return fmt.Errorf("dependency request ID missing from response")
+	}
+
+	deps, err := fetchDependencies(ctx, opts, client, *created.RequestId)
+	if err != nil {
+		return err
+	}
+
+	items := make([]dependencyItem, len(deps))
+	for i, d := range deps {
+		items[i] = toItem(d)
+	}
+
+	switch o.graph {
+	case graphDOT:
+		return writeDOT(opts.IOStreams.Out, items)
+	case graphMermaid:
+		return writeMermaid(opts.IOStreams.Out, items)
+	default:
+		return opts.OutputWriterList().WriteList(items, dependencyListTable, "No dependencies found.")
+	}
+}
+
+func (o *dependenciesOptions) request() (api.CreateMapDependenciesRequest, error) {
+	var body api.CreateMapDependenciesRequest
+
+	var start, end *int
+	if !o.startTime.IsZero() {
+		start = ptr(o.startTime.UnixSeconds())
+	}
+	if !o.endTime.IsZero() {
+		end = ptr(o.endTime.UnixSeconds())
+	}
+
+	if start != nil && end != nil {
+		if *end < *start {
+			return body, fmt.Errorf("--end-time must not be before --start-time")
+		}
+		body.StartTime = start
+		body.EndTime = end
+	} else {
+		seconds := int(o.timeRange.Seconds())
+		if seconds < 1 {
+			return body, fmt.Errorf("--time-range must be at least 1s")
+		}
+		body.TimeRange = &seconds
+		body.StartTime = start
+		body.EndTime = end
+	}
+
+	if len(o.services) > 0 {
+		nodeType := api.Service
+		filters := make([]api.MapNode, len(o.services))
+		for i, name := range o.services {
+			filters[i] = api.MapNode{Name: name, Type: &nodeType}
+		}
+		body.Filters = &filters
+	}
+
+	return body, nil
+}
+
+// fetchDependencies polls the request until it leaves the pending state, then
+// follows pagination to collect every dependency. The polled response is the
+// first page.
+func fetchDependencies(ctx context.Context, opts *options.RootOptions, client *api.ClientWithResponses, requestID string) ([]api.MapDependency, error) {
+	params := &api.GetMapDependenciesParams{}
+	get := func(ctx context.Context) (*api.GetMapDependenciesResponse, error) {
+		resp, err := client.GetMapDependenciesWithResponse(ctx, requestID, params)
+		if err != nil {
+			return nil, fmt.Errorf("getting dependencies: %w", err)
+		}
+		return api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
+	}
+
+	cfg := poll.Config{
+		Title:       "Mapping service dependencies...",
+		Interactive: opts.IOStreams.CanPrompt(),
+	}
+	page, err := poll.Poll(ctx, cfg, func(ctx context.Context) (*api.GetMapDependenciesResponse, bool, error) {
+		page, err := get(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		switch deref.Enum(page.Status) {
+		case string(api.GetMapDependenciesResponseStatusError):
+			return nil, false, fmt.Errorf("dependency request %s failed", requestID)
+		case string(api.GetMapDependenciesResponseStatusReady):
+			return page, true, nil
+		default:
+			return page, false, nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	first := page
+	pages := api.NewPages(func(ctx context.Context, cursor string, _ int) ([]api.MapDependency, *api.PaginationLinks, error) {
+		page := first
+		if cursor != "" {
+			params.PageAfter = &cursor
+			if page, err = get(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+		var deps []api.MapDependency
+		if page.Dependencies.IsSpecified() && !page.Dependencies.IsNull() {
+			deps = page.Dependencies.MustGet()
+		}
+		return deps, page.Links, nil
+	}, 0)
+	return pages.Collect(ctx)
+}
+
+func toItem(d api.MapDependency) dependencyItem {
+	item := dependencyItem{Calls: deref.Int(d.CallCount)}
+	if d.ParentNode != nil {
+		item.Parent = d.ParentNode.Name
+	}
+	if d.ChildNode != nil {
+		item.Child = d.ChildNode.Name
+	}
+	return item
+}
+
+func writeDOT(w io.Writer, items []dependencyItem) error {
+	var b strings.Builder
+	b.WriteString("digraph services {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, it := range items {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(it.Parent), dotQuote(it.Child), dotQuote(strconv.Itoa(it.Calls)))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// writeMermaid renders a flowchart. Service names can contain characters that
+// are not valid in Mermaid node IDs, so nodes get positional IDs and carry the
+// name as their label.
+func writeMermaid(w io.Writer, items []dependencyItem) error {
+	ids := map[string]string{}
+	var b strings.Builder
+
+	node := func(name string) string {
+		if id, ok := ids[name]; ok {
+			return id
+		}
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[name] = id
+		return fmt.Sprintf("%s[%s]", id, mermaidQuote(name))
+	}
+
+	b.WriteString("flowchart LR\n")
+	for _, it := range items {
+		fmt.Fprintf(&b, "  %s -->|%d| %s\n", node(it.Parent), it.Calls, node(it.Child))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func dependencyJSON(parent, child string, calls int) map[string]any {
+	return map[string]any{
+		"parent_node": map[string]any{"name": parent, "type": "service"},
+		"child_node":  map[string]any{"name": child, "type": "service"},
+		"call_count":  calls,
+	}
+}
+
+// dependencyServer accepts a dependency request and serves the given pages of
+// results, linking each page to the next.
+func dependencyServer(t *testing.T, body *map[string]any, pages ...[]map[string]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/1/maps/dependencies/requests":
+			if body != nil {
+				if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+					t.Errorf("decoding request body: %v", err)
+				}
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"request_id": "req-1", "status": "pending"})
+		case r.Method == http.MethodGet && r.URL.Path == "/1/maps/dependencies/requests/req-1":
+			page, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Query().Get("page[after]"), "page-"))
+			resp := map[string]any{
+				"request_id":   "req-1",
+				"status":       "ready",
+				"dependencies": pages[page],
+			}
+			if page+1 < len(pages) {
+				resp["links"] = map[string]any{
+					"next": "/1/maps/dependencies/requests/req-1?page[after]=page-" + strconv.Itoa(page+1),
+				}
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	var body map[string]any
+	opts, ts := setupTest(t, dependencyServer(t, &body,
+		[]map[string]any{dependencyJSON("frontend", "checkout", 142)},
+		[]map[string]any{dependencyJSON("checkout", "payments", 37)},
+	))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"dependencies", "--service", "checkout", "--time-range", "1h"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if body["time_range"] != float64(3600) {
+		t.Errorf("time_range = %v, want 3600", body["time_range"])
+	}
+	filters, _ := body["filters"].([]any)
+	if len(filters) != 1 || filters[0].(map[string]any)["name"] != "checkout" {
+		t.Errorf("filters = %v, want checkout", body["filters"])
+	}
+
+	var items []dependencyItem
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &items); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	want := []dependencyItem{
+		{Parent: "frontend", Child: "checkout", Calls: 142},
+		{Parent: "checkout", Child: "payments", Calls: 37},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("items = %+v, want %+v", items, want)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Errorf("items[%d] = %+v, want %+v", i, items[i], want[i])
+		}
+	}
+}
+
+func TestDependencies_AbsoluteTime(t *testing.T) {
+	var body map[string]any
+	opts, _ := setupTest(t, dependencyServer(t, &body, []map[string]any{}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"dependencies", "--start-time", "1700000000", "--end-time", "1700003600"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if body["start_time"] != float64(1700000000) || body["end_time"] != float64(1700003600) {
+		t.Errorf("body = %v, want start_time and end_time", body)
+	}
+	if _, ok := body["time_range"]; ok {
+		t.Errorf("time_range should be omitted with absolute times: %v", body)
+	}
+}
+
+func TestDependencies_Graph(t *testing.T) {
+	for _, tc := range []struct {
+		graph string
+		want  string
+	}{
+		{
+			graph: "dot",
+			want: "digraph services {\n" +
+				"  rankdir=LR;\n" +
+				"  \"frontend\" -> \"checkout\" [label=\"142\"];\n" +
+				"  \"checkout\" -> \"pay \\\"v2\\\"\" [label=\"37\"];\n" +
+				"}\n",
+		},
+		{
+			graph: "mermaid",
+			want: "flowchart LR\n" +
+				"  n0[\"frontend\"] -->|142| n1[\"checkout\"]\n" +
+				"  n1 -->|37| n2[\"pay #quot;v2#quot;\"]\n",
+		},
+	} {
+		t.Run(tc.graph, func(t *testing.T) {
+			opts, ts := setupTest(t, dependencyServer(t, nil, []map[string]any{
+				dependencyJSON("frontend", "checkout", 142),
+				dependencyJSON("checkout", `pay "v2"`, 37),
+			}))
+
+			cmd := NewCmd(opts)
+			cmd.SetArgs([]string{"dependencies", "--graph", tc.graph})
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ts.OutBuf.String(); got != tc.want {
+				t.Errorf("output:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDependencies_RequestFailed(t *testing.T) {
+	opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(map[string]any{"request_id": "req-1", "status": "pending"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"request_id": "req-1", "status": "error"})
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"dependencies"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "dependency request req-1 failed") {
+		t.Fatalf("err = %v, want request failed error", err)
+	}
+}
+
+func TestDependencies_InvalidGraph(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"dependencies", "--graph", "svg"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for invalid --graph")
+	}
+}
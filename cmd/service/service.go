@@ -0,0 +1,24 @@
+package service
+
+import (
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd(opts *options.RootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "service",
+		Short:   "Explore services in the service map",
+		Aliases: []string{"services"},
+		Example: `  # List service dependencies from the last two hours
+  honeycomb service dependencies
+
+  # Render the dependencies of one service as a Mermaid graph
+  honeycomb service dependencies --service checkout --graph mermaid`,
+	}
+
+	cmd.AddCommand(NewDependenciesCmd(opts))
+
+	return command.Group(cmd)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
 	"github.com/bendrucker/honeycomb-cli/internal/deref"
@@ -33,8 +34,9 @@ var anomalyListTable = output.TableDef{Columns: append(
 
 func NewAnomaliesCmd(opts *options.RootOptions) *cobra.Command {
 	var (
-		startTime int
-		endTime   int
+		startTime command.Time
+		endTime   command.Time
+		limit     command.ListLimit
 	)
 
 	cmd := &cobra.Command{
@@ -44,65 +46,59 @@ func NewAnomaliesCmd(opts *options.RootOptions) *cobra.Command {
 			"The time range is required and may span no more than 30 days.",
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runAnomalies(cmd.Context(), opts, args[0], startTime, endTime)
+			n, err := limit.Max()
+			if err != nil {
+				return err
+			}
+			return runAnomalies(cmd.Context(), opts, args[0], startTime.UnixSeconds(), endTime.UnixSeconds(), n)
 		},
 	}
 
-	cmd.Flags().IntVar(&startTime, "start-time", 0, "Start time as Unix timestamp (required)")
-	cmd.Flags().IntVar(&endTime, "end-time", 0, "End time as Unix timestamp (required)")
+	cmd.Flags().Var(&startTime, "start-time", "Start time: "+command.TimeFormats+" (required)")
+	cmd.Flags().Var(&endTime, "end-time", "End time: "+command.TimeFormats+" (required)")
 
 	_ = cmd.MarkFlagRequired("start-time")
 	_ = cmd.MarkFlagRequired("end-time")
+	limit.AddFlags(cmd)
 
 	return cmd
 }
 
-func runAnomalies(ctx context.Context, opts *options.RootOptions, id string, startTime, endTime int) error {
+func runAnomalies(ctx context.Context, opts *options.RootOptions, id string, startTime, endTime, limit int) error {
 	client, err := opts.ClientFor(nil, options.AuthConfig)
 	if err != nil {
 		return err
 	}
 
-	params := &api.ListSignalHistoricalAnomaliesParams{
-		StartTime: startTime,
-		EndTime:   endTime,
-	}
-
-	items := []anomalyItem{}
-	var cursor string
-	for {
+	pages := api.NewPages(func(ctx context.Context, cursor string, size int) ([]api.HistoricalAnomaly, *api.PaginationLinks, error) {
+		params := &api.ListSignalHistoricalAnomaliesParams{
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+		params.PageAfter, params.PageSize = api.PageParams(cursor, size)
 		resp, err := client.ListSignalHistoricalAnomaliesWithResponse(ctx, id, params)
 		if err != nil {
-			return fmt.Errorf("listing anomalies: %w", err)
+			return nil, nil, fmt.Errorf("listing anomalies: %w", err)
 		}
-
 		page, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
+		return page.HistoricalAnomalies, page.Links, nil
+	}, limit)
 
-		for _, a := range page.HistoricalAnomalies {
-			items = append(items, anomalyItem{
-				ID:          deref.String(a.Id),
-				StartedAt:   deref.Int(a.StartedAt),
-				EndedAt:     deref.Int(a.EndedAt),
-				Measurement: deref.Val(a.Measurement),
-				LowerBound:  a.NormalRange.Lower,
-				UpperBound:  a.NormalRange.Upper,
-			})
-		}
+	return command.WritePages(ctx, opts.IOStreams, opts.OutputWriterList(), pages, anomalyListTable, "No anomalies found.", toAnomalyItem)
+}
 
-		cursor, err = api.NextPageCursor(page.Links, cursor)
-		if err != nil {
-			return err
-		}
-		if cursor == "" {
-			break
-		}
-		params.PageAfter = &cursor
+func toAnomalyItem(a api.HistoricalAnomaly) anomalyItem {
+	return anomalyItem{
+		ID:          deref.String(a.Id),
+		StartedAt:   deref.Int(a.StartedAt),
+		EndedAt:     deref.Int(a.EndedAt),
+		Measurement: deref.Val(a.Measurement),
+		LowerBound:  a.NormalRange.Lower,
+		UpperBound:  a.NormalRange.Upper,
 	}
-
-	return opts.OutputWriterList().WriteList(items, anomalyListTable, "No anomalies found.")
 }
 
 func formatEpoch(seconds int) string {
@@ -20,11 +20,23 @@ func NewListCmd(opts *options.RootOptions) *cobra.Command {
 		measuredSignal string
 		status         string
 		anomalous      bool
+		limit          command.ListLimit
+		watch          command.WatchFlag
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List signals",
+		Long: `List signals.
+
+With --watch, the list refreshes until interrupted. In a terminal the table is
+redrawn in place; otherwise each signal is written as a JSON line when it is
+first listed and again whenever it changes, such as when it becomes anomalous.`,
+		Example: `  # List anomalous signals
+  honeycomb signal list --anomalous
+
+  # Write anomalous signals as JSON lines as they change, refreshing every minute
+  honeycomb signal list --anomalous --watch=1m | my-notifier`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			if err := command.ValidateEnum("measured-signal", measuredSignal, measuredSignals); err != nil {
 				return err
@@ -52,7 +64,14 @@ func NewListCmd(opts *options.RootOptions) *cobra.Command {
 				params.CurrentlyAnomalous = &anomalous
 			}
 
-			return runList(cmd.Context(), opts, params)
+			n, err := limit.Max()
+			if err != nil {
+				return err
+			}
+			if watch.Enabled() {
+				return runListWatch(cmd.Context(), opts, params, n, watch)
+			}
+			return runList(cmd.Context(), opts, params, n)
 		},
 	}
 
@@ -61,42 +80,59 @@ func NewListCmd(opts *options.RootOptions) *cobra.Command {
 	cmd.Flags().StringVar(&measuredSignal, "measured-signal", "", "Filter by measured signal: "+command.EnumUsage(measuredSignals))
 	cmd.Flags().StringVar(&status, "status", "", "Filter by status: "+command.EnumUsage(statuses))
 	cmd.Flags().BoolVar(&anomalous, "anomalous", false, "Only list signals that are currently anomalous")
+	limit.AddFlags(cmd)
+	watch.AddFlags(cmd)
 
 	return cmd
 }
 
-func runList(ctx context.Context, opts *options.RootOptions, params *api.ListSignalsParams) error {
+func runList(ctx context.Context, opts *options.RootOptions, params *api.ListSignalsParams, limit int) error {
 	client, err := opts.ClientFor(nil, options.AuthConfig)
 	if err != nil {
 		return err
 	}
 
-	items := []signalItem{}
-	var cursor string
-	for {
-		resp, err := client.ListSignalsWithResponse(ctx, params)
-		if err != nil {
-			return fmt.Errorf("listing signals: %w", err)
-		}
+	pages := api.NewPages(listSignals(client, params), limit)
+	return command.WritePages(ctx, opts.IOStreams, opts.OutputWriterList(), pages, signalListTable, "No signals found.", toItem)
+}
 
-		page, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
-		if err != nil {
-			return err
-		}
+func runListWatch(ctx context.Context, opts *options.RootOptions, params *api.ListSignalsParams, limit int, watch command.WatchFlag) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
 
-		for _, s := range page.Signals {
-			items = append(items, toItem(s))
-		}
+	return command.Watch[signalItem]{
+		Interval: watch.Interval.Duration,
+		Fetch: func(ctx context.Context) ([]signalItem, error) {
+			signals, err := api.NewPages(listSignals(client, params), limit).Collect(ctx)
+			if err != nil {
+				return nil, err
+			}
+			items := make([]signalItem, len(signals))
+			for i, s := range signals {
+				items[i] = toItem(s)
+			}
+			return items, nil
+		},
+		Key: func(s signalItem) string { return s.ID },
+		Render: func(items []signalItem) error {
+			return opts.OutputWriterList().WriteList(items, signalListTable, "No signals found.")
+		},
+	}.Run(ctx, opts.IOStreams)
+}
 
-		cursor, err = api.NextPageCursor(page.Links, cursor)
+func listSignals(client *api.ClientWithResponses, params *api.ListSignalsParams) api.PageFunc[api.Signal] {
+	return func(ctx context.Context, cursor string, size int) ([]api.Signal, *api.PaginationLinks, error) {
+		params.PageAfter, params.PageSize = api.PageParams(cursor, size)
+		resp, err := client.ListSignalsWithResponse(ctx, params)
 		if err != nil {
-			return err
+			return nil, nil, fmt.Errorf("listing signals: %w", err)
 		}
-		if cursor == "" {
-			break
+		page, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
+		if err != nil {
+			return nil, nil, err
 		}
-		params.PageAfter = &cursor
+		return page.Signals, page.Links, nil
 	}
-
-	return opts.OutputWriterList().WriteList(items, signalListTable, "No signals found.")
 }
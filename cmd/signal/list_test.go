@@ -161,3 +161,48 @@ func TestList_Paginates(t *testing.T) {
 		t.Fatalf("items len = %d, want 2", len(items))
 	}
 }
+
+func TestList_Limit(t *testing.T) {
+	var sizes []string
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sizes = append(sizes, r.URL.Query().Get("page[size]"))
+		page := map[string]any{
+			"signals": []map[string]any{signalJSON("sig-1", "checkout")},
+			"links":   map[string]any{"next": "https://api.honeycomb.io/1/signals?page%5Bafter%5D=cursor-2"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"list", "--limit", "1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sizes) != 1 || sizes[0] != "1" {
+		t.Errorf("page sizes = %v, want one request for 1", sizes)
+	}
+	var items []signalItem
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &items); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(items) != 1 {
+		t.Errorf("items len = %d, want 1", len(items))
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "Showing the first 1 results") {
+		t.Errorf("stderr = %q, want truncation notice", ts.ErrBuf.String())
+	}
+}
+
+func TestList_LimitAndAll(t *testing.T) {
+	opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"list", "--limit", "5", "--all"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error for --limit with --all")
+	}
+}
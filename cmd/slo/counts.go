@@ -0,0 +1,185 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/deref"
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// defaultHourlySince is how far back --hourly looks when --since is unset.
+const defaultHourlySince = 24 * time.Hour
+
+// epochSeconds is a Unix timestamp. It marshals as its underlying integer
+// while rendering as RFC3339 in tables.
+type epochSeconds int64
+
+func (e epochSeconds) FormatField() string {
+	return time.Unix(int64(e), 0).UTC().Format(time.RFC3339)
+}
+
+// sliPercent is the share of good events in a bucket, as a percentage.
+type sliPercent float64
+
+func (p sliPercent) FormatField() string {
+	return strconv.FormatFloat(float64(p), 'f', 3, 64) + "%"
+}
+
+type countBucket struct {
+	StartTime epochSeconds `json:"start_time" col:"Start"`
+	EndTime   epochSeconds `json:"end_time" col:"End"`
+	Total     int          `json:"total_count" col:"Total"`
+	Good      int          `json:"good_count" col:"Good"`
+	Bad       int          `json:"error_count" col:"Bad"`
+	SLI       *sliPercent  `json:"sli,omitempty" col:"SLI"`
+	Partial   bool         `json:"is_partial" col:"Partial"`
+}
+
+var countBucketTable = output.TableFromTags[countBucket]()
+
+func newCountBucket(start, end int64, total, bad int, partial bool) countBucket {
+	b := countBucket{
+		StartTime: epochSeconds(start),
+		EndTime:   epochSeconds(end),
+		Total:     total,
+		Good:      total - bad,
+		Bad:       bad,
+		Partial:   partial,
+	}
+	if total > 0 {
+		sli := sliPercent(float64(total-bad) / float64(total) * 100)
+		b.SLI = &sli
+	}
+	return b
+}
+
+func NewCountsCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	var (
+		hourly bool
+		since  command.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "counts <slo-id>",
+		Short: "Get good and bad event counts for an SLO (Enterprise)",
+		Long: "Get good and bad event counts for an SLO, with the SLI computed for each bucket.\n\n" +
+			"By default, per-minute realtime counts are returned for the current clock hour. " +
+			"Realtime counts reset at each hour boundary, so --since must not reach back past " +
+			"the start of the hour. Use --hourly for completed hours from the historical store.",
+		Example: `  # Per-minute counts for the current hour
+  honeycomb slo counts --dataset my-dataset slo-abc
+
+  # Per-minute counts for the last 15 minutes
+  honeycomb slo counts --dataset my-dataset slo-abc --since 15m
+
+  # Hourly counts for the last week
+  honeycomb slo counts --dataset my-dataset slo-abc --hourly --since 7d`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("since") && since.Duration <= 0 {
+				return fmt.Errorf("--since must be positive")
+			}
+
+			now := time.Now()
+
+			var start time.Time
+			switch {
+			case since.Duration > 0:
+				start = now.Add(-since.Duration)
+			case hourly:
+				start = now.Add(-defaultHourlySince)
+			default:
+				start = now.Truncate(time.Hour)
+			}
+
+			if hourly {
+				return runHourlyCounts(cmd.Context(), opts, *dataset, args[0], start, now)
+			}
+			return runRealtimeCounts(cmd.Context(), opts, *dataset, args[0], start, now)
+		},
+	}
+
+	cmd.Flags().BoolVar(&hourly, "hourly", false, "Get hourly counts from the historical store instead of realtime per-minute counts")
+	cmd.Flags().Var(&since, "since", "How far back to look, e.g. 30m, 12h, 7d (default: start of the hour, or 24h with --hourly)")
+
+	return cmd
+}
+
+func runRealtimeCounts(ctx context.Context, opts *options.RootOptions, dataset, sloID string, start, end time.Time) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	params := &api.GetSloRealtimeCountsParams{
+		StartTime: int(start.Unix()),
+		EndTime:   int(end.Unix()),
+	}
+	resp, err := client.GetSloRealtimeCountsWithResponse(ctx, dataset, sloID, params)
+	if err != nil {
+		return fmt.Errorf("getting SLO counts: %w", err)
+	}
+
+	counts, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
+	if err != nil {
+		return err
+	}
+
+	buckets := []countBucket{}
+	if counts.Windows != nil {
+		for _, w := range *counts.Windows {
+			buckets = append(buckets, newCountBucket(
+				int64(deref.Int(w.WindowStart)),
+				int64(deref.Int(w.WindowEnd)),
+				deref.Int(w.TotalCount),
+				deref.Int(w.ErrorCount),
+				deref.Bool(w.IsPartial),
+			))
+		}
+	}
+
+	return opts.OutputWriterList().WriteList(buckets, countBucketTable, "No counts found.")
+}
+
+func runHourlyCounts(ctx context.Context, opts *options.RootOptions, dataset, sloID string, start, end time.Time) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	params := &api.GetSloHourlyCountsHistoryParams{
+		StartTime: int(start.Unix()),
+		EndTime:   int(end.Unix()),
+	}
+	resp, err := client.GetSloHourlyCountsHistoryWithResponse(ctx, dataset, sloID, params)
+	if err != nil {
+		return fmt.Errorf("getting SLO hourly counts: %w", err)
+	}
+
+	counts, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
+	if err != nil {
+		return err
+	}
+
+	buckets := []countBucket{}
+	if counts.Buckets != nil {
+		for _, b := range *counts.Buckets {
+			buckets = append(buckets, newCountBucket(
+				deref.Val(b.StartTime),
+				deref.Val(b.EndTime),
+				deref.Int(b.TotalCount),
+				deref.Int(b.ErrorCount),
+				deref.Bool(b.IsPartial),
+			))
+		}
+	}
+
+	return opts.OutputWriterList().WriteList(buckets, countBucketTable, "No counts found.")
+}
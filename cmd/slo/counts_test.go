@@ -0,0 +1,117 @@
+package slo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/internal/output"
+)
+
+func TestCounts_Realtime(t *testing.T) {
+	var start, end int
+	opts, ts := setupBurnAlertTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/slos/test-dataset/slo-1/counts" {
+			t.Errorf("path = %q, want /1/slos/test-dataset/slo-1/counts", r.URL.Path)
+		}
+		start, _ = strconv.Atoi(r.URL.Query().Get("start_time"))
+		end, _ = strconv.Atoi(r.URL.Query().Get("end_time"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"slo_id":             "slo-1",
+			"resolution_seconds": 60,
+			"windows": []map[string]any{
+				{"window_start": 1745251200, "window_end": 1745251260, "total_count": 1000, "error_count": 5, "is_partial": false},
+				{"window_start": 1745251260, "window_end": 1745251320, "total_count": 0, "error_count": 0, "is_partial": true},
+			},
+		})
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"counts", "slo-1", "--dataset", "test-dataset"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if start%3600 != 0 {
+		t.Errorf("start_time = %d, want the start of the hour", start)
+	}
+	if end <= start {
+		t.Errorf("end_time = %d, want after start_time %d", end, start)
+	}
+
+	var buckets []countBucket
+	if err := json.Unmarshal(ts.OutBuf.Bytes(), &buckets); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("buckets len = %d, want 2", len(buckets))
+	}
+	if buckets[0].Good != 995 || buckets[0].Bad != 5 {
+		t.Errorf("good/bad = %d/%d, want 995/5", buckets[0].Good, buckets[0].Bad)
+	}
+	if buckets[0].SLI == nil || *buckets[0].SLI != 99.5 {
+		t.Errorf("SLI = %v, want 99.5", buckets[0].SLI)
+	}
+	if buckets[1].SLI != nil {
+		t.Errorf("SLI = %v, want nil for an empty window", *buckets[1].SLI)
+	}
+	if !buckets[1].Partial {
+		t.Error("second window should be partial")
+	}
+}
+
+func TestCounts_Hourly(t *testing.T) {
+	var start, end int
+	opts, ts := setupBurnAlertTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/slos/test-dataset/slo-1/counts/history" {
+			t.Errorf("path = %q, want /1/slos/test-dataset/slo-1/counts/history", r.URL.Path)
+		}
+		start, _ = strconv.Atoi(r.URL.Query().Get("start_time"))
+		end, _ = strconv.Atoi(r.URL.Query().Get("end_time"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"slo_id":             "slo-1",
+			"resolution_seconds": 3600,
+			"buckets": []map[string]any{
+				{"start_time": 1745168400, "end_time": 1745172000, "total_count": 74520, "error_count": 182},
+			},
+		})
+	}))
+	opts.Format = output.FormatTable
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"counts", "slo-1", "--dataset", "test-dataset", "--hourly", "--since", "7d"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := time.Duration(end-start) * time.Second; got != 7*24*time.Hour {
+		t.Errorf("range = %s, want 168h", got)
+	}
+
+	out := ts.OutBuf.String()
+	for _, want := range []string{"2025-04-20T17:00:00Z", "74338", "99.756%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCounts_ZeroSince(t *testing.T) {
+	opts, _ := setupBurnAlertTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"counts", "slo-1", "--dataset", "test-dataset", "--since", "0s"})
+	err := cmd.Execute()
+	if err == nil || err.Error() != "--since must be positive" {
+		t.Errorf("err = %v, want --since must be positive", err)
+	}
+}
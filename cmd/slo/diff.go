@@ -0,0 +1,36 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func NewDiffCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	return command.NewDiffCmd(opts.IOStreams, command.DiffCmd{
+		Use:    "diff <slo-id>",
+		Short:  "Compare an SLO with a local definition",
+		Noun:   "SLO",
+		Schema: "SLO",
+		Example: `  # Check an SLO for drift
+  honeycomb slo diff abc123 --dataset my-dataset --file slo.json
+
+  # List changed fields instead of a line diff
+  honeycomb slo diff abc123 --dataset my-dataset --file slo.json --style structural`,
+		Get: func(ctx context.Context, sloID string) ([]byte, error) {
+			client, err := opts.ClientFor(nil, options.AuthConfig)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.GetSloWithResponse(ctx, *dataset, sloID, &api.GetSloParams{})
+			if err != nil {
+				return nil, fmt.Errorf("getting SLO: %w", err)
+			}
+			return resp.Body, api.CheckResponse(resp.StatusCode(), resp.Body)
+		},
+	})
+}
@@ -0,0 +1,77 @@
+package slo
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sloDiffServer(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/slos/my-dataset/slo-1" {
+			t.Errorf("path = %q, want /1/slos/my-dataset/slo-1", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "slo-1",
+			"name": "Availability",
+			"time_period_days": 30,
+			"target_per_million": 999000,
+			"sli": {"alias": "is_good"},
+			"dataset_slugs": ["my-dataset"],
+			"created_at": "2024-01-01T00:00:00Z"
+		}`))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		definition string
+		wantErr    string
+		wantOut    []string
+	}{
+		{
+			// Read-only fields differ, which is not drift.
+			name:       "no drift",
+			definition: `{"id": "other", "name": "Availability", "target_per_million": 999000, "dataset_slugs": ["elsewhere"], "created_at": "2020-01-01T00:00:00Z"}`,
+		},
+		{
+			name:       "drift",
+			definition: `{"name": "Availability", "target_per_million": 995000}`,
+			wantErr:    "SLO slo-1 differs from",
+			wantOut:    []string{"--- live/slo-1\n", "-  \"target_per_million\": 999000\n", "+  \"target_per_million\": 995000\n"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, ts := setupTest(t, sloDiffServer(t))
+			file := filepath.Join(t.TempDir(), "slo.json")
+			if err := os.WriteFile(file, []byte(tc.definition), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := NewCmd(opts)
+			cmd.SetArgs([]string{"diff", "slo-1", "--dataset", "my-dataset", "--file", file})
+			err := cmd.Execute()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ts.OutBuf.Len() != 0 || !strings.Contains(ts.ErrBuf.String(), "No differences.") {
+					t.Errorf("stdout = %q, stderr = %q, want no differences", ts.OutBuf.String(), ts.ErrBuf.String())
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tc.wantErr)
+			}
+			for _, want := range tc.wantOut {
+				if !strings.Contains(ts.OutBuf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, ts.OutBuf.String())
+				}
+			}
+		})
+	}
+}
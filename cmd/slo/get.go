@@ -5,24 +5,42 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
 	"github.com/spf13/cobra"
 )
 
+// budgetThresholds are the remaining error budget percentages that
+// slo get --watch reports crossing.
+var budgetThresholds = []float64{75, 50, 25, 0}
+
 func NewGetCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
-	var detailed bool
+	var (
+		detailed bool
+		watch    command.WatchFlag
+	)
 
 	cmd := &cobra.Command{
 		Use:   "get <slo-id>",
 		Short: "Get an SLO",
-		Args:  cobra.ExactArgs(1),
+		Long: `Get an SLO.
+
+With --watch, the SLO refreshes until interrupted. In a terminal it is redrawn
+in place; otherwise it is written as a JSON line first and again whenever its
+definition changes or, with --detailed, its remaining budget crosses 75%, 50%,
+25%, or 0%.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if watch.Enabled() {
+				return runSLOGetWatch(cmd.Context(), opts, *dataset, args[0], detailed, watch)
+			}
 			return runSLOGet(cmd.Context(), opts, *dataset, args[0], detailed)
 		},
 	}
 
 	cmd.Flags().BoolVar(&detailed, "detailed", false, "Include compliance and budget data (Enterprise)")
+	watch.AddFlags(cmd)
 
 	return cmd
 }
@@ -33,6 +51,38 @@ func runSLOGet(ctx context.Context, opts *options.RootOptions, dataset, sloID st
 		return err
 	}
 
+	detail, err := getSLODetail(ctx, client, dataset, sloID, detailed)
+	if err != nil {
+		return err
+	}
+
+	return writeSloDetail(opts, detail)
+}
+
+func runSLOGetWatch(ctx context.Context, opts *options.RootOptions, dataset, sloID string, detailed bool, watch command.WatchFlag) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	return command.Watch[sloDetail]{
+		Interval: watch.Interval.Duration,
+		Fetch: func(ctx context.Context) ([]sloDetail, error) {
+			detail, err := getSLODetail(ctx, client, dataset, sloID, detailed)
+			if err != nil {
+				return nil, err
+			}
+			return []sloDetail{detail}, nil
+		},
+		Key:   func(d sloDetail) string { return d.ID },
+		State: sloWatchState,
+		Render: func(details []sloDetail) error {
+			return writeSloDetail(opts, details[0])
+		},
+	}.Run(ctx, opts.IOStreams)
+}
+
+func getSLODetail(ctx context.Context, client *api.ClientWithResponses, dataset, sloID string, detailed bool) (sloDetail, error) {
 	params := &api.GetSloParams{}
 	if detailed {
 		params.Detailed = ptr(true)
@@ -40,20 +90,38 @@ func runSLOGet(ctx context.Context, opts *options.RootOptions, dataset, sloID st
 
 	resp, err := client.GetSloWithResponse(ctx, dataset, sloID, params)
 	if err != nil {
-		return fmt.Errorf("getting SLO: %w", err)
+		return sloDetail{}, fmt.Errorf("getting SLO: %w", err)
 	}
 
 	if err := api.CheckResponse(resp.StatusCode(), resp.Body); err != nil {
-		return err
+		return sloDetail{}, err
 	}
 
 	// GetSloResp.JSON200 is a union type (unusable). Unmarshal resp.Body instead.
 	var sloResp sloDetailedResponse
 	if err := json.Unmarshal(resp.Body, &sloResp); err != nil {
-		return fmt.Errorf("parsing SLO response: %w", err)
+		return sloDetail{}, fmt.Errorf("parsing SLO response: %w", err)
 	}
 
-	return writeSloDetail(opts, detailedToDetail(sloResp))
+	return detailedToDetail(sloResp), nil
+}
+
+// sloWatchState compares SLOs by their definition and the budget threshold
+// they are under, rather than by compliance and budget values that change
+// with every refresh.
+func sloWatchState(d sloDetail) string {
+	crossed := -1
+	if d.BudgetRemaining != nil {
+		crossed = 0
+		for _, t := range budgetThresholds {
+			if *d.BudgetRemaining < t {
+				crossed++
+			}
+		}
+	}
+	d.Compliance, d.BudgetRemaining = nil, nil
+	b, _ := json.Marshal(d)
+	return fmt.Sprintf("%s %d", b, crossed)
 }
 
 func ptr[T any](v T) *T {
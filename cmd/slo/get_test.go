@@ -1,6 +1,7 @@
 package slo
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -84,6 +85,49 @@ func TestGet_Detailed(t *testing.T) {
 	}
 }
 
+func TestGet_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	budgets := []float64{80, 78, 60, 59, 59}
+	calls := 0
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		budget := budgets[calls]
+		calls++
+		if calls == len(budgets) {
+			cancel()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":                 "slo-1",
+			"name":               "Availability",
+			"target_per_million": 999000,
+			"time_period_days":   30,
+			"sli":                map[string]any{"alias": "sli.availability"},
+			"compliance":         99.9,
+			"budget_remaining":   budget,
+		})
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"get", "--dataset", "test-dataset", "--detailed", "--watch", "1ms", "slo-1"})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(ts.OutBuf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want the first refresh and the 75%% crossing:\n%s", len(lines), ts.OutBuf.String())
+	}
+	var detail sloDetail
+	if err := json.Unmarshal([]byte(lines[1]), &detail); err != nil {
+		t.Fatal(err)
+	}
+	if detail.BudgetRemaining == nil || *detail.BudgetRemaining != 60 {
+		t.Errorf("BudgetRemaining = %v, want 60", detail.BudgetRemaining)
+	}
+}
+
 func TestGet_NotFound(t *testing.T) {
 	opts, _ := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
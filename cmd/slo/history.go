@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
 	"github.com/bendrucker/honeycomb-cli/internal/output"
@@ -14,8 +15,8 @@ import (
 func NewHistoryCmd(opts *options.RootOptions, _ *string) *cobra.Command {
 	var (
 		sloIDs    []string
-		startTime int
-		endTime   int
+		startTime command.Time
+		endTime   command.Time
 	)
 
 	cmd := &cobra.Command{
@@ -29,13 +30,13 @@ func NewHistoryCmd(opts *options.RootOptions, _ *string) *cobra.Command {
 			if len(ids) == 0 {
 				return fmt.Errorf("at least one SLO ID is required (positional argument or --slo-id)")
 			}
-			return runHistory(cmd.Context(), opts, ids, startTime, endTime)
+			return runHistory(cmd.Context(), opts, ids, startTime.UnixSeconds(), endTime.UnixSeconds())
 		},
 	}
 
 	cmd.Flags().StringSliceVar(&sloIDs, "slo-id", nil, "SLO IDs to retrieve history for (repeatable; may also be passed as positional arguments)")
-	cmd.Flags().IntVar(&startTime, "start-time", 0, "Start time as Unix timestamp (required)")
-	cmd.Flags().IntVar(&endTime, "end-time", 0, "End time as Unix timestamp (required)")
+	cmd.Flags().Var(&startTime, "start-time", "Start time: "+command.TimeFormats+" (required)")
+	cmd.Flags().Var(&endTime, "end-time", "End time: "+command.TimeFormats+" (required)")
 
 	_ = cmd.MarkFlagRequired("start-time")
 	_ = cmd.MarkFlagRequired("end-time")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
 	"github.com/bendrucker/honeycomb-cli/internal/deref"
@@ -23,13 +24,27 @@ var sloListTable = output.TableDef{
 }
 
 func NewListCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
-	return &cobra.Command{
+	var watch command.WatchFlag
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List SLOs",
+		Long: `List SLOs for a dataset.
+
+With --watch, the list refreshes until interrupted. In a terminal the table is
+redrawn in place; otherwise each SLO is written as a JSON line when it is first
+listed and again whenever it changes.`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if watch.Enabled() {
+				return runSLOListWatch(cmd.Context(), opts, *dataset, watch)
+			}
 			return runSLOList(cmd.Context(), opts, *dataset)
 		},
 	}
+
+	watch.AddFlags(cmd)
+
+	return cmd
 }
 
 func runSLOList(ctx context.Context, opts *options.RootOptions, dataset string) error {
@@ -38,14 +53,39 @@ func runSLOList(ctx context.Context, opts *options.RootOptions, dataset string)
 		return err
 	}
 
+	items, err := listSLOs(ctx, client, dataset)
+	if err != nil {
+		return err
+	}
+
+	return writeSLOList(opts, items)
+}
+
+func runSLOListWatch(ctx context.Context, opts *options.RootOptions, dataset string, watch command.WatchFlag) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	return command.Watch[sloItem]{
+		Interval: watch.Interval.Duration,
+		Fetch: func(ctx context.Context) ([]sloItem, error) {
+			return listSLOs(ctx, client, dataset)
+		},
+		Key:    func(s sloItem) string { return s.ID },
+		Render: func(items []sloItem) error { return writeSLOList(opts, items) },
+	}.Run(ctx, opts.IOStreams)
+}
+
+func listSLOs(ctx context.Context, client *api.ClientWithResponses, dataset string) ([]sloItem, error) {
 	resp, err := client.ListSlosWithResponse(ctx, dataset)
 	if err != nil {
-		return fmt.Errorf("listing SLOs: %w", err)
+		return nil, fmt.Errorf("listing SLOs: %w", err)
 	}
 
 	slos, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	items := make([]sloItem, len(*slos))
@@ -59,6 +99,9 @@ func runSLOList(ctx context.Context, opts *options.RootOptions, dataset string)
 			SLIAlias:         s.Sli.Alias,
 		}
 	}
+	return items, nil
+}
 
+func writeSLOList(opts *options.RootOptions, items []sloItem) error {
 	return opts.OutputWriterList().WriteList(items, sloListTable, "No SLOs found.")
 }
@@ -23,8 +23,10 @@ func NewCmd(opts *options.RootOptions) *cobra.Command {
 	cmd.AddCommand(NewCreateCmd(opts, &dataset))
 	cmd.AddCommand(NewUpdateCmd(opts, &dataset))
 	cmd.AddCommand(NewDeleteCmd(opts, &dataset))
+	cmd.AddCommand(NewDiffCmd(opts, &dataset))
 	cmd.AddCommand(NewBurnAlertCmd(opts, &dataset))
 	cmd.AddCommand(NewHistoryCmd(opts, &dataset))
+	cmd.AddCommand(NewCountsCmd(opts, &dataset))
 
 	return command.Group(cmd)
 }
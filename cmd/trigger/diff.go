@@ -0,0 +1,36 @@
+package trigger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
+	"github.com/bendrucker/honeycomb-cli/cmd/options"
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func NewDiffCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
+	return command.NewDiffCmd(opts.IOStreams, command.DiffCmd{
+		Use:    "diff <trigger-id>",
+		Short:  "Compare a trigger with a local definition",
+		Noun:   "trigger",
+		Schema: "TriggerResponse",
+		Example: `  # Check a trigger for drift
+  honeycomb trigger diff abc123 --dataset my-dataset --file trigger.json
+
+  # List changed fields instead of a line diff
+  honeycomb trigger diff abc123 --dataset my-dataset --file trigger.json --style structural`,
+		Get: func(ctx context.Context, triggerID string) ([]byte, error) {
+			client, err := opts.ClientFor(nil, options.AuthConfig)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.GetTriggerWithResponse(ctx, *dataset, triggerID)
+			if err != nil {
+				return nil, fmt.Errorf("getting trigger: %w", err)
+			}
+			return resp.Body, api.CheckResponse(resp.StatusCode(), resp.Body)
+		},
+	})
+}
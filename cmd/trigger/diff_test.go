@@ -0,0 +1,112 @@
+package trigger
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const liveTrigger = `{
+	"id": "abc123",
+	"name": "High latency",
+	"description": "P99 too high",
+	"disabled": false,
+	"triggered": true,
+	"frequency": 300,
+	"threshold": {"op": ">", "value": 1000, "exceeded_limit": 1},
+	"created_at": "2024-01-01T00:00:00Z",
+	"updated_at": "2024-02-01T00:00:00Z"
+}`
+
+func diffServer(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1/triggers/my-dataset/abc123" {
+			t.Errorf("path = %q, want /1/triggers/my-dataset/abc123", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(liveTrigger))
+	})
+}
+
+func writeDefinition(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "trigger.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiff_NoDrift(t *testing.T) {
+	opts, ts := setupTest(t, diffServer(t))
+
+	// Read-only fields differ and server defaults are omitted, neither of
+	// which is drift.
+	file := writeDefinition(t, `{
+		"id": "other",
+		"name": "High latency",
+		"description": "P99 too high",
+		"threshold": {"op": ">", "value": 1000},
+		"updated_at": "2020-01-01T00:00:00Z"
+	}`)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"diff", "abc123", "--dataset", "my-dataset", "--file", file})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if ts.OutBuf.Len() != 0 {
+		t.Errorf("output = %q, want empty", ts.OutBuf.String())
+	}
+	if !strings.Contains(ts.ErrBuf.String(), "No differences.") {
+		t.Errorf("stderr = %q, want No differences.", ts.ErrBuf.String())
+	}
+}
+
+func TestDiff_Drift(t *testing.T) {
+	opts, ts := setupTest(t, diffServer(t))
+	file := writeDefinition(t, `{"name": "High latency", "threshold": {"op": ">", "value": 500}}`)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"diff", "abc123", "--dataset", "my-dataset", "--file", file})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "trigger abc123 differs from") {
+		t.Fatalf("err = %v, want drift error", err)
+	}
+
+	out := ts.OutBuf.String()
+	for _, want := range []string{"--- live/abc123\n", "+++ " + file + "\n", "-    \"value\": 1000\n", "+    \"value\": 500\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDiff_Structural(t *testing.T) {
+	opts, ts := setupTest(t, diffServer(t))
+	file := writeDefinition(t, `{"name": "Latency", "frequency": 300, "alert_type": "on_change"}`)
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"diff", "abc123", "--dataset", "my-dataset", "--file", file, "--style", "structural"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected drift error")
+	}
+
+	want := "+ alert_type: \"on_change\"\n~ name: \"High latency\" -> \"Latency\"\n"
+	if !strings.HasSuffix(ts.OutBuf.String(), want) {
+		t.Errorf("output = %q, want suffix %q", ts.OutBuf.String(), want)
+	}
+}
+
+func TestDiff_InvalidStyle(t *testing.T) {
+	opts, _ := setupTest(t, http.NotFoundHandler())
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"diff", "abc123", "--dataset", "my-dataset", "--file", "x.json", "--style", "side-by-side"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --style") {
+		t.Errorf("err = %v, want invalid --style", err)
+	}
+}
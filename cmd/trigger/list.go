@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/bendrucker/honeycomb-cli/cmd/command"
 	"github.com/bendrucker/honeycomb-cli/cmd/options"
 	"github.com/bendrucker/honeycomb-cli/internal/api"
 	"github.com/bendrucker/honeycomb-cli/internal/output"
@@ -13,18 +14,36 @@ import (
 var triggerListTable = output.TableFromTags[triggerItem]()
 
 func NewListCmd(opts *options.RootOptions, dataset *string) *cobra.Command {
-	return &cobra.Command{
+	var watch command.WatchFlag
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List triggers",
+		Long: `List triggers for a dataset.
+
+With --watch, the list refreshes until interrupted. In a terminal the table is
+redrawn in place; otherwise each trigger is written as a JSON line when it is
+first listed and again whenever it changes, such as when it starts or stops
+alerting.`,
 		Example: `  # List triggers for a dataset
   honeycomb trigger list --dataset my-dataset
 
   # List triggers as JSON
-  honeycomb trigger list --dataset my-dataset --format json`,
+  honeycomb trigger list --dataset my-dataset --format json
+
+  # Refresh every 10 seconds
+  honeycomb trigger list --dataset my-dataset --watch=10s`,
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			if watch.Enabled() {
+				return runListWatch(cmd.Context(), opts, *dataset, watch)
+			}
 			return runList(cmd.Context(), opts, *dataset)
 		},
 	}
+
+	watch.AddFlags(cmd)
+
+	return cmd
 }
 
 func runList(ctx context.Context, opts *options.RootOptions, dataset string) error {
@@ -33,20 +52,46 @@ func runList(ctx context.Context, opts *options.RootOptions, dataset string) err
 		return err
 	}
 
+	items, err := listTriggers(ctx, client, dataset)
+	if err != nil {
+		return err
+	}
+
+	return opts.OutputWriterList().WriteList(items, triggerListTable, "No triggers found.")
+}
+
+func runListWatch(ctx context.Context, opts *options.RootOptions, dataset string, watch command.WatchFlag) error {
+	client, err := opts.ClientFor(nil, options.AuthConfig)
+	if err != nil {
+		return err
+	}
+
+	return command.Watch[triggerItem]{
+		Interval: watch.Interval.Duration,
+		Fetch: func(ctx context.Context) ([]triggerItem, error) {
+			return listTriggers(ctx, client, dataset)
+		},
+		Key: func(t triggerItem) string { return t.ID },
+		Render: func(items []triggerItem) error {
+			return opts.OutputWriterList().WriteList(items, triggerListTable, "No triggers found.")
+		},
+	}.Run(ctx, opts.IOStreams)
+}
+
+func listTriggers(ctx context.Context, client *api.ClientWithResponses, dataset string) ([]triggerItem, error) {
 	resp, err := client.ListTriggersWithResponse(ctx, dataset)
 	if err != nil {
-		return fmt.Errorf("listing triggers: %w", err)
+		return nil, fmt.Errorf("listing triggers: %w", err)
 	}
 
 	triggers, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.JSON200)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	items := make([]triggerItem, len(*triggers))
 	for i, t := range *triggers {
 		items[i] = toItem(t)
 	}
-
-	return opts.OutputWriterList().WriteList(items, triggerListTable, "No triggers found.")
+	return items, nil
 }
@@ -1,6 +1,7 @@
 package trigger
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -176,3 +177,37 @@ func TestList_Unauthorized(t *testing.T) {
 		t.Errorf("error = %q, want HTTP 401", err.Error())
 	}
 }
+
+func TestList_Watch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	triggered := []bool{false, false, true, true}
+	calls := 0
+	opts, ts := setupTest(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		state := triggered[calls]
+		calls++
+		if calls == len(triggered) {
+			cancel()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"id": "trigger-1", "name": "High Latency", "triggered": state},
+			{"id": "trigger-2", "name": "Error Rate", "triggered": false},
+		})
+	}))
+
+	cmd := NewCmd(opts)
+	cmd.SetArgs([]string{"list", "--dataset", "test-dataset", "--watch=1ms"})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"id":"trigger-1","name":"High Latency","disabled":false,"triggered":false}
+{"id":"trigger-2","name":"Error Rate","disabled":false,"triggered":false}
+{"id":"trigger-1","name":"High Latency","disabled":false,"triggered":true}
+`
+	if got := ts.OutBuf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -11,9 +11,12 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/bendrucker/honeycomb-cli/cmd"
+	"github.com/bendrucker/honeycomb-cli/internal/cassette"
 	"github.com/bendrucker/honeycomb-cli/internal/config"
 	"github.com/bendrucker/honeycomb-cli/internal/iostreams"
 )
@@ -35,7 +38,7 @@ func TestMain(m *testing.M) {
 	team = requireEnv("HONEYCOMB_TEAM")
 	apiURL = os.Getenv("HONEYCOMB_API_URL")
 
-	runID = generateRunID()
+	runID = resolveRunID()
 	log.Printf("run ID: %s", runID)
 
 	if ds := os.Getenv("HONEYCOMB_DATASET"); ds != "" {
@@ -109,6 +112,29 @@ func requireEnv(key string) string {
 	return v
 }
 
+// resolveRunID keeps the run ID of a recorded session alongside its
+// interactions, so replayed requests name the same resources.
+func resolveRunID() string {
+	if dir := os.Getenv(cassette.ReplayEnv); dir != "" {
+		data, err := os.ReadFile(filepath.Join(dir, "run_id"))
+		if err != nil {
+			log.Fatalf("reading recorded run ID: %v", err)
+		}
+		return strings.TrimSpace(string(data))
+	}
+
+	id := generateRunID()
+	if dir := os.Getenv(cassette.RecordEnv); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			log.Fatalf("creating recording directory: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "run_id"), []byte(id+"\n"), 0o600); err != nil {
+			log.Fatalf("recording run ID: %v", err)
+		}
+	}
+	return id
+}
+
 func generateRunID() string {
 	b := make([]byte, 4)
 	if _, err := rand.Read(b); err != nil {
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/url"
 )
 
@@ -34,3 +36,104 @@ func NextPageCursor(links *PaginationLinks, prev string) (string, error) {
 
 	return cursor, nil
 }
+
+// MaxPageSize is the largest page[size] the API accepts.
+const MaxPageSize = 100
+
+// PageFunc fetches one page of a cursor-paginated list: the page after
+// cursor, which is empty for the first page, with up to size items, or the
+// server's default page size when size is 0. It returns the page's items and
+// links.
+type PageFunc[T any] func(ctx context.Context, cursor string, size int) ([]T, *PaginationLinks, error)
+
+// PageParams converts a PageFunc's cursor and size to the page[after] and
+// page[size] request parameters, leaving each nil when unset.
+func PageParams(cursor string, size int) (*PaginationCursor, *PaginationSize) {
+	var (
+		after *PaginationCursor
+		sz    *PaginationSize
+	)
+	if cursor != "" {
+		after = &cursor
+	}
+	if size > 0 {
+		s := PaginationSize(size)
+		sz = &s
+	}
+	return after, sz
+}
+
+// Pages iterates over a cursor-paginated list, fetching each page as the
+// previous one is consumed.
+type Pages[T any] struct {
+	fetch     PageFunc[T]
+	limit     int
+	truncated bool
+}
+
+// NewPages returns an iterator over the list fetch pages through, stopping
+// after limit items. A limit of 0 fetches every page.
+func NewPages[T any](fetch PageFunc[T], limit int) *Pages[T] {
+	return &Pages[T]{fetch: fetch, limit: limit}
+}
+
+// All yields the items of each page in turn. Pages are requested no larger
+// than the remaining limit, and as large as the API allows without one. A
+// fetch error is yielded once and ends iteration.
+func (p *Pages[T]) All(ctx context.Context) iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		var cursor string
+		count := 0
+		for {
+			size := MaxPageSize
+			if p.limit > 0 {
+				size = min(p.limit-count, MaxPageSize)
+			}
+			items, links, err := p.fetch(ctx, cursor, size)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if p.limit > 0 && count+len(items) > p.limit {
+				items = items[:p.limit-count]
+				p.truncated = true
+			}
+			count += len(items)
+			if !yield(items, nil) {
+				return
+			}
+
+			next, err := NextPageCursor(links, cursor)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if next == "" {
+				return
+			}
+			if p.limit > 0 && count >= p.limit {
+				p.truncated = true
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+// Collect fetches every page up to the limit and returns their items.
+func (p *Pages[T]) Collect(ctx context.Context) ([]T, error) {
+	all := []T{}
+	for items, err := range p.All(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// Truncated reports whether iteration stopped at the limit with items left
+// in the list.
+func (p *Pages[T]) Truncated() bool {
+	return p.truncated
+}
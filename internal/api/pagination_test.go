@@ -1,6 +1,10 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -80,3 +84,99 @@ func TestNextPageCursor(t *testing.T) {
 		})
 	}
 }
+
+// fakePages serves pages of n items numbered from 0, each page holding up to
+// size items (default 3), and records the requested sizes.
+func fakePages(n int, sizes *[]int) PageFunc[int] {
+	return func(_ context.Context, cursor string, size int) ([]int, *PaginationLinks, error) {
+		*sizes = append(*sizes, size)
+		start := 0
+		if cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		if size == 0 {
+			size = 3
+		}
+		end := min(start+size, n)
+		items := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			items = append(items, i)
+		}
+		links := &PaginationLinks{}
+		if end < n {
+			links.Next = nullable.NewNullableWithValue("/1/things?page%5Bafter%5D=" + strconv.Itoa(end))
+		}
+		return items, links, nil
+	}
+}
+
+func TestPages(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		n         int
+		limit     int
+		want      int
+		sizes     []int
+		truncated bool
+	}{
+		{name: "all", n: 250, limit: 0, want: 250, sizes: []int{100, 100, 100}},
+		{name: "limit within a page", n: 7, limit: 2, want: 2, sizes: []int{2}, truncated: true},
+		{name: "limit across pages", n: 250, limit: 150, want: 150, sizes: []int{100, 50}, truncated: true},
+		{name: "limit equals length", n: 2, limit: 2, want: 2, sizes: []int{2}},
+		{name: "limit exceeds length", n: 2, limit: 5, want: 2, sizes: []int{5}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var sizes []int
+			pages := NewPages(fakePages(tc.n, &sizes), tc.limit)
+			items, err := pages.Collect(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != tc.want {
+				t.Errorf("items = %d, want %d", len(items), tc.want)
+			}
+			for i, item := range items {
+				if item != i {
+					t.Fatalf("items[%d] = %d", i, item)
+				}
+			}
+			if !slices.Equal(sizes, tc.sizes) {
+				t.Errorf("page sizes = %v, want %v", sizes, tc.sizes)
+			}
+			if pages.Truncated() != tc.truncated {
+				t.Errorf("truncated = %v, want %v", pages.Truncated(), tc.truncated)
+			}
+		})
+	}
+}
+
+func TestPages_Error(t *testing.T) {
+	pages := NewPages(func(context.Context, string, int) ([]int, *PaginationLinks, error) {
+		return nil, nil, errors.New("boom")
+	}, 0)
+	if _, err := pages.Collect(context.Background()); err == nil || err.Error() != "boom" {
+		t.Errorf("err = %v, want boom", err)
+	}
+}
+
+func TestPages_Break(t *testing.T) {
+	var sizes []int
+	pages := NewPages(fakePages(9, &sizes), 0)
+	for range pages.All(context.Background()) {
+		break
+	}
+	if len(sizes) != 1 {
+		t.Errorf("requests = %d, want 1", len(sizes))
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	after, size := PageParams("", 0)
+	if after != nil || size != nil {
+		t.Errorf("PageParams(\"\", 0) = %v, %v, want nil", after, size)
+	}
+	after, size = PageParams("abc", 20)
+	if *after != "abc" || *size != 20 {
+		t.Errorf("PageParams = %q, %v", *after, *size)
+	}
+}
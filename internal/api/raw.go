@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ReadResponse reads and closes a response from one of the raw
+// ClientInterface methods, returning its body, or the API error it carries.
+// It takes the method's results directly, as in
+// api.ReadResponse(c.GetBoard(ctx, id)).
+func ReadResponse(resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if err := CheckResponse(resp.StatusCode, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// DecodeResponse reads a raw response like ReadResponse and unmarshals its
+// body into a T, typically a map[string]any or a slice of them for callers
+// that pass resources through without a typed model.
+func DecodeResponse[T any](resp *http.Response, err error) (T, error) {
+	var v T
+	body, err := ReadResponse(resp, err)
+	if err != nil {
+		return v, err
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return v, fmt.Errorf("parsing response: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,201 @@
+// Package backup exports an environment's configuration to a directory tree
+// of JSON definitions and imports such a tree into another environment.
+//
+// Each resource is written to its own file, named by its ID in the source
+// environment and stripped of read-only fields:
+//
+//	recipients/<id>.json
+//	boards/<id>.json
+//	boards/<board-id>/views/<id>.json
+//	datasets/<slug>/dataset.json
+//	datasets/<slug>/definitions.json
+//	datasets/<slug>/columns/<id>.json
+//	datasets/<slug>/calculated-fields/<id>.json
+//	datasets/<slug>/marker-settings/<id>.json
+//	datasets/<slug>/queries/<id>.json
+//	datasets/<slug>/query-annotations/<id>.json
+//	datasets/<slug>/slos/<id>.json
+//	datasets/<slug>/burn-alerts/<id>.json
+//	datasets/<slug>/triggers/<id>.json
+//
+// Environment-wide queries and query annotations are stored under the
+// datasets/__all__ directory, matching the API's own convention.
+//
+// Definitions keep the source IDs of the resources they reference, such as
+// the recipients of a trigger or the queries on a board. Import creates
+// resources in dependency order and rewrites those references to the IDs of
+// the resources it created or matched in the target environment.
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+)
+
+// AllDatasets is the dataset slug the API uses for environment-wide queries
+// and query annotations.
+const AllDatasets = "__all__"
+
+const (
+	KindDataset            = "Dataset"
+	KindDatasetDefinitions = "DatasetDefinitions"
+	KindColumn             = "Column"
+	KindQuery              = "Query"
+	KindQueryAnnotation    = "QueryAnnotation"
+	KindBoardView          = "BoardView"
+)
+
+const (
+	StatusExported = "exported"
+	StatusCreated  = "created"
+	StatusUpdated  = "updated"
+	StatusExists   = "exists"
+	StatusSkipped  = "skipped"
+)
+
+type Object = map[string]any
+
+// Entry records one exported or imported resource.
+type Entry struct {
+	Kind     string `json:"kind" col:"Kind"`
+	Dataset  string `json:"dataset,omitempty" col:"Dataset"`
+	Name     string `json:"name" col:"Name"`
+	SourceID string `json:"source_id,omitempty" col:"Source ID"`
+	ID       string `json:"id,omitempty" col:"ID"`
+	Status   string `json:"status" col:"Status"`
+	Path     string `json:"path"`
+}
+
+// Directories of the tree, relative to its root or to a dataset directory.
+const (
+	dirRecipients       = "recipients"
+	dirBoards           = "boards"
+	dirViews            = "views"
+	dirDatasets         = "datasets"
+	dirColumns          = "columns"
+	dirCalculatedFields = "calculated-fields"
+	dirMarkerSettings   = "marker-settings"
+	dirQueries          = "queries"
+	dirQueryAnnotations = "query-annotations"
+	dirSLOs             = "slos"
+	dirBurnAlerts       = "burn-alerts"
+	dirTriggers         = "triggers"
+
+	fileDataset     = "dataset.json"
+	fileDefinitions = "definitions.json"
+)
+
+// strip removes the schema's read-only fields from a resource, along with its
+// id, which the file name records instead.
+func strip(obj Object, schema string) (Object, error) {
+	data, err := api.MarshalStrippingReadOnly(obj, schema)
+	if err != nil {
+		return nil, err
+	}
+	var out Object
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	delete(out, "id")
+	return out, nil
+}
+
+// writeFile writes v as indented JSON. Files are only readable by the owner
+// because recipient definitions can hold webhook secrets.
+func writeFile(path string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func readFile(path string) (Object, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var obj Object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return obj, nil
+}
+
+// definition is a resource read from the tree.
+type definition struct {
+	id   string
+	path string
+	obj  Object
+}
+
+// readDefinitions reads the .json files directly under dir in name order. A
+// missing directory holds no definitions.
+func readDefinitions(dir string) ([]definition, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []definition
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		obj, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, definition{id: strings.TrimSuffix(e.Name(), ".json"), path: path, obj: obj})
+	}
+	return defs, nil
+}
+
+// subdirs returns the names of the directories under dir in sorted order.
+func subdirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// nameOf returns a resource's display name: the first of its name-like
+// fields that is set, or its manifest key.
+func nameOf(kind string, obj Object) string {
+	for _, f := range []string{"name", "alias", "key_name", "description"} {
+		if s, _ := obj[f].(string); s != "" {
+			return s
+		}
+	}
+	if k, ok := manifest.LookupKind(kind); ok {
+		return k.Key(obj)
+	}
+	return ""
+}
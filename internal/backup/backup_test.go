@@ -0,0 +1,356 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+// fakeEnv is an in-memory environment keyed by collection path, such as
+// /1/triggers/production.
+type fakeEnv struct {
+	prefix      string
+	collections map[string][]Object
+	definitions map[string]Object
+	nextID      int
+}
+
+func newFakeEnv(t *testing.T, prefix string, collections map[string][]Object) (*fakeEnv, api.ClientInterface) {
+	t.Helper()
+	f := &fakeEnv{prefix: prefix, collections: collections, definitions: map[string]Object{}}
+	if f.collections == nil {
+		f.collections = map[string][]Object{}
+	}
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+func (f *fakeEnv) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	w.Header().Set("Content-Type", "application/json")
+
+	if parts[1] == "dataset_definitions" {
+		switch r.Method {
+		case http.MethodGet:
+			defs := f.definitions[parts[2]]
+			if defs == nil {
+				defs = Object{}
+			}
+			_ = json.NewEncoder(w).Encode(defs)
+		case http.MethodPatch:
+			var body Object
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			f.definitions[parts[2]] = body
+			_ = json.NewEncoder(w).Encode(body)
+		}
+		return
+	}
+
+	n := 3
+	switch {
+	case parts[1] == "datasets" || parts[1] == "recipients":
+		n = 2
+	case parts[1] == "boards" && len(parts) >= 4:
+		n = 4
+	case parts[1] == "boards":
+		n = 2
+	}
+	collection := "/" + strings.Join(parts[:n], "/")
+	items := f.collections[collection]
+
+	if len(parts) > n && r.Method == http.MethodGet {
+		for _, obj := range items {
+			if obj["id"] == parts[n] {
+				_ = json.NewEncoder(w).Encode(obj)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "not found"}`))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list := []Object{}
+		for _, obj := range items {
+			if slo := r.URL.Query().Get("slo_id"); slo != "" {
+				if ref, _ := obj["slo"].(map[string]any); ref["id"] != slo {
+					continue
+				}
+			}
+			list = append(list, obj)
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case http.MethodPost:
+		var body Object
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.nextID++
+		if parts[1] == "datasets" {
+			body["slug"] = body["name"]
+		} else {
+			body["id"] = fmt.Sprintf("%s%d", f.prefix, f.nextID)
+		}
+		f.collections[collection] = append(items, body)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(body)
+	case http.MethodPut:
+		var body Object
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		body["id"] = parts[n]
+		for i, obj := range items {
+			if obj["id"] == parts[n] {
+				items[i] = body
+			}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+func (f *fakeEnv) find(collection, field, value string) Object {
+	for _, obj := range f.collections[collection] {
+		if obj[field] == value {
+			return obj
+		}
+	}
+	return nil
+}
+
+func sourceEnv(t *testing.T) api.ClientInterface {
+	t.Helper()
+	f, client := newFakeEnv(t, "src", map[string][]Object{
+		"/1/datasets": {
+			{"slug": "production", "name": "production", "created_at": "2024-01-01T00:00:00Z"},
+			{"slug": "scratch", "name": "scratch"},
+		},
+		"/1/recipients": {
+			{"id": "r1", "type": "email", "details": map[string]any{"email_address": "oncall@example.com"}},
+		},
+		"/1/columns/production": {
+			{"id": "c1", "key_name": "duration_ms", "type": "float", "last_written": "2024-01-01T00:00:00Z"},
+		},
+		"/1/derived_columns/production": {
+			{"id": "d1", "alias": "is_slow", "expression": "GT($duration_ms, 1000)"},
+		},
+		"/1/queries/production": {
+			{"id": "q1", "calculations": []any{map[string]any{"op": "COUNT"}}},
+		},
+		"/1/query_annotations/production": {
+			{"id": "a1", "name": "Request count", "query_id": "q1", "source": "query"},
+		},
+		"/1/slos/production": {
+			{"id": "s1", "name": "Availability", "target_per_million": float64(999000), "dataset_slugs": []any{"production"}},
+		},
+		"/1/burn_alerts/production": {
+			{"id": "ba1", "description": "Fast burn", "alert_type": "exhaustion_time", "slo": map[string]any{"id": "s1"}, "recipients": []any{map[string]any{"id": "r1"}}},
+		},
+		"/1/triggers/production": {
+			{"id": "t1", "name": "Errors", "query_id": "tq1", "query": map[string]any{"time_range": float64(900)}, "recipients": []any{map[string]any{"id": "r1", "type": "email"}}, "triggered": false},
+		},
+		"/1/boards": {
+			{"id": "b1", "name": "Overview", "type": "flexible", "links": map[string]any{"board_url": "https://example.com"}, "panels": []any{
+				map[string]any{"type": "query", "query_panel": map[string]any{"query_id": "q1", "query_annotation_id": "a1", "dataset": "production"}},
+				map[string]any{"type": "slo", "slo_panel": map[string]any{"slo_id": "s1"}},
+			}},
+			{"id": "b2", "name": "Scratch", "type": "flexible", "panels": []any{
+				map[string]any{"type": "query", "query_panel": map[string]any{"query_id": "q2", "dataset": "scratch"}},
+			}},
+		},
+		"/1/queries/scratch": {
+			{"id": "q2", "calculations": []any{map[string]any{"op": "COUNT"}}},
+		},
+		"/1/boards/b1/views": {
+			{"id": "v1", "name": "Errors only", "filters": []any{map[string]any{"column": "error", "operation": "exists"}}},
+		},
+	})
+	f.definitions["production"] = Object{"duration_ms": map[string]any{"name": "duration_ms", "column_type": "column"}}
+	return client
+}
+
+func TestExport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "export")
+
+	entries, err := Export(context.Background(), sourceEnv(t), dir, ExportOptions{Datasets: []string{"prod*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		"recipients/r1.json",
+		"boards/b1.json",
+		"boards/b1/views/v1.json",
+		"datasets/production/dataset.json",
+		"datasets/production/definitions.json",
+		"datasets/production/columns/c1.json",
+		"datasets/production/calculated-fields/d1.json",
+		"datasets/production/queries/q1.json",
+		"datasets/production/query-annotations/a1.json",
+		"datasets/production/slos/s1.json",
+		"datasets/production/burn-alerts/ba1.json",
+		"datasets/production/triggers/t1.json",
+	} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("missing %s", path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "datasets", "scratch")); err == nil {
+		t.Error("exported scratch, which the pattern excludes")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "boards/b2.json")); err == nil {
+		t.Error("exported board b2, which shows a query of scratch")
+	}
+	if len(entries) != 13 {
+		t.Errorf("got %d entries, want 13", len(entries))
+	}
+	skipped := 0
+	for _, e := range entries {
+		if e.Status == StatusSkipped {
+			skipped++
+			if e.SourceID != "b2" {
+				t.Errorf("skipped %+v, want only b2", e)
+			}
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("skipped %d entries, want b2", skipped)
+	}
+
+	trigger, err := readFile(filepath.Join(dir, "datasets/production/triggers/t1.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"id", "triggered", "query_id"} {
+		if _, ok := trigger[field]; ok {
+			t.Errorf("trigger definition has %s, want it stripped", field)
+		}
+	}
+
+	defs, err := readFile(filepath.Join(dir, "datasets/production/definitions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := defs["duration_ms"].(map[string]any); d["column_type"] != nil {
+		t.Errorf("definition = %v, want column_type stripped", d)
+	}
+}
+
+func TestExport_NotEmpty(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "x"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Export(context.Background(), sourceEnv(t), dir, ExportOptions{Datasets: []string{"*"}})
+	if err == nil || !strings.Contains(err.Error(), "is not empty") {
+		t.Errorf("err = %v, want not empty error", err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "export")
+	if _, err := Export(context.Background(), sourceEnv(t), dir, ExportOptions{Datasets: []string{"production"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	target, client := newFakeEnv(t, "dst", map[string][]Object{
+		"/1/recipients": {
+			{"id": "existing-r", "type": "email", "details": map[string]any{"email_address": "oncall@example.com"}},
+		},
+	})
+
+	entries, err := Import(context.Background(), client, dir, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := map[string]string{}
+	for _, e := range entries {
+		statuses[e.Kind+"/"+e.SourceID] = e.Status
+	}
+	if statuses["Recipient/r1"] != StatusExists || statuses["Dataset/production"] != StatusCreated || statuses["Trigger/t1"] != StatusCreated {
+		t.Errorf("statuses = %v", statuses)
+	}
+
+	if target.definitions["production"] == nil {
+		t.Error("dataset definitions were not imported")
+	}
+
+	trigger := target.find("/1/triggers/production", "name", "Errors")
+	if got := trigger["recipients"].([]any)[0].(map[string]any)["id"]; got != "existing-r" {
+		t.Errorf("trigger recipient = %v, want existing-r", got)
+	}
+
+	slo := target.find("/1/slos/production", "name", "Availability")
+	alert := target.find("/1/burn_alerts/production", "description", "Fast burn")
+	if got := alert["slo"].(map[string]any)["id"]; got != slo["id"] {
+		t.Errorf("burn alert slo = %v, want %v", got, slo["id"])
+	}
+
+	query := target.collections["/1/queries/production"][0]
+	annotation := target.find("/1/query_annotations/production", "name", "Request count")
+	if annotation["query_id"] != query["id"] {
+		t.Errorf("annotation query_id = %v, want %v", annotation["query_id"], query["id"])
+	}
+
+	board := target.find("/1/boards", "name", "Overview")
+	panels := board["panels"].([]any)
+	queryPanel := panels[0].(map[string]any)["query_panel"].(map[string]any)
+	if _, ok := queryPanel["dataset"]; ok {
+		t.Error("query panel has dataset, which the API rejects on write")
+	}
+	if queryPanel["query_id"] != query["id"] || queryPanel["query_annotation_id"] != annotation["id"] {
+		t.Errorf("query panel = %v, want query %v and annotation %v", queryPanel, query["id"], annotation["id"])
+	}
+	if got := panels[1].(map[string]any)["slo_panel"].(map[string]any)["slo_id"]; got != slo["id"] {
+		t.Errorf("slo panel slo_id = %v, want %v", got, slo["id"])
+	}
+
+	views := target.collections[fmt.Sprintf("/1/boards/%s/views", board["id"])]
+	if len(views) != 1 || views[0]["name"] != "Errors only" {
+		t.Errorf("board views = %v", views)
+	}
+
+	// A second import matches everything it created.
+	entries, err = Import(context.Background(), client, dir, ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Status == StatusCreated {
+			t.Errorf("second import created %s %s", e.Kind, e.Name)
+		}
+	}
+	if n := len(target.collections["/1/triggers/production"]); n != 1 {
+		t.Errorf("got %d triggers after second import, want 1", n)
+	}
+}
+
+func TestImport_MissingReference(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeFile(filepath.Join(dir, "datasets/production/triggers/t1.json"), Object{
+		"name":       "Errors",
+		"recipients": []any{map[string]any{"id": "r9"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, client := newFakeEnv(t, "dst", nil)
+	_, err := Import(context.Background(), client, dir, ImportOptions{})
+	if err == nil || !strings.Contains(err.Error(), "references Recipient r9, which is not in the export") {
+		t.Errorf("err = %v, want missing reference error", err)
+	}
+}
@@ -0,0 +1,316 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+)
+
+type ExportOptions struct {
+	// Datasets are patterns, in path.Match syntax, selecting the datasets to
+	// export by slug. "*" selects every dataset. Environment-wide resources
+	// are always exported, except boards with panels that show queries or
+	// SLOs of other datasets.
+	Datasets []string
+}
+
+type exporter struct {
+	ctx     context.Context
+	client  api.ClientInterface
+	dir     string
+	entries []Entry
+}
+
+// Export writes the configuration of the environment c is authorized for to
+// dir, which must be empty or not exist yet, and returns an entry for each
+// file written.
+func Export(ctx context.Context, c api.ClientInterface, dir string, opts ExportOptions) ([]Entry, error) {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not empty", dir)
+	}
+	for _, p := range opts.Datasets {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid dataset pattern %q: %w", p, err)
+		}
+	}
+
+	e := &exporter{ctx: ctx, client: c, dir: dir}
+
+	datasets, err := api.DecodeResponse[[]Object](c.ListDatasets(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("listing datasets: %w", err)
+	}
+
+	if err := e.exportKind(manifest.KindRecipient, manifest.Scope{}, filepath.Join(dir, dirRecipients)); err != nil {
+		return nil, err
+	}
+
+	for _, ds := range datasets {
+		slug, _ := ds["slug"].(string)
+		if !matchAny(opts.Datasets, slug) {
+			continue
+		}
+		if err := e.exportDataset(ds, slug); err != nil {
+			return nil, fmt.Errorf("exporting dataset %s: %w", slug, err)
+		}
+	}
+
+	// Classic environments have no environment-wide queries.
+	err = e.exportQueries(AllDatasets)
+	var apiErr *api.APIError
+	if err != nil && !(errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound) {
+		return nil, fmt.Errorf("exporting environment-wide queries: %w", err)
+	}
+
+	if err := e.exportBoards(); err != nil {
+		return nil, err
+	}
+	return e.entries, nil
+}
+
+// exportBoards exports the boards and their views once the datasets are
+// exported, skipping boards with panels that reference queries, query
+// annotations, or SLOs that were not, since import could not remap them.
+func (e *exporter) exportBoards() error {
+	c, ctx := e.client, e.ctx
+
+	exported := map[string]bool{}
+	for _, entry := range e.entries {
+		exported[entry.Kind+"/"+entry.SourceID] = true
+	}
+
+	var skipped []Entry
+	boards, err := e.exportList(manifest.KindBoard, "", filepath.Join(e.dir, dirBoards), "Board", func() ([]Object, error) {
+		all, err := api.DecodeResponse[[]Object](c.ListBoards(ctx))
+		if err != nil {
+			return nil, err
+		}
+		var boards []Object
+		for _, b := range all {
+			if panelsExported(b, exported) {
+				boards = append(boards, b)
+				continue
+			}
+			skipped = append(skipped, Entry{
+				Kind:     manifest.KindBoard,
+				Name:     nameOf(manifest.KindBoard, b),
+				SourceID: manifest.ObjectID(b),
+				Status:   StatusSkipped,
+			})
+		}
+		return boards, nil
+	})
+	if err != nil {
+		return err
+	}
+	e.entries = append(e.entries, skipped...)
+	for _, b := range boards {
+		id := manifest.ObjectID(b)
+		_, err := e.exportList(KindBoardView, "", filepath.Join(e.dir, dirBoards, id, dirViews), "", func() ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListBoardViews(ctx, id))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// panelsExported reports whether every query, query annotation, and SLO the
+// panels of board reference is in exported, keyed by kind and source ID.
+func panelsExported(board Object, exported map[string]bool) bool {
+	refs := []struct{ panel, field, kind string }{
+		{"query_panel", "query_id", KindQuery},
+		{"query_panel", "query_annotation_id", KindQueryAnnotation},
+		{"slo_panel", "slo_id", manifest.KindSLO},
+	}
+	panels, _ := board["panels"].([]any)
+	for _, p := range panels {
+		panel, _ := p.(map[string]any)
+		for _, ref := range refs {
+			m, _ := panel[ref.panel].(map[string]any)
+			if id, _ := m[ref.field].(string); id != "" && !exported[ref.kind+"/"+id] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, slug string) bool {
+	return slices.ContainsFunc(patterns, func(p string) bool {
+		ok, _ := path.Match(p, slug)
+		return ok
+	})
+}
+
+func (e *exporter) exportDataset(ds Object, slug string) error {
+	c, ctx := e.client, e.ctx
+	dir := filepath.Join(e.dir, dirDatasets, slug)
+
+	def, err := strip(ds, "Dataset")
+	if err != nil {
+		return err
+	}
+	if err := e.write(KindDataset, slug, "", filepath.Join(dir, fileDataset), def); err != nil {
+		return err
+	}
+
+	definitions, err := api.DecodeResponse[Object](c.ListDatasetDefinitions(ctx, slug))
+	if err != nil {
+		return fmt.Errorf("listing dataset definitions: %w", err)
+	}
+	for field, v := range definitions {
+		d, ok := v.(map[string]any)
+		if !ok {
+			delete(definitions, field)
+			continue
+		}
+		delete(d, "column_type")
+	}
+	if err := e.write(KindDatasetDefinitions, slug, "", filepath.Join(dir, fileDefinitions), definitions); err != nil {
+		return err
+	}
+
+	_, err = e.exportList(KindColumn, slug, filepath.Join(dir, dirColumns), "CreateColumn", func() ([]Object, error) {
+		return api.DecodeResponse[[]Object](c.ListColumns(ctx, slug, nil))
+	})
+	if err != nil {
+		return err
+	}
+
+	scope := manifest.Scope{Dataset: slug}
+	for _, k := range []struct{ kind, dir string }{
+		{manifest.KindCalculatedField, dirCalculatedFields},
+		{manifest.KindMarkerSetting, dirMarkerSettings},
+		{manifest.KindTrigger, dirTriggers},
+	} {
+		if err := e.exportKind(k.kind, scope, filepath.Join(dir, k.dir)); err != nil {
+			return err
+		}
+	}
+
+	slos, err := e.exportList(manifest.KindSLO, slug, filepath.Join(dir, dirSLOs), "SLO", func() ([]Object, error) {
+		return api.DecodeResponse[[]Object](c.ListSlos(ctx, slug))
+	})
+	if err != nil {
+		return err
+	}
+	for _, slo := range slos {
+		if err := e.exportKind(manifest.KindBurnAlert, manifest.Scope{Dataset: slug, SLOID: manifest.ObjectID(slo)}, filepath.Join(dir, dirBurnAlerts)); err != nil {
+			return err
+		}
+	}
+
+	return e.exportQueries(slug)
+}
+
+// exportQueries writes a dataset's query annotations, including those created
+// from boards, and the queries they describe.
+func (e *exporter) exportQueries(slug string) error {
+	c, ctx := e.client, e.ctx
+	dir := filepath.Join(e.dir, dirDatasets, slug)
+
+	annotations, err := e.exportList(KindQueryAnnotation, slug, filepath.Join(dir, dirQueryAnnotations), "QueryAnnotation", func() ([]Object, error) {
+		return api.DecodeResponse[[]Object](c.ListQueryAnnotations(ctx, slug, &api.ListQueryAnnotationsParams{IncludeBoardAnnotations: ptr(true)}))
+	})
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, a := range annotations {
+		id, _ := a["query_id"].(string)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		query, err := api.DecodeResponse[Object](c.GetQuery(ctx, slug, id))
+		if err != nil {
+			return fmt.Errorf("getting query %s: %w", id, err)
+		}
+		def, err := strip(query, "Query")
+		if err != nil {
+			return err
+		}
+		if err := e.write(KindQuery, slug, id, filepath.Join(dir, dirQueries, id+".json"), def); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportKind exports the resources of a manifest kind in a scope.
+func (e *exporter) exportKind(kind string, scope manifest.Scope, dir string) error {
+	k, _ := manifest.LookupKind(kind)
+	_, err := e.exportList(kind, scope.Dataset, dir, k.Schema, func() ([]Object, error) {
+		return k.List(e.ctx, e.client, scope)
+	})
+	return err
+}
+
+// exportList writes each listed resource to dir, named by its ID, and
+// returns the list.
+func (e *exporter) exportList(kind, dataset, dir, schema string, list func() ([]Object, error)) ([]Object, error) {
+	objs, err := list()
+	if err != nil {
+		return nil, fmt.Errorf("listing %s resources: %w", kind, err)
+	}
+	for _, obj := range objs {
+		id := manifest.ObjectID(obj)
+		if id == "" {
+			return nil, fmt.Errorf("%s %q has no id", kind, nameOf(kind, obj))
+		}
+		def, err := strip(obj, schema)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case manifest.KindTrigger:
+			// Triggers are returned with both their saved query ID and the
+			// query inline. Keep the inline query, which creates its own saved
+			// query in the target environment.
+			if def["query"] != nil {
+				delete(def, "query_id")
+			}
+		case manifest.KindBoard:
+			manifest.StripPanelDatasets(def)
+		}
+		if err := e.write(kind, dataset, id, filepath.Join(dir, id+".json"), def); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+func (e *exporter) write(kind, dataset, id, path string, def Object) error {
+	if err := writeFile(path, def); err != nil {
+		return err
+	}
+	name := nameOf(kind, def)
+	if name == "" {
+		name = dataset
+	}
+	e.entries = append(e.entries, Entry{
+		Kind:     kind,
+		Dataset:  dataset,
+		Name:     name,
+		SourceID: id,
+		Status:   StatusExported,
+		Path:     path,
+	})
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,522 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+)
+
+type ImportOptions struct {
+	// Update overwrites resources that already exist in the target
+	// environment with their exported definitions. Without it, existing
+	// resources are left unchanged, and only referenced by the resources
+	// that are created.
+	Update bool
+}
+
+type importer struct {
+	ctx    context.Context
+	client api.ClientInterface
+	dir    string
+	opts   ImportOptions
+
+	// ids maps "<kind>/<source id>" to the ID of the resource in the target
+	// environment.
+	ids     map[string]string
+	entries []Entry
+}
+
+// Import recreates the resources exported to dir in the environment c is
+// authorized for, and returns an entry for each. Resources are matched to
+// existing ones by datasets' slugs, columns' key names, query annotations'
+// names and query specifications, board views' names, and otherwise by the
+// key honeycomb apply matches manifests with. It stops at the first failure,
+// returning the entries completed so far.
+func Import(ctx context.Context, c api.ClientInterface, dir string, opts ImportOptions) ([]Entry, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	im := &importer{ctx: ctx, client: c, dir: dir, opts: opts, ids: map[string]string{}}
+
+	slugs, err := subdirs(filepath.Join(dir, dirDatasets))
+	if err != nil {
+		return nil, err
+	}
+
+	steps := []func() error{
+		func() error { return im.importDatasets(slugs) },
+		func() error {
+			return im.importKind(manifest.KindRecipient, manifest.Scope{}, filepath.Join(dir, dirRecipients), nil)
+		},
+		func() error {
+			return im.eachDataset(slugs, func(slug string) error {
+				return im.importQueries(slug)
+			})
+		},
+		func() error {
+			return im.eachDataset(slugs, func(slug string) error {
+				return im.importKind(manifest.KindSLO, manifest.Scope{Dataset: slug}, filepath.Join(dir, dirDatasets, slug, dirSLOs), nil)
+			})
+		},
+		func() error { return im.eachDataset(slugs, im.importBurnAlerts) },
+		func() error {
+			return im.eachDataset(slugs, func(slug string) error {
+				return im.importKind(manifest.KindTrigger, manifest.Scope{Dataset: slug}, filepath.Join(dir, dirDatasets, slug, dirTriggers), im.remapRecipients)
+			})
+		},
+		im.importBoards,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return im.entries, err
+		}
+	}
+	return im.entries, nil
+}
+
+// eachDataset calls fn for each exported dataset, including the
+// environment-wide __all__ directory.
+func (im *importer) eachDataset(slugs []string, fn func(slug string) error) error {
+	for _, slug := range slugs {
+		if err := fn(slug); err != nil {
+			return fmt.Errorf("dataset %s: %w", slug, err)
+		}
+	}
+	return nil
+}
+
+// importDatasets creates missing datasets, then their columns, definitions,
+// calculated fields, and marker settings.
+func (im *importer) importDatasets(slugs []string) error {
+	c, ctx := im.client, im.ctx
+
+	existing, err := api.DecodeResponse[[]Object](c.ListDatasets(ctx))
+	if err != nil {
+		return fmt.Errorf("listing datasets: %w", err)
+	}
+	found := map[string]bool{}
+	for _, ds := range existing {
+		if slug, _ := ds["slug"].(string); slug != "" {
+			found[slug] = true
+		}
+	}
+
+	return im.eachDataset(slugs, func(slug string) error {
+		if slug == AllDatasets {
+			return nil
+		}
+		dir := filepath.Join(im.dir, dirDatasets, slug)
+
+		path := filepath.Join(dir, fileDataset)
+		def, err := readFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if def != nil {
+			entry := Entry{Kind: KindDataset, Dataset: slug, Name: nameOf(KindDataset, def), SourceID: slug, ID: slug, Path: path}
+			if found[slug] {
+				entry.Status = StatusExists
+			} else {
+				created, err := api.DecodeResponse[Object](c.CreateDatasetWithBody(ctx, "application/json", jsonBody(def)))
+				if err != nil {
+					return fmt.Errorf("creating dataset: %w", err)
+				}
+				if s, _ := created["slug"].(string); s != slug {
+					return fmt.Errorf("created dataset has slug %q, want %q; rename the export directory to match", s, slug)
+				}
+				entry.Status = StatusCreated
+			}
+			im.entries = append(im.entries, entry)
+		}
+
+		if err := im.importColumns(slug); err != nil {
+			return err
+		}
+		if err := im.importDefinitions(slug); err != nil {
+			return err
+		}
+		scope := manifest.Scope{Dataset: slug}
+		if err := im.importKind(manifest.KindCalculatedField, scope, filepath.Join(dir, dirCalculatedFields), nil); err != nil {
+			return err
+		}
+		return im.importKind(manifest.KindMarkerSetting, scope, filepath.Join(dir, dirMarkerSettings), nil)
+	})
+}
+
+func (im *importer) importColumns(slug string) error {
+	c, ctx := im.client, im.ctx
+	defs, err := readDefinitions(filepath.Join(im.dir, dirDatasets, slug, dirColumns))
+	if err != nil || len(defs) == 0 {
+		return err
+	}
+
+	existing, err := api.DecodeResponse[[]Object](c.ListColumns(ctx, slug, nil))
+	if err != nil {
+		return fmt.Errorf("listing columns: %w", err)
+	}
+
+	return im.reconcile(KindColumn, slug, defs, existing,
+		func(obj Object) string { s, _ := obj["key_name"].(string); return s },
+		func(body Object) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateColumnWithBody(ctx, slug, "application/json", jsonBody(body)))
+		},
+		func(id string, body Object) (Object, error) {
+			// key_name is read-only once a column exists.
+			body, err := strip(body, "Column")
+			if err != nil {
+				return nil, err
+			}
+			return api.DecodeResponse[Object](c.UpdateColumnWithBody(ctx, slug, id, "application/json", jsonBody(body)))
+		})
+}
+
+func (im *importer) importDefinitions(slug string) error {
+	path := filepath.Join(im.dir, dirDatasets, slug, fileDefinitions)
+	def, err := readFile(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(def) == 0) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := api.ReadResponse(im.client.PatchDatasetDefinitionsWithBody(im.ctx, slug, "application/json", jsonBody(def))); err != nil {
+		return fmt.Errorf("updating dataset definitions: %w", err)
+	}
+	im.entries = append(im.entries, Entry{Kind: KindDatasetDefinitions, Dataset: slug, Name: slug, Status: StatusUpdated, Path: path})
+	return nil
+}
+
+// importQueries imports a dataset's query annotations along with the queries
+// they describe. Queries are immutable, so an annotation matches an existing
+// one with the same name whose query has the same specification; otherwise
+// both are created.
+func (im *importer) importQueries(slug string) error {
+	c, ctx := im.client, im.ctx
+	dir := filepath.Join(im.dir, dirDatasets, slug)
+
+	annotations, err := readDefinitions(filepath.Join(dir, dirQueryAnnotations))
+	if err != nil || len(annotations) == 0 {
+		return err
+	}
+	queryDefs, err := readDefinitions(filepath.Join(dir, dirQueries))
+	if err != nil {
+		return err
+	}
+	queries := map[string]definition{}
+	for _, q := range queryDefs {
+		queries[q.id] = q
+	}
+
+	existing, err := api.DecodeResponse[[]Object](c.ListQueryAnnotations(ctx, slug, &api.ListQueryAnnotationsParams{IncludeBoardAnnotations: ptr(true)}))
+	if err != nil {
+		return fmt.Errorf("listing query annotations: %w", err)
+	}
+	existingQueries := map[string]Object{}
+
+	for _, a := range annotations {
+		source, _ := a.obj["query_id"].(string)
+		query, ok := queries[source]
+		if !ok {
+			return fmt.Errorf("%s: references %s %s, which is not in the export", a.path, KindQuery, source)
+		}
+
+		entry := Entry{Kind: KindQueryAnnotation, Dataset: slug, Name: nameOf(KindQueryAnnotation, a.obj), SourceID: a.id, Path: a.path}
+
+		var match Object
+		for _, e := range existing {
+			if e["name"] != a.obj["name"] {
+				continue
+			}
+			id, _ := e["query_id"].(string)
+			q, ok := existingQueries[id]
+			if !ok {
+				q, err = api.DecodeResponse[Object](c.GetQuery(ctx, slug, id))
+				if err != nil {
+					return fmt.Errorf("getting query %s: %w", id, err)
+				}
+				existingQueries[id] = q
+			}
+			if manifest.Matches(query.obj, q) {
+				match = e
+				break
+			}
+		}
+
+		if match != nil {
+			entry.ID = manifest.ObjectID(match)
+			im.ids[KindQuery+"/"+source], _ = match["query_id"].(string)
+			entry.Status = StatusExists
+			if im.opts.Update {
+				body := maps.Clone(a.obj)
+				body["query_id"] = match["query_id"]
+				if _, err := api.ReadResponse(c.UpdateQueryAnnotationWithBody(ctx, slug, entry.ID, "application/json", jsonBody(body))); err != nil {
+					return fmt.Errorf("updating %s %q: %w", KindQueryAnnotation, entry.Name, err)
+				}
+				entry.Status = StatusUpdated
+			}
+		} else {
+			if err := im.createQuery(slug, query); err != nil {
+				return err
+			}
+			body := maps.Clone(a.obj)
+			body["query_id"] = im.ids[KindQuery+"/"+source]
+			created, err := api.DecodeResponse[Object](c.CreateQueryAnnotationWithBody(ctx, slug, "application/json", jsonBody(body)))
+			if err != nil {
+				return fmt.Errorf("creating %s %q: %w", KindQueryAnnotation, entry.Name, err)
+			}
+			entry.ID = manifest.ObjectID(created)
+			entry.Status = StatusCreated
+		}
+
+		im.ids[KindQueryAnnotation+"/"+a.id] = entry.ID
+		im.entries = append(im.entries, entry)
+	}
+	return nil
+}
+
+// createQuery creates a query unless an earlier annotation already mapped it.
+func (im *importer) createQuery(slug string, q definition) error {
+	if _, ok := im.ids[KindQuery+"/"+q.id]; ok {
+		return nil
+	}
+	created, err := api.DecodeResponse[Object](im.client.CreateQueryWithBody(im.ctx, slug, "application/json", jsonBody(q.obj)))
+	if err != nil {
+		return fmt.Errorf("creating query %s: %w", q.id, err)
+	}
+	id := manifest.ObjectID(created)
+	im.ids[KindQuery+"/"+q.id] = id
+	im.entries = append(im.entries, Entry{Kind: KindQuery, Dataset: slug, Name: q.id, SourceID: q.id, ID: id, Status: StatusCreated, Path: q.path})
+	return nil
+}
+
+// importBurnAlerts imports a dataset's burn alerts, grouped by the SLO they
+// belong to.
+func (im *importer) importBurnAlerts(slug string) error {
+	path := filepath.Join(im.dir, dirDatasets, slug, dirBurnAlerts)
+	defs, err := readDefinitions(path)
+	if err != nil {
+		return err
+	}
+
+	bySLO := map[string][]definition{}
+	var order []string
+	for _, d := range defs {
+		if err := im.remapRecipients(d.obj); err != nil {
+			return fmt.Errorf("%s: %w", d.path, err)
+		}
+		slo, _ := d.obj["slo"].(map[string]any)
+		if err := im.remap(slo, manifest.KindSLO, "id"); err != nil {
+			return fmt.Errorf("%s: %w", d.path, err)
+		}
+		id, _ := slo["id"].(string)
+		if _, ok := bySLO[id]; !ok {
+			order = append(order, id)
+		}
+		bySLO[id] = append(bySLO[id], d)
+	}
+
+	k, _ := manifest.LookupKind(manifest.KindBurnAlert)
+	for _, sloID := range order {
+		scope := manifest.Scope{Dataset: slug, SLOID: sloID}
+		if err := im.importDefinitionsOfKind(k, scope, bySLO[sloID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (im *importer) importBoards() error {
+	c, ctx := im.client, im.ctx
+	dir := filepath.Join(im.dir, dirBoards)
+
+	if err := im.importKind(manifest.KindBoard, manifest.Scope{}, dir, im.remapPanels); err != nil {
+		return err
+	}
+
+	boards, err := subdirs(dir)
+	if err != nil {
+		return err
+	}
+	for _, source := range boards {
+		views, err := readDefinitions(filepath.Join(dir, source, dirViews))
+		if err != nil {
+			return err
+		}
+		if len(views) == 0 {
+			continue
+		}
+		board, ok := im.ids[manifest.KindBoard+"/"+source]
+		if !ok {
+			return fmt.Errorf("views of board %s: the board is not in the export", source)
+		}
+
+		existing, err := api.DecodeResponse[[]Object](c.ListBoardViews(ctx, board))
+		if err != nil {
+			return fmt.Errorf("listing views of board %s: %w", board, err)
+		}
+		err = im.reconcile(KindBoardView, "", views, existing,
+			func(obj Object) string { s, _ := obj["name"].(string); return s },
+			func(body Object) (Object, error) {
+				return api.DecodeResponse[Object](c.CreateBoardViewWithBody(ctx, board, "application/json", jsonBody(body)))
+			},
+			func(id string, body Object) (Object, error) {
+				return api.DecodeResponse[Object](c.UpdateBoardViewWithBody(ctx, board, id, "application/json", jsonBody(body)))
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importKind imports the definitions of a manifest kind in dir, rewriting
+// each with remap first when it is set.
+func (im *importer) importKind(kind string, scope manifest.Scope, dir string, remap func(Object) error) error {
+	defs, err := readDefinitions(dir)
+	if err != nil || len(defs) == 0 {
+		return err
+	}
+	if remap != nil {
+		for _, d := range defs {
+			if err := remap(d.obj); err != nil {
+				return fmt.Errorf("%s: %w", d.path, err)
+			}
+		}
+	}
+	k, _ := manifest.LookupKind(kind)
+	return im.importDefinitionsOfKind(k, scope, defs)
+}
+
+func (im *importer) importDefinitionsOfKind(k manifest.Kind, scope manifest.Scope, defs []definition) error {
+	existing, err := k.List(im.ctx, im.client, scope)
+	if err != nil {
+		return fmt.Errorf("listing %s resources: %w", k.Name, err)
+	}
+	return im.reconcile(k.Name, scope.Dataset, defs, existing, k.Key,
+		func(body Object) (Object, error) {
+			data, err := json.Marshal(body)
+			if err != nil {
+				return nil, err
+			}
+			return k.Create(im.ctx, im.client, scope, data)
+		},
+		func(id string, body Object) (Object, error) {
+			data, err := json.Marshal(body)
+			if err != nil {
+				return nil, err
+			}
+			return k.Update(im.ctx, im.client, scope, id, data)
+		})
+}
+
+// reconcile creates each definition that has no existing match by key, and
+// updates matches when the Update option is set, recording the target ID of
+// every definition.
+func (im *importer) reconcile(
+	kind, dataset string,
+	defs []definition,
+	existing []Object,
+	key func(Object) string,
+	create func(body Object) (Object, error),
+	update func(id string, body Object) (Object, error),
+) error {
+	byKey := map[string]string{}
+	for _, obj := range existing {
+		if k := key(obj); k != "" {
+			byKey[k] = manifest.ObjectID(obj)
+		}
+	}
+
+	for _, d := range defs {
+		entry := Entry{Kind: kind, Dataset: dataset, Name: nameOf(kind, d.obj), SourceID: d.id, Path: d.path}
+
+		id, exists := byKey[key(d.obj)]
+		switch {
+		case exists && !im.opts.Update:
+			entry.Status = StatusExists
+		case exists:
+			if _, err := update(id, d.obj); err != nil {
+				return fmt.Errorf("updating %s %q: %w", kind, entry.Name, err)
+			}
+			entry.Status = StatusUpdated
+		default:
+			created, err := create(d.obj)
+			if err != nil {
+				return fmt.Errorf("creating %s %q: %w", kind, entry.Name, err)
+			}
+			id = manifest.ObjectID(created)
+			entry.Status = StatusCreated
+		}
+
+		entry.ID = id
+		im.ids[kind+"/"+d.id] = id
+		im.entries = append(im.entries, entry)
+	}
+	return nil
+}
+
+// remap replaces the source ID in obj[field] with the target ID of the
+// resource of the given kind. An unset field is left alone.
+func (im *importer) remap(obj map[string]any, kind, field string) error {
+	source, _ := obj[field].(string)
+	if source == "" {
+		return nil
+	}
+	id, ok := im.ids[kind+"/"+source]
+	if !ok {
+		return fmt.Errorf("references %s %s, which is not in the export", kind, source)
+	}
+	obj[field] = id
+	return nil
+}
+
+func (im *importer) remapRecipients(obj Object) error {
+	recipients, _ := obj["recipients"].([]any)
+	for _, r := range recipients {
+		if m, ok := r.(map[string]any); ok {
+			if err := im.remap(m, manifest.KindRecipient, "id"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// remapPanels rewrites the query, query annotation, and SLO IDs of a board's
+// panels.
+func (im *importer) remapPanels(obj Object) error {
+	panels, _ := obj["panels"].([]any)
+	for _, p := range panels {
+		panel, _ := p.(map[string]any)
+		if q, ok := panel["query_panel"].(map[string]any); ok {
+			if err := im.remap(q, KindQuery, "query_id"); err != nil {
+				return err
+			}
+			if err := im.remap(q, KindQueryAnnotation, "query_annotation_id"); err != nil {
+				return err
+			}
+		}
+		if s, ok := panel["slo_panel"].(map[string]any); ok {
+			if err := im.remap(s, manifest.KindSLO, "slo_id"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func jsonBody(v any) *bytes.Reader {
+	data, _ := json.Marshal(v)
+	return bytes.NewReader(data)
+}
@@ -0,0 +1,369 @@
+// Package cassette records HTTP interactions to a directory and replays them,
+// so that a session against the Honeycomb API can be captured once and run
+// again offline, in tests and in bug reports.
+//
+// Each interaction is a JSON file named for its sequence number, method, and
+// path. Credentials are scrubbed from recorded headers, as are secret fields
+// of JSON request and response bodies, such as the secret of a created API
+// key or a recipient's webhook secret. Replay ignores headers entirely, so a
+// recording works with any key.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Environment variables naming the directory to record to or replay from.
+const (
+	RecordEnv = "HONEYCOMB_RECORD"
+	ReplayEnv = "HONEYCOMB_REPLAY"
+)
+
+// redacted replaces the value of credential headers and secret fields.
+const redacted = "REDACTED"
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. URL holds the path and query only, so a
+// recording replays against any API URL.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// FromEnv wraps next in a Recorder or Replayer when HONEYCOMB_RECORD or
+// HONEYCOMB_REPLAY is set, and returns next unchanged otherwise.
+func FromEnv(next http.RoundTripper) (http.RoundTripper, error) {
+	record, replay := os.Getenv(RecordEnv), os.Getenv(ReplayEnv)
+	switch {
+	case record != "" && replay != "":
+		return nil, fmt.Errorf("only one of %s and %s can be set", RecordEnv, ReplayEnv)
+	case record != "":
+		return NewRecorder(record, next), nil
+	case replay != "":
+		return NewReplayer(replay)
+	default:
+		return next, nil
+	}
+}
+
+// Recorder is an http.RoundTripper that sends requests with the next
+// transport and records each interaction to a directory.
+type Recorder struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecorder returns a Recorder writing to dir, which is created on the
+// first request. Interactions already in dir are kept, and new ones are
+// numbered after them.
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("recording request: %w", err)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("recording response: %w", err)
+	}
+
+	err = r.write(Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.RequestURI(),
+			Headers: scrub(req.Header),
+			Body:    string(scrubBody(reqBody)),
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: scrub(resp.Header),
+			Body:    string(scrubBody(respBody)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+var unsafePath = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// write saves an interaction as the next file in the directory. Creating the
+// file exclusively keeps concurrent recorders from overwriting each other.
+func (r *Recorder) write(in Interaction) error {
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding interaction: %w", err)
+	}
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		return fmt.Errorf("creating cassette directory: %w", err)
+	}
+
+	path, _, _ := strings.Cut(in.Request.URL, "?")
+	name := strings.ToLower(in.Request.Method) + "-" + strings.Trim(unsafePath.ReplaceAllString(path, "-"), "-")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+
+	files, err := interactionFiles(r.dir)
+	if err != nil {
+		return err
+	}
+	for seq := len(files) + 1; ; seq++ {
+		f, err := os.OpenFile(filepath.Join(r.dir, fmt.Sprintf("%04d-%s.json", seq, name)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("writing interaction: %w", err)
+		}
+		_, err = f.Write(append(data, '\n'))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing interaction: %w", err)
+		}
+		return nil
+	}
+}
+
+// Replayer is an http.RoundTripper that answers requests with recorded
+// responses, without the network.
+type Replayer struct {
+	dir          string
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+var (
+	replayersMu sync.Mutex
+	replayers   = map[string]*Replayer{}
+)
+
+// NewReplayer returns the Replayer of dir. Replayers are shared within a
+// process, so a session that builds a client per command replays its
+// interactions in order.
+func NewReplayer(dir string) (*Replayer, error) {
+	replayersMu.Lock()
+	defer replayersMu.Unlock()
+	if r, ok := replayers[dir]; ok {
+		return r, nil
+	}
+
+	files, err := interactionFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recorded interactions in %s", dir)
+	}
+
+	r := &Replayer{dir: dir}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading interaction: %w", err)
+		}
+		var in Interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("parsing interaction %s: %w", filepath.Base(f), err)
+		}
+		r.interactions = append(r.interactions, in)
+	}
+	r.used = make([]bool, len(r.interactions))
+
+	replayers[dir] = r
+	return r, nil
+}
+
+// RoundTrip answers req with the first unused interaction of the same method
+// and URL, preferring one with the same body, since bodies can hold values
+// that change between runs, such as timestamps. Once every match has been
+// used, the last one answers again, as for a polled result.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("replaying request: %w", err)
+	}
+	uri := req.URL.RequestURI()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match, sameBody, last := -1, -1, -1
+	for i, in := range r.interactions {
+		if in.Request.Method != req.Method || in.Request.URL != uri {
+			continue
+		}
+		last = i
+		if r.used[i] {
+			continue
+		}
+		if match < 0 {
+			match = i
+		}
+		if sameBody < 0 && in.Request.Body == string(scrubBody(body)) {
+			sameBody = i
+		}
+	}
+	switch {
+	case sameBody >= 0:
+		match = sameBody
+	case match < 0:
+		match = last
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s in %s", req.Method, uri, r.dir)
+	}
+	r.used[match] = true
+
+	in := r.interactions[match]
+	return &http.Response{
+		Status:        strconv.Itoa(in.Response.Status) + " " + http.StatusText(in.Response.Status),
+		StatusCode:    in.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        in.Response.Headers.Clone(),
+		Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// interactionFiles returns the interaction files of dir in sequence order.
+func interactionFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "[0-9]*.json"))
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(files, func(a, b string) int {
+		return sequence(a) - sequence(b)
+	})
+	return files, nil
+}
+
+func sequence(path string) int {
+	prefix, _, _ := strings.Cut(filepath.Base(path), "-")
+	n, _ := strconv.Atoi(strings.TrimSuffix(prefix, ".json"))
+	return n
+}
+
+// readBody reads a request or response body and replaces it with a copy, so
+// it can still be read by the caller.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// secretField matches the names of fields holding credentials, such as
+// secret, webhook_secret, pagerduty_integration_key, token, and password.
+var secretField = regexp.MustCompile(`^(.*_)?(secret|token|password|integration_key)$`)
+
+// scrubBody returns a JSON body with the value of every secret field
+// redacted, at any depth. Other bodies are returned unchanged.
+func scrubBody(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || !scrubSecrets(v) {
+		return body
+	}
+	scrubbed, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return scrubbed
+}
+
+// scrubSecrets redacts secret fields in v, reporting whether it found any.
+func scrubSecrets(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if secretField.MatchString(strings.ToLower(k)) {
+				if _, ok := child.(string); ok {
+					v[k] = redacted
+					found = true
+					continue
+				}
+			}
+			if scrubSecrets(child) {
+				found = true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if scrubSecrets(child) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// scrub returns a copy of h with credentials redacted.
+func scrub(h http.Header) http.Header {
+	h = h.Clone()
+	if h.Get("X-Honeycomb-Team") != "" {
+		h.Set("X-Honeycomb-Team", redacted)
+	}
+	if auth := h.Get("Authorization"); auth != "" {
+		scheme, _, _ := strings.Cut(auth, " ")
+		h.Set("Authorization", scheme+" "+redacted)
+	}
+	for _, name := range []string{"Cookie", "Set-Cookie"} {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	return h
+}
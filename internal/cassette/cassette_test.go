@@ -0,0 +1,254 @@
+package cassette
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func do(t *testing.T, rt http.RoundTripper, method, url, body string, header http.Header) (int, string) {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+func TestRecordReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+			return
+		}
+		_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(calls) + `}`))
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "session")
+	rec := NewRecorder(dir, nil)
+	auth := http.Header{
+		"X-Honeycomb-Team": {"secret-config-key"},
+		"Authorization":    {"Bearer id:secret"},
+	}
+
+	if status, body := do(t, rec, http.MethodGet, srv.URL+"/1/boards?limit=1", "", auth); status != 200 || body != `{"call":1}` {
+		t.Fatalf("recorded GET = %d %s", status, body)
+	}
+	if status, body := do(t, rec, http.MethodPost, srv.URL+"/1/markers/api", `{"message":"a"}`, auth); status != 201 || body != `{"message":"a"}` {
+		t.Fatalf("recorded POST = %d %s", status, body)
+	}
+	do(t, rec, http.MethodPost, srv.URL+"/1/markers/api", `{"message":"b"}`, auth)
+	do(t, rec, http.MethodGet, srv.URL+"/1/boards?limit=1", "", auth)
+
+	files, err := interactionFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 4 || filepath.Base(files[0]) != "0001-get-1-boards.json" {
+		t.Fatalf("files = %v", files)
+	}
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		if strings.Contains(string(data), "secret") {
+			t.Errorf("%s contains a credential:\n%s", filepath.Base(f), data)
+		}
+	}
+	var in Interaction
+	data, _ := os.ReadFile(files[0])
+	if err := json.Unmarshal(data, &in); err != nil {
+		t.Fatal(err)
+	}
+	if in.Request.URL != "/1/boards?limit=1" || in.Request.Headers.Get("Authorization") != "Bearer REDACTED" || in.Request.Headers.Get("X-Honeycomb-Team") != "REDACTED" {
+		t.Errorf("request = %+v", in.Request)
+	}
+
+	srv.Close()
+	rep, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status, body := do(t, rep, http.MethodPost, "http://replay.invalid/1/markers/api", `{"message":"b"}`, nil); status != 201 || body != `{"message":"b"}` {
+		t.Errorf("replayed POST with matching body = %d %s", status, body)
+	}
+	if _, body := do(t, rep, http.MethodPost, "http://replay.invalid/1/markers/api", `{"message":"changed"}`, nil); body != `{"message":"a"}` {
+		t.Errorf("replayed POST with changed body = %s, want the unused recording", body)
+	}
+	for _, want := range []string{`{"call":1}`, `{"call":4}`, `{"call":4}`} {
+		if _, body := do(t, rep, http.MethodGet, "http://replay.invalid/1/boards?limit=1", "", nil); body != want {
+			t.Errorf("replayed GET = %s, want %s", body, want)
+		}
+	}
+
+	again, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != rep {
+		t.Error("replayer of the same directory is not shared")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://replay.invalid/1/triggers/api", nil)
+	if _, err := rep.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "GET /1/triggers/api") {
+		t.Errorf("err = %v, want an error naming the request", err)
+	}
+}
+
+func TestRecord_RecipientSecret(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "session")
+	body := `{"type":"webhook","details":{"webhook_name":"ci","webhook_url":"https://example.com","webhook_secret":"hunter2"}}`
+	if status, got := do(t, NewRecorder(dir, nil), http.MethodPost, srv.URL+"/1/recipients", body, nil); status != 201 || got != body {
+		t.Fatalf("recorded POST = %d %s", status, got)
+	}
+
+	files, err := interactionFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("recording contains the webhook secret:\n%s", data)
+	}
+
+	rep, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, got := do(t, rep, http.MethodPost, "http://replay.invalid/1/recipients", body, nil); status != 201 || strings.Contains(got, "hunter2") {
+		t.Errorf("replayed POST = %d %s", status, got)
+	}
+}
+
+func TestRecorder_Appends(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	do(t, NewRecorder(dir, nil), http.MethodGet, srv.URL+"/1/auth", "", nil)
+	do(t, NewRecorder(dir, nil), http.MethodGet, srv.URL+"/2/auth", "", nil)
+
+	files, err := interactionFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || filepath.Base(files[1]) != "0002-get-2-auth.json" {
+		t.Errorf("files = %v", files)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	next := http.DefaultTransport
+
+	rt, err := FromEnv(next)
+	if err != nil || rt != next {
+		t.Errorf("FromEnv() = %v, %v; want next unchanged", rt, err)
+	}
+
+	t.Setenv(RecordEnv, t.TempDir())
+	if rt, err := FromEnv(next); err != nil {
+		t.Error(err)
+	} else if _, ok := rt.(*Recorder); !ok {
+		t.Errorf("FromEnv() = %T, want *Recorder", rt)
+	}
+
+	t.Setenv(ReplayEnv, t.TempDir())
+	if _, err := FromEnv(next); err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Errorf("err = %v, want a conflict error", err)
+	}
+
+	t.Setenv(RecordEnv, "")
+	if _, err := FromEnv(next); err == nil || !strings.Contains(err.Error(), "no recorded interactions") {
+		t.Errorf("err = %v, want an error for an empty directory", err)
+	}
+}
+
+func TestScrub(t *testing.T) {
+	h := http.Header{
+		"X-Honeycomb-Team": {"key"},
+		"Authorization":    {"Bearer id:secret"},
+		"Content-Type":     {"application/json"},
+	}
+	got := scrub(h)
+	if got.Get("X-Honeycomb-Team") != "REDACTED" || got.Get("Authorization") != "Bearer REDACTED" || got.Get("Content-Type") != "application/json" {
+		t.Errorf("scrub() = %v", got)
+	}
+	if h.Get("X-Honeycomb-Team") != "key" {
+		t.Error("scrub modified its argument")
+	}
+}
+
+func TestScrubBody(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "key secret",
+			in:   `{"data":{"id":"hcxik_1","attributes":{"name":"ci","secret":"s3cr3t","count":12345678901234567890}}}`,
+			want: `{"data":{"attributes":{"count":12345678901234567890,"name":"ci","secret":"REDACTED"},"id":"hcxik_1"}}`,
+		},
+		{
+			name: "list",
+			in:   `[{"secret":"a"},{"name":"b"}]`,
+			want: `[{"secret":"REDACTED"},{"name":"b"}]`,
+		},
+		{
+			name: "recipient",
+			in:   `{"type":"webhook","details":{"webhook_name":"ci","webhook_secret":"w","pagerduty_integration_key":"p","auth":{"token":"t","password":"pw"}}}`,
+			want: `{"details":{"auth":{"password":"REDACTED","token":"REDACTED"},"pagerduty_integration_key":"REDACTED","webhook_name":"ci","webhook_secret":"REDACTED"},"type":"webhook"}`,
+		},
+		{
+			name: "no secret",
+			in:   `{"name": "unchanged"}`,
+			want: `{"name": "unchanged"}`,
+		},
+		{
+			name: "not json",
+			in:   `secret=abc`,
+			want: `secret=abc`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(scrubBody([]byte(tc.in))); got != tc.want {
+				t.Errorf("scrubBody() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
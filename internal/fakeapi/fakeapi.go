@@ -0,0 +1,323 @@
+// Package fakeapi is an in-memory fake of the Honeycomb API, for developing
+// and testing against without a Honeycomb team or the network.
+//
+// The fake serves the Configuration API resources the CLI manages (datasets,
+// columns, calculated fields, boards and their views, triggers, SLOs, burn
+// alerts, markers, recipients, queries, and query annotations), query results
+// computed from canned data, and the Management API's environments and API keys. Every
+// resource starts empty apart from the team's default environment, and lives
+// only as long as the Server.
+//
+// Any non-empty key is accepted: the Configuration API takes it in the
+// X-Honeycomb-Team header, and the Management API as a bearer token.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Team is the slug of the team the Management API serves. It defaults
+	// to "dev".
+	Team string
+	// Environment is the name of the team's default environment, whose
+	// resources the Configuration API serves. It defaults to "development".
+	Environment string
+}
+
+// Server is an http.Handler serving the fake API.
+type Server struct {
+	team        string
+	environment string
+	mux         *http.ServeMux
+
+	mu           sync.Mutex
+	seq          int
+	datasets     *collection
+	definitions  map[string]map[string]any
+	collections  map[string]*collection
+	queries      map[string]map[string]any
+	results      map[string]map[string]any
+	queryData    map[string][]map[string]any
+	environments *collection
+	keys         *collection
+}
+
+// New returns a Server with no resources apart from the default environment.
+func New(opts Options) *Server {
+	if opts.Team == "" {
+		opts.Team = "dev"
+	}
+	if opts.Environment == "" {
+		opts.Environment = "development"
+	}
+
+	s := &Server{
+		team:         opts.Team,
+		environment:  opts.Environment,
+		mux:          http.NewServeMux(),
+		datasets:     &collection{idField: "slug"},
+		definitions:  map[string]map[string]any{},
+		collections:  map[string]*collection{},
+		queries:      map[string]map[string]any{},
+		results:      map[string]map[string]any{},
+		queryData:    map[string][]map[string]any{},
+		environments: &collection{idField: "id"},
+		keys:         &collection{idField: "id"},
+	}
+	s.environments.add(s.newEnvironment(opts.Environment, "", "blue"))
+	s.routes()
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Seed adds an example dataset with a few typical columns, so queries and
+// column commands have something to show.
+func (s *Server) Seed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ds := s.newDataset(map[string]any{
+		"name":        "example",
+		"description": "Example dataset served by the fake API",
+	})
+	s.datasets.add(ds)
+
+	columns := s.collection("columns", "example")
+	for _, c := range []struct{ name, typ string }{
+		{"service.name", "string"},
+		{"name", "string"},
+		{"duration_ms", "float"},
+		{"http.status_code", "integer"},
+		{"error", "boolean"},
+		{"trace.trace_id", "string"},
+	} {
+		col := map[string]any{"key_name": c.name, "type": c.typ}
+		s.stamp(col)
+		col["last_written"] = col["created_at"]
+		columns.add(col)
+	}
+	ds["regular_columns_count"] = len(columns.items)
+}
+
+// SetQueryData replaces the canned data of queries on dataset. Each row is a
+// group of a query result: its breakdown values and calculation results,
+// keyed as in the API, such as "service.name" and "P99(duration_ms)". Rows
+// are returned as they are, without regard to the query that was run.
+func (s *Server) SetQueryData(dataset string, rows []map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queryData[dataset] = rows
+}
+
+func (s *Server) routes() {
+	s.handleResource(resource{kind: "boards", path: "/1/boards", idParam: "boardId", name: "board", prepare: s.prepareBoard})
+	s.handleResource(resource{kind: "board_views", path: "/1/boards/{boardId}/views", idParam: "viewId", name: "board view", parent: "boardId", parentKind: "boards"})
+	s.handleResource(resource{kind: "recipients", path: "/1/recipients", idParam: "recipientId", name: "recipient"})
+	s.handleResource(resource{kind: "columns", path: "/1/columns/{datasetSlug}", idParam: "columnId", name: "column", unique: "key_name", prepare: prepareColumn})
+	s.handleResource(resource{kind: "derived_columns", path: "/1/derived_columns/{datasetSlug}", idParam: "derivedColumnId", name: "calculated field", unique: "alias"})
+	s.handleResource(resource{kind: "triggers", path: "/1/triggers/{datasetSlug}", idParam: "triggerId", name: "trigger", prepare: s.prepareTrigger})
+	s.handleResource(resource{kind: "slos", path: "/1/slos/{datasetSlug}", idParam: "sloId", name: "SLO", prepare: prepareSLO})
+	s.handleResource(resource{kind: "burn_alerts", path: "/1/burn_alerts/{datasetSlug}", idParam: "burnAlertId", name: "burn alert", filter: matchSLO})
+	s.handleResource(resource{kind: "markers", path: "/1/markers/{datasetSlug}", idParam: "markerId", name: "marker", deleteBody: true, prepare: prepareMarker})
+	s.handleResource(resource{kind: "marker_settings", path: "/1/marker_settings/{datasetSlug}", idParam: "markerSettingId", name: "marker setting", deleteBody: true})
+	s.handleResource(resource{kind: "query_annotations", path: "/1/query_annotations/{datasetSlug}", idParam: "queryAnnotationId", name: "query annotation", prepare: prepareAnnotation})
+
+	s.mux.HandleFunc("GET /1/auth", s.v1(s.getAuth))
+	s.mux.HandleFunc("GET /1/recipients/{recipientId}/triggers", s.v1(s.listRecipientTriggers))
+
+	s.mux.HandleFunc("GET /1/datasets", s.v1(s.listDatasets))
+	s.mux.HandleFunc("POST /1/datasets", s.v1(s.createDataset))
+	s.mux.HandleFunc("GET /1/datasets/{datasetSlug}", s.v1(s.getDataset))
+	s.mux.HandleFunc("PUT /1/datasets/{datasetSlug}", s.v1(s.updateDataset))
+	s.mux.HandleFunc("DELETE /1/datasets/{datasetSlug}", s.v1(s.deleteDataset))
+	s.mux.HandleFunc("GET /1/dataset_definitions/{datasetSlug}", s.v1(s.getDefinitions))
+	s.mux.HandleFunc("PATCH /1/dataset_definitions/{datasetSlug}", s.v1(s.updateDefinitions))
+
+	s.mux.HandleFunc("POST /1/queries/{datasetSlug}", s.v1(s.createQuery))
+	s.mux.HandleFunc("GET /1/queries/{datasetSlug}/{queryId}", s.v1(s.getQuery))
+	s.mux.HandleFunc("POST /1/query_results/{datasetSlug}", s.v1(s.createQueryResult))
+	s.mux.HandleFunc("GET /1/query_results/{datasetSlug}/{queryResultId}", s.v1(s.getQueryResult))
+
+	s.mux.HandleFunc("GET /2/auth", s.v2(s.getAuthV2))
+	s.mux.HandleFunc("GET /2/teams/{teamSlug}/environments", s.v2(s.listEnvironments))
+	s.mux.HandleFunc("POST /2/teams/{teamSlug}/environments", s.v2(s.createEnvironment))
+	s.mux.HandleFunc("GET /2/teams/{teamSlug}/environments/{ID}", s.v2(s.getEnvironment))
+	s.mux.HandleFunc("PATCH /2/teams/{teamSlug}/environments/{ID}", s.v2(s.updateEnvironment))
+	s.mux.HandleFunc("DELETE /2/teams/{teamSlug}/environments/{ID}", s.v2(s.deleteEnvironment))
+	s.mux.HandleFunc("GET /2/teams/{teamSlug}/api-keys", s.v2(s.listKeys))
+	s.mux.HandleFunc("POST /2/teams/{teamSlug}/api-keys", s.v2(s.createKey))
+	s.mux.HandleFunc("GET /2/teams/{teamSlug}/api-keys/{ID}", s.v2(s.getKey))
+	s.mux.HandleFunc("PATCH /2/teams/{teamSlug}/api-keys/{ID}", s.v2(s.updateKey))
+	s.mux.HandleFunc("DELETE /2/teams/{teamSlug}/api-keys/{ID}", s.v2(s.deleteKey))
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s is not served by the fake API", r.Method, r.URL.Path))
+	})
+}
+
+// v1 wraps a Configuration API handler, requiring a key and serializing
+// access to the server's state.
+func (s *Server) v1(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Honeycomb-Team") == "" {
+			writeError(w, http.StatusUnauthorized, "unknown API key - check your credentials")
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		h(w, r)
+	}
+}
+
+// v2 wraps a Management API handler, requiring a bearer key and, for team
+// routes, the server's team.
+func (s *Server) v2(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			writeErrorV2(w, http.StatusUnauthorized, "missing or invalid management key")
+			return
+		}
+		if team := r.PathValue("teamSlug"); team != "" && team != s.team {
+			writeErrorV2(w, http.StatusNotFound, fmt.Sprintf("team %q not found", team))
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		h(w, r)
+	}
+}
+
+func (s *Server) getAuth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":   "fake-configuration-key",
+		"type": "configuration",
+		"api_key_access": map[string]any{
+			"events":         true,
+			"markers":        true,
+			"triggers":       true,
+			"boards":         true,
+			"queries":        true,
+			"columns":        true,
+			"createDatasets": true,
+			"slos":           true,
+			"recipients":     true,
+			"privateBoards":  true,
+		},
+		"environment": map[string]any{"name": s.environment, "slug": slugify(s.environment)},
+		"team":        map[string]any{"name": s.team, "slug": s.team},
+	})
+}
+
+func (s *Server) getAuthV2(w http.ResponseWriter, _ *http.Request) {
+	teamID := "hcxtm_" + fmt.Sprintf("%026d", 1)
+	now := s.now()
+	writeJSONAPI(w, http.StatusOK, map[string]any{
+		"data": map[string]any{
+			"id":   "hcxmk_" + fmt.Sprintf("%026d", 1),
+			"type": "api-keys",
+			"attributes": map[string]any{
+				"name":       "fake management key",
+				"key_type":   "management",
+				"disabled":   false,
+				"scopes":     []string{"api-keys:write", "environments:write"},
+				"timestamps": map[string]any{"created": now, "updated": now},
+			},
+			"relationships": map[string]any{
+				"team": map[string]any{"data": map[string]any{"id": teamID, "type": "teams"}},
+			},
+		},
+		"included": []any{
+			map[string]any{
+				"id":         teamID,
+				"type":       "teams",
+				"attributes": map[string]any{"name": s.team, "slug": s.team},
+			},
+		},
+	})
+}
+
+// newID returns the next resource ID. IDs are sequential, so a test that
+// creates resources in order knows their IDs.
+func (s *Server) newID() string {
+	s.seq++
+	return fmt.Sprintf("fake%07d", s.seq)
+}
+
+// newPrefixedID returns the next ID in the form of Management API IDs.
+func (s *Server) newPrefixedID(prefix string) string {
+	s.seq++
+	return prefix + fmt.Sprintf("%026d", s.seq)
+}
+
+func (s *Server) now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// stamp sets the creation and update times of a new resource.
+func (s *Server) stamp(obj map[string]any) {
+	now := s.now()
+	obj["created_at"] = now
+	obj["updated_at"] = now
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify derives a URL slug from a name, as Honeycomb does for datasets
+// and environments.
+func slugify(name string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// readBody decodes a JSON object request body. Numbers are kept as
+// json.Number so they are returned as sent.
+func readBody(r *http.Request) (map[string]any, error) {
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	var body map[string]any
+	if err := dec.Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if body == nil {
+		return nil, fmt.Errorf("request body must be a JSON object")
+	}
+	return body, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONAPI(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"error": message})
+}
+
+func writeErrorV2(w http.ResponseWriter, status int, detail string) {
+	writeJSONAPI(w, status, map[string]any{
+		"errors": []any{
+			map[string]any{
+				"status": fmt.Sprint(status),
+				"title":  http.StatusText(status),
+				"detail": detail,
+			},
+		},
+	})
+}
@@ -0,0 +1,440 @@
+package fakeapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+func setupTest(t *testing.T) (*Server, *api.ClientWithResponses, string) {
+	t.Helper()
+	fake := New(Options{Team: "test-team"})
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client, err := api.NewClientWithResponses(srv.URL, api.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("X-Honeycomb-Team", "config-key")
+		req.Header.Set("Authorization", "Bearer id:secret")
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fake, client, srv.URL
+}
+
+func TestAuth(t *testing.T) {
+	_, client, url := setupTest(t)
+
+	resp, err := client.GetAuthWithResponse(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.JSON200 == nil || resp.JSON200.Team.Slug == nil || *resp.JSON200.Team.Slug != "test-team" {
+		t.Errorf("auth = %s", resp.Body)
+	}
+
+	v2, err := client.GetV2AuthWithResponse(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2.ApplicationvndApiJSON200 == nil {
+		t.Errorf("v2 auth = %d %s", v2.StatusCode(), v2.Body)
+	}
+
+	for _, path := range []string{"/1/datasets", "/2/teams/test-team/environments"} {
+		r, err := http.Get(url + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = r.Body.Close()
+		if r.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s without a key = %d, want 401", path, r.StatusCode)
+		}
+	}
+}
+
+func TestDatasets(t *testing.T) {
+	_, client, _ := setupTest(t)
+	ctx := t.Context()
+
+	created, err := client.CreateDatasetWithResponse(ctx, api.CreateDatasetJSONRequestBody{Name: "My API"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.JSON201 == nil || created.JSON201.Slug == nil || *created.JSON201.Slug != "my-api" {
+		t.Fatalf("create = %d %s", created.StatusCode(), created.Body)
+	}
+	again, err := client.CreateDatasetWithResponse(ctx, api.CreateDatasetJSONRequestBody{Name: "My API"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.StatusCode() != http.StatusOK {
+		t.Errorf("create existing = %d, want 200", again.StatusCode())
+	}
+
+	col, err := client.CreateColumnWithResponse(ctx, "my-api", api.CreateColumnJSONRequestBody{KeyName: "duration_ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if col.JSON201 == nil || col.JSON201.Id == nil || col.JSON201.Type == nil || *col.JSON201.Type != "string" {
+		t.Fatalf("create column = %d %s", col.StatusCode(), col.Body)
+	}
+	dup, err := client.CreateColumnWithResponse(ctx, "my-api", api.CreateColumnJSONRequestBody{KeyName: "duration_ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dup.StatusCode() != http.StatusConflict {
+		t.Errorf("duplicate column = %d, want 409", dup.StatusCode())
+	}
+	missing, err := client.ListColumnsWithResponse(ctx, "missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.CheckResponse(missing.StatusCode(), missing.Body); err == nil || !strings.Contains(err.Error(), `dataset "missing" not found`) {
+		t.Errorf("columns of a missing dataset: %v", err)
+	}
+
+	list, err := client.ListDatasetsWithResponse(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.JSON200 == nil || len(*list.JSON200) != 1 {
+		t.Fatalf("list = %s", list.Body)
+	}
+	if n, err := (*list.JSON200)[0].RegularColumnsCount.Get(); err != nil || n != 1 {
+		t.Errorf("regular_columns_count = %d, %v; want 1", n, err)
+	}
+
+	protected, err := client.DeleteDatasetWithResponse(ctx, "my-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.CheckResponse(protected.StatusCode(), protected.Body); err == nil || !strings.Contains(err.Error(), "delete protected") {
+		t.Errorf("delete protected dataset: %v", err)
+	}
+	unprotect, err := client.UpdateDatasetWithBodyWithResponse(ctx, "my-api", "application/json", strings.NewReader(`{"settings": {"delete_protected": false}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unprotect.JSON200 == nil || unprotect.JSON200.Settings == nil || *unprotect.JSON200.Settings.DeleteProtected {
+		t.Fatalf("update = %d %s", unprotect.StatusCode(), unprotect.Body)
+	}
+
+	deleted, err := client.DeleteDatasetWithResponse(ctx, "my-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.StatusCode() != http.StatusAccepted {
+		t.Errorf("delete = %d %s", deleted.StatusCode(), deleted.Body)
+	}
+	columns, err := client.ListColumnsWithResponse(ctx, "my-api", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if columns.StatusCode() != http.StatusNotFound {
+		t.Errorf("columns of a deleted dataset = %d, want 404", columns.StatusCode())
+	}
+}
+
+func TestResources(t *testing.T) {
+	fake, client, _ := setupTest(t)
+	fake.Seed()
+	ctx := t.Context()
+
+	board, err := client.CreateBoardWithBodyWithResponse(ctx, "application/json", strings.NewReader(`{"name": "Latency", "type": "flexible"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.JSON201 == nil || board.JSON201.Id == nil || board.JSON201.Links == nil {
+		t.Fatalf("create board = %d %s", board.StatusCode(), board.Body)
+	}
+	id := *board.JSON201.Id
+
+	updated, err := client.UpdateBoardWithBodyWithResponse(ctx, id, "application/json", strings.NewReader(`{"name": "Latency v2", "type": "flexible"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.JSON200 == nil || updated.JSON200.Name != "Latency v2" || *updated.JSON200.Id != id {
+		t.Errorf("update board = %d %s", updated.StatusCode(), updated.Body)
+	}
+
+	if _, err := client.DeleteBoardWithResponse(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetBoardWithResponse(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.StatusCode() != http.StatusNotFound {
+		t.Errorf("deleted board = %d, want 404", got.StatusCode())
+	}
+
+	recipient, err := client.CreateRecipientWithBodyWithResponse(ctx, "application/json", strings.NewReader(`{"type": "email", "details": {"email_address": "oncall@example.com"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rcpt struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(recipient.Body, &rcpt); err != nil || recipient.StatusCode() != http.StatusCreated {
+		t.Fatalf("create recipient = %d %s", recipient.StatusCode(), recipient.Body)
+	}
+
+	trigger, err := client.CreateTriggerWithBodyWithResponse(ctx, "example", "application/json", strings.NewReader(`{
+		"name": "Slow",
+		"query": {"calculations": [{"op": "P99", "column": "duration_ms"}], "time_range": 900},
+		"threshold": {"op": ">", "value": 500},
+		"frequency": 900,
+		"recipients": [{"id": "`+rcpt.ID+`"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trigger.JSON201 == nil || trigger.JSON201.QueryId == nil {
+		t.Fatalf("create trigger = %d %s", trigger.StatusCode(), trigger.Body)
+	}
+
+	triggers, err := client.ListTriggersWithRecipientWithResponse(ctx, rcpt.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if triggers.JSON200 == nil || len(*triggers.JSON200) != 1 {
+		t.Errorf("recipient triggers = %d %s", triggers.StatusCode(), triggers.Body)
+	}
+
+	marker, err := client.CreateMarkerWithBodyWithResponse(ctx, "example", "application/json", strings.NewReader(`{"message": "deploy"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marker.StatusCode() != http.StatusCreated || !strings.Contains(string(marker.Body), `"start_time"`) {
+		t.Errorf("create marker = %d %s", marker.StatusCode(), marker.Body)
+	}
+}
+
+func TestBurnAlertsBySLO(t *testing.T) {
+	fake, client, _ := setupTest(t)
+	fake.Seed()
+	ctx := t.Context()
+
+	for _, slo := range []string{"slo-1", "slo-1", "slo-2"} {
+		resp, err := client.CreateBurnAlertWithBodyWithResponse(ctx, "example", "application/json", strings.NewReader(`{"alert_type": "exhaustion_time", "exhaustion_minutes": 60, "slo": {"id": "`+slo+`"}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode() != http.StatusCreated {
+			t.Fatalf("create burn alert = %d %s", resp.StatusCode(), resp.Body)
+		}
+	}
+
+	for slo, want := range map[string]int{"slo-1": 2, "slo-2": 1, "slo-3": 0} {
+		resp, err := client.ListBurnAlertsBySloWithResponse(ctx, "example", &api.ListBurnAlertsBySloParams{SloId: slo})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var alerts []json.RawMessage
+		if err := json.Unmarshal(resp.Body, &alerts); err != nil {
+			t.Fatalf("list burn alerts = %d %s", resp.StatusCode(), resp.Body)
+		}
+		if len(alerts) != want {
+			t.Errorf("burn alerts of %s = %d, want %d", slo, len(alerts), want)
+		}
+	}
+}
+
+func TestBoardViews(t *testing.T) {
+	_, client, _ := setupTest(t)
+	ctx := t.Context()
+
+	board, err := client.CreateBoardWithBodyWithResponse(ctx, "application/json", strings.NewReader(`{"name": "Latency", "type": "flexible"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.JSON201 == nil || board.JSON201.Id == nil {
+		t.Fatalf("create board = %d %s", board.StatusCode(), board.Body)
+	}
+	boardID := *board.JSON201.Id
+
+	created, err := client.CreateBoardViewWithBodyWithResponse(ctx, boardID, "application/json", strings.NewReader(`{"name": "Errors", "filters": []}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var view struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(created.Body, &view); err != nil || created.StatusCode() != http.StatusCreated || view.ID == "" {
+		t.Fatalf("create view = %d %s", created.StatusCode(), created.Body)
+	}
+
+	updated, err := client.UpdateBoardViewWithBodyWithResponse(ctx, boardID, view.ID, "application/json", strings.NewReader(`{"name": "Errors v2", "filters": []}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.StatusCode() != http.StatusOK || !strings.Contains(string(updated.Body), `"Errors v2"`) {
+		t.Errorf("update view = %d %s", updated.StatusCode(), updated.Body)
+	}
+
+	list, err := client.ListBoardViewsWithResponse(ctx, boardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var views []json.RawMessage
+	if err := json.Unmarshal(list.Body, &views); err != nil || len(views) != 1 {
+		t.Errorf("list views = %d %s", list.StatusCode(), list.Body)
+	}
+
+	if _, err := client.DeleteBoardViewWithResponse(ctx, boardID, view.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetBoardViewWithResponse(ctx, boardID, view.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.StatusCode() != http.StatusNotFound {
+		t.Errorf("deleted view = %d, want 404", got.StatusCode())
+	}
+
+	missing, err := client.ListBoardViewsWithResponse(ctx, "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing.StatusCode() != http.StatusNotFound {
+		t.Errorf("views of missing board = %d, want 404", missing.StatusCode())
+	}
+}
+
+func TestQueryResults(t *testing.T) {
+	fake, client, _ := setupTest(t)
+	fake.Seed()
+	ctx := t.Context()
+
+	run := func(dataset, spec string, series bool) *api.QueryResultDetails {
+		t.Helper()
+		q, err := client.CreateQueryWithBodyWithResponse(ctx, dataset, "application/json", strings.NewReader(spec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.JSON200 == nil || q.JSON200.Id == nil {
+			t.Fatalf("create query = %d %s", q.StatusCode(), q.Body)
+		}
+		disable := !series
+		created, err := client.CreateQueryResultWithResponse(ctx, dataset, api.CreateQueryResultRequest{QueryId: q.JSON200.Id, DisableSeries: &disable})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.JSON201 == nil || created.JSON201.Id == nil {
+			t.Fatalf("create result = %d %s", created.StatusCode(), created.Body)
+		}
+		result, err := client.GetQueryResultWithResponse(ctx, dataset, *created.JSON201.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.JSON200 == nil || result.JSON200.Complete == nil || !*result.JSON200.Complete || result.JSON200.Data == nil {
+			t.Fatalf("get result = %d %s", result.StatusCode(), result.Body)
+		}
+		return result.JSON200
+	}
+
+	spec := `{"breakdowns": ["service.name"], "calculations": [{"op": "COUNT"}, {"op": "P99", "column": "duration_ms"}], "time_range": 3600, "granularity": 600}`
+	result := run("example", spec, true)
+	rows := *result.Data.Results
+	if len(rows) != cannedGroups {
+		t.Fatalf("results = %d groups, want %d", len(rows), cannedGroups)
+	}
+	first := *rows[0].Data
+	for _, key := range []string{"service.name", "COUNT", "P99(duration_ms)"} {
+		if _, ok := first[key]; !ok {
+			t.Errorf("result row missing %q: %v", key, first)
+		}
+	}
+	if series := *result.Data.Series; len(series) != 6*cannedGroups {
+		t.Errorf("series = %d points, want %d", len(series), 6*cannedGroups)
+	}
+
+	if again := run("example", spec, false); (*again.Data.Results)[0].Data == nil || (*(*again.Data.Results)[0].Data)["COUNT"] != first["COUNT"] || again.Data.Series != nil {
+		t.Errorf("rerun = %+v, want the same results without a series", again.Data)
+	}
+
+	fake.SetQueryData("example", []map[string]any{{"COUNT": 42}})
+	canned := run("example", spec, false)
+	if rows := *canned.Data.Results; len(rows) != 1 || (*rows[0].Data)["COUNT"] != float64(42) {
+		t.Errorf("canned results = %+v", rows)
+	}
+}
+
+func TestEnvironmentsAndKeys(t *testing.T) {
+	_, client, url := setupTest(t)
+	ctx := t.Context()
+
+	env, err := client.CreateEnvironmentWithBodyWithResponse(ctx, "test-team", "application/vnd.api+json", strings.NewReader(`{"data": {"type": "environments", "attributes": {"name": "Staging"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.ApplicationvndApiJSON201 == nil || env.ApplicationvndApiJSON201.Data.Attributes.Slug != "staging" {
+		t.Fatalf("create environment = %d %s", env.StatusCode(), env.Body)
+	}
+	envID := env.ApplicationvndApiJSON201.Data.Id
+
+	del, err := client.DeleteEnvironmentWithResponse(ctx, "test-team", envID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.CheckResponse(del.StatusCode(), del.Body); err == nil || !strings.Contains(err.Error(), "delete protected") {
+		t.Errorf("delete protected environment: %v", err)
+	}
+
+	for _, name := range []string{"one", "two", "three"} {
+		key, err := client.CreateApiKeyWithBodyWithResponse(ctx, "test-team", "application/vnd.api+json", strings.NewReader(`{"data": {
+			"type": "api-keys",
+			"attributes": {"key_type": "ingest", "name": "`+name+`"},
+			"relationships": {"environment": {"data": {"id": "`+envID+`", "type": "environments"}}}
+		}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.StatusCode() != http.StatusCreated || !strings.Contains(string(key.Body), `"secret"`) || !strings.Contains(string(key.Body), `"hcxik_`) {
+			t.Fatalf("create key = %d %s", key.StatusCode(), key.Body)
+		}
+	}
+
+	pages := api.NewPages(func(ctx context.Context, cursor string, _ int) ([]api.ApiKeyObject, *api.PaginationLinks, error) {
+		params := &api.ListApiKeysParams{}
+		params.PageAfter, params.PageSize = api.PageParams(cursor, 2)
+		resp, err := client.ListApiKeysWithResponse(ctx, "test-team", params)
+		if err != nil {
+			return nil, nil, err
+		}
+		list, err := api.Decode(resp.StatusCode(), resp.Status(), resp.Body, resp.ApplicationvndApiJSON200)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list.Data, list.Links, nil
+	}, 0)
+	var count, fetches int
+	for keys, err := range pages.All(ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		fetches++
+		count += len(keys)
+	}
+	if count != 3 || fetches != 2 {
+		t.Errorf("listed %d keys in %d pages, want 3 in 2", count, fetches)
+	}
+
+	r, err := http.Get(url + "/1/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Body.Close() }()
+	var body map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || r.StatusCode != http.StatusNotFound || !strings.Contains(body["error"], "not served by the fake API") {
+		t.Errorf("unknown route = %d %v", r.StatusCode, body)
+	}
+}
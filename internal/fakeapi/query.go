@@ -0,0 +1,281 @@
+package fakeapi
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	// cannedGroups is the number of groups in the canned result of a query
+	// with breakdowns.
+	cannedGroups = 3
+	// maxSeriesPoints bounds the time series of a canned result.
+	maxSeriesPoints = 100
+	// defaultTimeRange is the time range of a query that sets none, in
+	// seconds, as in Honeycomb.
+	defaultTimeRange = 7200
+)
+
+func queryKey(dataset, id string) string {
+	return dataset + "/" + id
+}
+
+func (s *Server) createQuery(w http.ResponseWriter, r *http.Request) {
+	scope, ok := s.scope(w, r)
+	if !ok {
+		return
+	}
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q := withID(body, s.newID())
+	s.queries[queryKey(scope, q["id"].(string))] = q
+	writeJSON(w, http.StatusOK, q)
+}
+
+func (s *Server) getQuery(w http.ResponseWriter, r *http.Request) {
+	scope, ok := s.scope(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("queryId")
+	q, ok := s.queries[queryKey(scope, id)]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("query %q not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, q)
+}
+
+// createQueryResult starts a query result, which is complete as soon as it is
+// first fetched.
+func (s *Server) createQueryResult(w http.ResponseWriter, r *http.Request) {
+	scope, ok := s.scope(w, r)
+	if !ok {
+		return
+	}
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	queryID, _ := body["query_id"].(string)
+	q, ok := s.queries[queryKey(scope, queryID)]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("query %q not found", queryID))
+		return
+	}
+
+	id := s.newID()
+	disableSeries, _ := body["disable_series"].(bool)
+	s.results[queryKey(scope, id)] = map[string]any{"query": q, "series": !disableSeries}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":       id,
+		"complete": false,
+		"query":    q,
+		"links":    s.resultLinks(scope, id),
+	})
+}
+
+func (s *Server) getQueryResult(w http.ResponseWriter, r *http.Request) {
+	scope, ok := s.scope(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("queryResultId")
+	result, ok := s.results[queryKey(scope, id)]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("query result %q not found", id))
+		return
+	}
+	q := result["query"].(map[string]any)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":       id,
+		"complete": true,
+		"query":    q,
+		"data":     s.cannedData(scope, q, result["series"].(bool)),
+		"links":    s.resultLinks(scope, id),
+	})
+}
+
+func (s *Server) resultLinks(dataset, id string) map[string]any {
+	url := fmt.Sprintf("https://ui.honeycomb.io/%s/environments/%s/datasets/%s/result/%s", s.team, slugify(s.environment), dataset, id)
+	return map[string]any{"query_url": url, "graph_image_url": url + "/snapshot"}
+}
+
+// cannedData returns the data of a query result: the rows set with
+// SetQueryData, or otherwise a few groups of each breakdown with values
+// derived from the dataset, group, and calculation, so the same query always
+// returns the same result.
+func (s *Server) cannedData(dataset string, q map[string]any, series bool) map[string]any {
+	rows := s.queryData[dataset]
+	if rows == nil {
+		rows = generateRows(dataset, q)
+	}
+
+	results := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		results[i] = map[string]any{"data": row}
+	}
+	data := map[string]any{"results": results}
+	if series {
+		data["series"] = generateSeries(q, rows)
+	}
+	return data
+}
+
+func generateRows(dataset string, q map[string]any) []map[string]any {
+	breakdowns := stringList(q["breakdowns"])
+	calcs := calculationNames(q)
+
+	groups := 1
+	if len(breakdowns) > 0 {
+		groups = cannedGroups
+	}
+	if limit, ok := number(q["limit"]); ok && limit > 0 && int(limit) < groups {
+		groups = int(limit)
+	}
+
+	rows := make([]map[string]any, groups)
+	for i := range rows {
+		row := map[string]any{}
+		for _, b := range breakdowns {
+			row[b] = fmt.Sprintf("%s-%d", b, i+1)
+		}
+		for _, c := range calcs {
+			row[c.name] = cannedValue(c, dataset, fmt.Sprint(i), c.name)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// generateSeries repeats each row at each step of the query's time range,
+// varying its calculations a little from step to step.
+func generateSeries(q map[string]any, rows []map[string]any) []map[string]any {
+	start, end := timeBounds(q)
+	step := int64(0)
+	if g, ok := number(q["granularity"]); ok && g > 0 {
+		step = int64(g)
+	}
+	if minStep := (end - start + maxSeriesPoints - 1) / maxSeriesPoints; step < minStep {
+		step = minStep
+	}
+	if step <= 0 {
+		step = 1
+	}
+	calcs := calculationNames(q)
+
+	var series []map[string]any
+	for t := start; t < end; t += step {
+		for _, row := range rows {
+			data := make(map[string]any, len(row))
+			for k, v := range row {
+				data[k] = v
+			}
+			for _, c := range calcs {
+				if v, ok := number(row[c.name]); ok {
+					data[c.name] = round(v * (0.75 + 0.5*unit(fmt.Sprint(t, row))))
+				}
+			}
+			series = append(series, map[string]any{
+				"time": time.Unix(t, 0).UTC().Format(time.RFC3339),
+				"data": data,
+			})
+		}
+	}
+	return series
+}
+
+// timeBounds returns the start and end of a query in Unix seconds.
+func timeBounds(q map[string]any) (int64, int64) {
+	timeRange := int64(defaultTimeRange)
+	if v, ok := number(q["time_range"]); ok && v > 0 {
+		timeRange = int64(v)
+	}
+	start, hasStart := number(q["start_time"])
+	end, hasEnd := number(q["end_time"])
+	switch {
+	case hasStart && hasEnd:
+		return int64(start), int64(end)
+	case hasStart:
+		return int64(start), int64(start) + timeRange
+	case hasEnd:
+		return int64(end) - timeRange, int64(end)
+	default:
+		now := time.Now().Unix()
+		return now - timeRange, now
+	}
+}
+
+type calculation struct {
+	name    string
+	integer bool
+}
+
+// calculationNames returns the result keys of a query's calculations, such
+// as "COUNT" and "P99(duration_ms)".
+func calculationNames(q map[string]any) []calculation {
+	list, _ := q["calculations"].([]any)
+	if len(list) == 0 {
+		return []calculation{{name: "COUNT", integer: true}}
+	}
+	calcs := make([]calculation, 0, len(list))
+	for _, v := range list {
+		c, _ := v.(map[string]any)
+		op, _ := c["op"].(string)
+		if op == "" {
+			op = "COUNT"
+		}
+		name, _ := c["name"].(string)
+		if name == "" {
+			name = op
+			if col, _ := c["column"].(string); col != "" {
+				name = fmt.Sprintf("%s(%s)", op, col)
+			}
+		}
+		calcs = append(calcs, calculation{
+			name:    name,
+			integer: op == "COUNT" || op == "COUNT_DISTINCT" || op == "SUM",
+		})
+	}
+	return calcs
+}
+
+func cannedValue(c calculation, parts ...string) float64 {
+	v := 1 + 999*unit(parts...)
+	if c.integer {
+		return math.Round(v * 10)
+	}
+	return round(v / 10)
+}
+
+// unit hashes parts to a number in [0, 1).
+func unit(parts ...string) float64 {
+	h := fnv.New64a()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		_, _ = h.Write([]byte{0})
+	}
+	return float64(h.Sum64()%1_000_000) / 1_000_000
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func stringList(v any) []string {
+	list, _ := v.([]any)
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,456 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// allDatasets is the dataset slug of environment-wide resources.
+const allDatasets = "__all__"
+
+// collection is the resources of one kind in one scope, such as the columns
+// of a dataset, in creation order.
+type collection struct {
+	idField string
+	items   []map[string]any
+}
+
+func (c *collection) add(obj map[string]any) {
+	c.items = append(c.items, obj)
+}
+
+func (c *collection) find(id string) (int, map[string]any) {
+	for i, obj := range c.items {
+		if v, _ := obj[c.idField].(string); v == id {
+			return i, obj
+		}
+	}
+	return -1, nil
+}
+
+func (c *collection) remove(i int) {
+	c.items = slices.Delete(c.items, i, i+1)
+}
+
+// list returns the items, as an empty list rather than null when there are
+// none.
+func (c *collection) list() []map[string]any {
+	if c.items == nil {
+		return []map[string]any{}
+	}
+	return c.items
+}
+
+// collection returns the collection of kind in scope, which is a dataset slug
+// or empty for resources outside datasets.
+func (s *Server) collection(kind, scope string) *collection {
+	key := kind + "/" + scope
+	c, ok := s.collections[key]
+	if !ok {
+		c = &collection{idField: "id"}
+		s.collections[key] = c
+	}
+	return c
+}
+
+// resource describes a Configuration API resource served with the usual
+// list, create, get, update, and delete routes.
+type resource struct {
+	kind    string
+	name    string
+	path    string
+	idParam string
+	// unique is a field whose value must be unique within the scope.
+	unique string
+	// deleteBody returns the deleted resource with a 200 instead of a 204.
+	deleteBody bool
+	// prepare fills in the server-side fields of a created or updated
+	// resource, which already has its ID.
+	prepare func(obj map[string]any, dataset string)
+	// parent is the path parameter naming the resource the collection
+	// belongs to, such as the board of a view, instead of a dataset.
+	parent, parentKind string
+	// filter reports whether a listed resource matches the request's query
+	// parameters.
+	filter func(obj map[string]any, r *http.Request) bool
+}
+
+func (s *Server) handleResource(res resource) {
+	item := res.path + "/{" + res.idParam + "}"
+
+	s.mux.HandleFunc("GET "+res.path, s.v1(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := s.resourceScope(w, r, res)
+		if !ok {
+			return
+		}
+		items := s.collection(res.kind, scope).list()
+		if res.filter != nil {
+			items = slices.DeleteFunc(slices.Clone(items), func(obj map[string]any) bool { return !res.filter(obj, r) })
+		}
+		writeJSON(w, http.StatusOK, items)
+	}))
+
+	s.mux.HandleFunc("POST "+res.path, s.v1(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := s.resourceScope(w, r, res)
+		if !ok {
+			return
+		}
+		obj, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		c := s.collection(res.kind, scope)
+		if res.unique != "" {
+			if v, _ := obj[res.unique].(string); v != "" && slices.ContainsFunc(c.items, func(o map[string]any) bool { return o[res.unique] == v }) {
+				writeError(w, http.StatusConflict, fmt.Sprintf("%s with %s %q already exists", res.name, res.unique, v))
+				return
+			}
+		}
+		obj["id"] = s.newID()
+		s.stamp(obj)
+		if res.prepare != nil {
+			res.prepare(obj, scope)
+		}
+		c.add(obj)
+		writeJSON(w, http.StatusCreated, obj)
+	}))
+
+	s.mux.HandleFunc("GET "+item, s.v1(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, obj, ok := s.findResource(w, r, res); ok {
+			writeJSON(w, http.StatusOK, obj)
+		}
+	}))
+
+	s.mux.HandleFunc("PUT "+item, s.v1(func(w http.ResponseWriter, r *http.Request) {
+		scope, i, existing, ok := s.findResource(w, r, res)
+		if !ok {
+			return
+		}
+		obj, err := readBody(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		obj["id"] = existing["id"]
+		obj["created_at"] = existing["created_at"]
+		obj["updated_at"] = s.now()
+		if res.prepare != nil {
+			res.prepare(obj, scope)
+		}
+		s.collection(res.kind, scope).items[i] = obj
+		writeJSON(w, http.StatusOK, obj)
+	}))
+
+	s.mux.HandleFunc("DELETE "+item, s.v1(func(w http.ResponseWriter, r *http.Request) {
+		scope, i, obj, ok := s.findResource(w, r, res)
+		if !ok {
+			return
+		}
+		s.collection(res.kind, scope).remove(i)
+		if res.deleteBody {
+			writeJSON(w, http.StatusOK, obj)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+// resourceScope returns the scope of a request for res: its parent
+// resource's ID, or otherwise its dataset. It writes a 404 when the scope
+// does not exist.
+func (s *Server) resourceScope(w http.ResponseWriter, r *http.Request, res resource) (string, bool) {
+	if res.parent == "" {
+		return s.scope(w, r)
+	}
+	id := r.PathValue(res.parent)
+	if _, obj := s.collection(res.parentKind, "").find(id); obj == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %q not found", strings.TrimSuffix(res.parentKind, "s"), id))
+		return "", false
+	}
+	return id, true
+}
+
+// scope returns the dataset of a request, writing a 404 when it does not
+// exist.
+func (s *Server) scope(w http.ResponseWriter, r *http.Request) (string, bool) {
+	ds := r.PathValue("datasetSlug")
+	if ds == "" || ds == allDatasets {
+		return ds, true
+	}
+	if _, obj := s.datasets.find(ds); obj == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("dataset %q not found", ds))
+		return "", false
+	}
+	return ds, true
+}
+
+// findResource returns the dataset and index of the resource a request
+// names, writing a 404 when it or its dataset does not exist.
+func (s *Server) findResource(w http.ResponseWriter, r *http.Request, res resource) (string, int, map[string]any, bool) {
+	scope, ok := s.resourceScope(w, r, res)
+	if !ok {
+		return "", 0, nil, false
+	}
+	id := r.PathValue(res.idParam)
+	i, obj := s.collection(res.kind, scope).find(id)
+	if obj == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %q not found", res.name, id))
+		return "", 0, nil, false
+	}
+	return scope, i, obj, true
+}
+
+func (s *Server) prepareBoard(obj map[string]any, _ string) {
+	setDefault(obj, "type", "flexible")
+	obj["links"] = map[string]any{
+		"board_url": fmt.Sprintf("https://ui.honeycomb.io/%s/environments/%s/board/%s", s.team, slugify(s.environment), obj["id"]),
+	}
+}
+
+// matchSLO filters burn alerts by the slo_id query parameter.
+func matchSLO(obj map[string]any, r *http.Request) bool {
+	id := r.URL.Query().Get("slo_id")
+	slo, _ := obj["slo"].(map[string]any)
+	return id == "" || slo["id"] == id
+}
+
+func prepareColumn(obj map[string]any, _ string) {
+	setDefault(obj, "type", "string")
+	setDefault(obj, "hidden", false)
+	setDefault(obj, "description", "")
+}
+
+// prepareTrigger saves a trigger's inline query, as Honeycomb does, so the
+// trigger refers to it by ID.
+func (s *Server) prepareTrigger(obj map[string]any, dataset string) {
+	setDefault(obj, "disabled", false)
+	setDefault(obj, "triggered", false)
+	if q, ok := obj["query"].(map[string]any); ok {
+		id := s.newID()
+		s.queries[queryKey(dataset, id)] = withID(q, id)
+		obj["query_id"] = id
+	}
+}
+
+func prepareSLO(obj map[string]any, dataset string) {
+	if dataset != "" && dataset != allDatasets {
+		setDefault(obj, "dataset_slugs", []string{dataset})
+	}
+}
+
+func prepareMarker(obj map[string]any, _ string) {
+	setDefault(obj, "start_time", time.Now().Unix())
+}
+
+func prepareAnnotation(obj map[string]any, _ string) {
+	setDefault(obj, "source", "query")
+}
+
+func (s *Server) listRecipientTriggers(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("recipientId")
+	if _, obj := s.collection("recipients", "").find(id); obj == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("recipient %q not found", id))
+		return
+	}
+
+	triggers := []map[string]any{}
+	for key, c := range s.collections {
+		if !strings.HasPrefix(key, "triggers/") {
+			continue
+		}
+		for _, t := range c.items {
+			recipients, _ := t["recipients"].([]any)
+			if slices.ContainsFunc(recipients, func(v any) bool {
+				rcpt, _ := v.(map[string]any)
+				return rcpt["id"] == id
+			}) {
+				triggers = append(triggers, t)
+			}
+		}
+	}
+	slices.SortFunc(triggers, func(a, b map[string]any) int {
+		return strings.Compare(fmt.Sprint(a["id"]), fmt.Sprint(b["id"]))
+	})
+	writeJSON(w, http.StatusOK, triggers)
+}
+
+// newDataset returns a delete protected dataset, as Honeycomb creates.
+func (s *Server) newDataset(body map[string]any) map[string]any {
+	name, _ := body["name"].(string)
+	ds := map[string]any{
+		"name":                  name,
+		"slug":                  slugify(name),
+		"description":           "",
+		"expand_json_depth":     0,
+		"settings":              map[string]any{"delete_protected": true},
+		"regular_columns_count": 0,
+		"last_written_at":       nil,
+		"created_at":            s.now(),
+	}
+	for _, field := range []string{"description", "expand_json_depth"} {
+		if v, ok := body[field]; ok {
+			ds[field] = v
+		}
+	}
+	return ds
+}
+
+// dataset returns a dataset with its column count up to date.
+func (s *Server) dataset(ds map[string]any) map[string]any {
+	ds["regular_columns_count"] = len(s.collection("columns", ds["slug"].(string)).items)
+	return ds
+}
+
+func (s *Server) listDatasets(w http.ResponseWriter, _ *http.Request) {
+	for _, ds := range s.datasets.items {
+		s.dataset(ds)
+	}
+	writeJSON(w, http.StatusOK, s.datasets.list())
+}
+
+// createDataset returns an existing dataset of the same slug with a 200, as
+// Honeycomb does, rather than a conflict.
+func (s *Server) createDataset(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if name, _ := body["name"].(string); slugify(name) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "dataset name is required")
+		return
+	}
+
+	ds := s.newDataset(body)
+	if _, existing := s.datasets.find(ds["slug"].(string)); existing != nil {
+		writeJSON(w, http.StatusOK, s.dataset(existing))
+		return
+	}
+	s.datasets.add(ds)
+	writeJSON(w, http.StatusCreated, ds)
+}
+
+func (s *Server) getDataset(w http.ResponseWriter, r *http.Request) {
+	if _, ds := s.findDataset(w, r); ds != nil {
+		writeJSON(w, http.StatusOK, s.dataset(ds))
+	}
+}
+
+func (s *Server) updateDataset(w http.ResponseWriter, r *http.Request) {
+	_, ds := s.findDataset(w, r)
+	if ds == nil {
+		return
+	}
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, field := range []string{"name", "description", "expand_json_depth"} {
+		if v, ok := body[field]; ok {
+			ds[field] = v
+		}
+	}
+	if settings, ok := body["settings"].(map[string]any); ok {
+		merge(ds["settings"].(map[string]any), settings)
+	}
+	writeJSON(w, http.StatusOK, s.dataset(ds))
+}
+
+// deleteDataset removes a dataset and everything in it, unless it is delete
+// protected.
+func (s *Server) deleteDataset(w http.ResponseWriter, r *http.Request) {
+	i, ds := s.findDataset(w, r)
+	if ds == nil {
+		return
+	}
+	if settings, _ := ds["settings"].(map[string]any); settings["delete_protected"] == true {
+		writeError(w, http.StatusConflict, "dataset is delete protected")
+		return
+	}
+
+	slug := ds["slug"].(string)
+	s.datasets.remove(i)
+	for key := range s.collections {
+		if strings.HasSuffix(key, "/"+slug) {
+			delete(s.collections, key)
+		}
+	}
+	delete(s.definitions, slug)
+	writeJSON(w, http.StatusAccepted, map[string]any{})
+}
+
+func (s *Server) findDataset(w http.ResponseWriter, r *http.Request) (int, map[string]any) {
+	slug := r.PathValue("datasetSlug")
+	i, ds := s.datasets.find(slug)
+	if ds == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("dataset %q not found", slug))
+	}
+	return i, ds
+}
+
+func (s *Server) getDefinitions(w http.ResponseWriter, r *http.Request) {
+	if _, ds := s.findDataset(w, r); ds == nil {
+		return
+	}
+	defs := s.definitions[r.PathValue("datasetSlug")]
+	if defs == nil {
+		defs = map[string]any{}
+	}
+	writeJSON(w, http.StatusOK, defs)
+}
+
+func (s *Server) updateDefinitions(w http.ResponseWriter, r *http.Request) {
+	if _, ds := s.findDataset(w, r); ds == nil {
+		return
+	}
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	slug := r.PathValue("datasetSlug")
+	defs := s.definitions[slug]
+	if defs == nil {
+		defs = map[string]any{}
+		s.definitions[slug] = defs
+	}
+	merge(defs, body)
+	writeJSON(w, http.StatusOK, defs)
+}
+
+// setDefault sets a field the request left out.
+func setDefault(obj map[string]any, field string, value any) {
+	if _, ok := obj[field]; !ok {
+		obj[field] = value
+	}
+}
+
+// withID returns a copy of obj with its ID set.
+func withID(obj map[string]any, id string) map[string]any {
+	out := make(map[string]any, len(obj)+1)
+	merge(out, obj)
+	out["id"] = id
+	return out
+}
+
+// number returns the value of a decoded JSON number.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
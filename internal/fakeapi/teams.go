@@ -0,0 +1,281 @@
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func (s *Server) newEnvironment(name, description, color string) map[string]any {
+	id := s.newPrefixedID("hcaen_")
+	return map[string]any{
+		"id":   id,
+		"type": "environments",
+		"attributes": map[string]any{
+			"name":        name,
+			"slug":        slugify(name),
+			"description": description,
+			"color":       color,
+			"settings":    map[string]any{"delete_protected": true},
+		},
+		"links": map[string]any{"self": "/2/teams/" + s.team + "/environments/" + id},
+	}
+}
+
+func (s *Server) listEnvironments(w http.ResponseWriter, r *http.Request) {
+	s.writePage(w, r, s.environments.list())
+}
+
+// createEnvironment creates a delete protected environment, as Honeycomb
+// does.
+func (s *Server) createEnvironment(w http.ResponseWriter, r *http.Request) {
+	attrs, ok := readAttributes(w, r)
+	if !ok {
+		return
+	}
+	name, _ := attrs["name"].(string)
+	if slugify(name) == "" {
+		writeErrorV2(w, http.StatusUnprocessableEntity, "environment name is required")
+		return
+	}
+	if slices.ContainsFunc(s.environments.items, func(e map[string]any) bool {
+		return e["attributes"].(map[string]any)["slug"] == slugify(name)
+	}) {
+		writeErrorV2(w, http.StatusConflict, fmt.Sprintf("environment %q already exists", name))
+		return
+	}
+
+	description, _ := attrs["description"].(string)
+	color, _ := attrs["color"].(string)
+	if color == "" {
+		color = "blue"
+	}
+	env := s.newEnvironment(name, description, color)
+	s.environments.add(env)
+	writeJSONAPI(w, http.StatusCreated, map[string]any{"data": env})
+}
+
+func (s *Server) getEnvironment(w http.ResponseWriter, r *http.Request) {
+	if _, env := s.findV2(w, r, s.environments, "environment"); env != nil {
+		writeJSONAPI(w, http.StatusOK, map[string]any{"data": env})
+	}
+}
+
+func (s *Server) updateEnvironment(w http.ResponseWriter, r *http.Request) {
+	_, env := s.findV2(w, r, s.environments, "environment")
+	if env == nil {
+		return
+	}
+	attrs, ok := readAttributes(w, r)
+	if !ok {
+		return
+	}
+	current := env["attributes"].(map[string]any)
+	for _, field := range []string{"description", "color"} {
+		if v, ok := attrs[field]; ok {
+			current[field] = v
+		}
+	}
+	if settings, ok := attrs["settings"].(map[string]any); ok {
+		merge(current["settings"].(map[string]any), settings)
+	}
+	writeJSONAPI(w, http.StatusOK, map[string]any{"data": env})
+}
+
+func (s *Server) deleteEnvironment(w http.ResponseWriter, r *http.Request) {
+	i, env := s.findV2(w, r, s.environments, "environment")
+	if env == nil {
+		return
+	}
+	settings := env["attributes"].(map[string]any)["settings"].(map[string]any)
+	if settings["delete_protected"] == true {
+		writeErrorV2(w, http.StatusConflict, "environment is delete protected")
+		return
+	}
+	s.environments.remove(i)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listKeys(w http.ResponseWriter, r *http.Request) {
+	keys := s.keys.list()
+	if kt := r.URL.Query().Get("filter[type]"); kt != "" {
+		keys = slices.DeleteFunc(slices.Clone(keys), func(k map[string]any) bool {
+			return k["attributes"].(map[string]any)["key_type"] != kt
+		})
+	}
+	s.writePage(w, r, keys)
+}
+
+// createKey creates an ingest or configuration key in an environment. The
+// response carries the key's secret, which is not returned again.
+func (s *Server) createKey(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		writeErrorV2(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	data, _ := body["data"].(map[string]any)
+	attrs, _ := data["attributes"].(map[string]any)
+	rel, _ := data["relationships"].(map[string]any)
+	envRel, _ := rel["environment"].(map[string]any)
+	envData, _ := envRel["data"].(map[string]any)
+	envID, _ := envData["id"].(string)
+
+	var prefix string
+	switch attrs["key_type"] {
+	case "ingest":
+		prefix = "hcxik_"
+	case "configuration":
+		prefix = "hcxlk_"
+	default:
+		writeErrorV2(w, http.StatusUnprocessableEntity, "key_type must be ingest or configuration")
+		return
+	}
+	if _, env := s.environments.find(envID); env == nil {
+		writeErrorV2(w, http.StatusNotFound, fmt.Sprintf("environment %q not found", envID))
+		return
+	}
+
+	id := s.newPrefixedID(prefix)
+	now := s.now()
+	stored := map[string]any{
+		"key_type":   attrs["key_type"],
+		"name":       attrs["name"],
+		"disabled":   attrs["disabled"] == true,
+		"timestamps": map[string]any{"created": now, "updated": now},
+	}
+	if perms, ok := attrs["permissions"].(map[string]any); ok {
+		stored["permissions"] = perms
+	}
+	key := map[string]any{
+		"id":         id,
+		"type":       "api-keys",
+		"attributes": stored,
+		"relationships": map[string]any{
+			"environment": map[string]any{"data": map[string]any{"id": envID, "type": "environments"}},
+		},
+		"links": map[string]any{"self": "/2/teams/" + s.team + "/api-keys/" + id},
+	}
+	s.keys.add(key)
+
+	withSecret := map[string]any{"secret": fmt.Sprintf("fakesecret%022d", s.seq)}
+	merge(withSecret, stored)
+	created := withID(key, id)
+	created["attributes"] = withSecret
+	writeJSONAPI(w, http.StatusCreated, map[string]any{"data": created})
+}
+
+func (s *Server) getKey(w http.ResponseWriter, r *http.Request) {
+	if _, key := s.findV2(w, r, s.keys, "API key"); key != nil {
+		writeJSONAPI(w, http.StatusOK, map[string]any{"data": key})
+	}
+}
+
+func (s *Server) updateKey(w http.ResponseWriter, r *http.Request) {
+	_, key := s.findV2(w, r, s.keys, "API key")
+	if key == nil {
+		return
+	}
+	attrs, ok := readAttributes(w, r)
+	if !ok {
+		return
+	}
+	current := key["attributes"].(map[string]any)
+	for _, field := range []string{"name", "disabled"} {
+		if v, ok := attrs[field]; ok {
+			current[field] = v
+		}
+	}
+	if perms, ok := attrs["permissions"].(map[string]any); ok {
+		existing, _ := current["permissions"].(map[string]any)
+		if existing == nil {
+			existing = map[string]any{}
+			current["permissions"] = existing
+		}
+		merge(existing, perms)
+	}
+	current["timestamps"].(map[string]any)["updated"] = s.now()
+	writeJSONAPI(w, http.StatusOK, map[string]any{"data": key})
+}
+
+func (s *Server) deleteKey(w http.ResponseWriter, r *http.Request) {
+	i, key := s.findV2(w, r, s.keys, "API key")
+	if key == nil {
+		return
+	}
+	s.keys.remove(i)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) findV2(w http.ResponseWriter, r *http.Request, c *collection, name string) (int, map[string]any) {
+	id := r.PathValue("ID")
+	i, obj := c.find(id)
+	if obj == nil {
+		writeErrorV2(w, http.StatusNotFound, fmt.Sprintf("%s %q not found", name, id))
+	}
+	return i, obj
+}
+
+// writePage writes the page of items a request asks for with page[after] and
+// page[size], linking to the next page when there is one.
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, items []map[string]any) {
+	query := r.URL.Query()
+
+	size := defaultPageSize
+	if v := query.Get("page[size]"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			writeErrorV2(w, http.StatusBadRequest, fmt.Sprintf("page[size] must be between 1 and %d", maxPageSize))
+			return
+		}
+		size = n
+	}
+
+	start := 0
+	if after := query.Get("page[after]"); after != "" {
+		i := slices.IndexFunc(items, func(item map[string]any) bool { return item["id"] == after })
+		if i < 0 {
+			writeErrorV2(w, http.StatusBadRequest, fmt.Sprintf("invalid page[after] cursor %q", after))
+			return
+		}
+		start = i + 1
+	}
+
+	end := min(start+size, len(items))
+	var next any
+	if end < len(items) {
+		next = fmt.Sprintf("%s?page[after]=%s&page[size]=%d", r.URL.Path, items[end-1]["id"], size)
+	}
+	writeJSONAPI(w, http.StatusOK, map[string]any{
+		"data":  items[start:end],
+		"links": map[string]any{"next": next},
+	})
+}
+
+// readAttributes returns the data.attributes of a JSON:API request body.
+func readAttributes(w http.ResponseWriter, r *http.Request) (map[string]any, bool) {
+	body, err := readBody(r)
+	if err != nil {
+		writeErrorV2(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	data, _ := body["data"].(map[string]any)
+	attrs, _ := data["attributes"].(map[string]any)
+	if attrs == nil {
+		writeErrorV2(w, http.StatusBadRequest, "request body must have data.attributes")
+		return nil, false
+	}
+	return attrs, true
+}
+
+func merge(dst, src map[string]any) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
@@ -0,0 +1,257 @@
+// Package jsondiff compares decoded JSON values, as produced by
+// json.Unmarshal into an any, and renders the differences as a unified line
+// diff or as a list of changed paths.
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Project returns the parts of got that want sets: objects keep only want's
+// keys, and arrays of the same length are projected element-wise. Projecting
+// a live resource onto a local definition ignores fields the definition
+// leaves to the server, such as defaults.
+func Project(want, got any) any {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return got
+		}
+		out := make(map[string]any, len(w))
+		for k, v := range w {
+			if gv, ok := g[k]; ok {
+				out[k] = Project(v, gv)
+			}
+		}
+		return out
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return got
+		}
+		out := make([]any, len(g))
+		for i := range g {
+			out[i] = Project(w[i], g[i])
+		}
+		return out
+	default:
+		return got
+	}
+}
+
+// Equal reports whether two decoded JSON values are the same.
+func Equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+const contextLines = 3
+
+// Unified returns a unified diff of the indented JSON forms of from and to,
+// or "" when they are equal. Object keys are sorted, so only values and
+// structure produce differences.
+func Unified(from, to any, fromLabel, toLabel string) (string, error) {
+	a, err := lines(from)
+	if err != nil {
+		return "", err
+	}
+	b, err := lines(to)
+	if err != nil {
+		return "", err
+	}
+
+	ops := diffLines(a, b)
+	if !hasChanges(ops) {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	for _, h := range hunks(ops) {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.fromStart, h.fromCount), hunkRange(h.toStart, h.toCount))
+		for _, op := range h.ops {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String(), nil
+}
+
+func lines(v any) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(b), "\n"), nil
+}
+
+type lineOp struct {
+	kind byte // ' ', '-', or '+'
+	line string
+}
+
+// diffLines computes an edit script from a to b from their longest common
+// subsequence.
+func diffLines(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]lineOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, lineOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, lineOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, lineOp{'+', b[j]})
+	}
+	return ops
+}
+
+func hasChanges(ops []lineOp) bool {
+	for _, op := range ops {
+		if op.kind != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+type hunk struct {
+	fromStart, fromCount int
+	toStart, toCount     int
+	ops                  []lineOp
+}
+
+// hunks groups changed lines with up to contextLines of surrounding context,
+// merging groups whose context overlaps.
+func hunks(ops []lineOp) []hunk {
+	// fromLine[i] and toLine[i] are the 1-based line numbers of ops[i] in
+	// each side.
+	fromLine := make([]int, len(ops)+1)
+	toLine := make([]int, len(ops)+1)
+	fromLine[0], toLine[0] = 1, 1
+	for i, op := range ops {
+		fromLine[i+1], toLine[i+1] = fromLine[i], toLine[i]
+		if op.kind != '+' {
+			fromLine[i+1]++
+		}
+		if op.kind != '-' {
+			toLine[i+1]++
+		}
+	}
+
+	var result []hunk
+	start, end := -1, -1
+	flush := func() {
+		h := hunk{fromStart: fromLine[start], toStart: toLine[start], ops: ops[start:end]}
+		for _, op := range h.ops {
+			if op.kind != '+' {
+				h.fromCount++
+			}
+			if op.kind != '-' {
+				h.toCount++
+			}
+		}
+		result = append(result, h)
+	}
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		lo, hi := max(0, i-contextLines), min(len(ops), i+contextLines+1)
+		if start >= 0 && lo > end {
+			flush()
+			start = -1
+		}
+		if start < 0 {
+			start = lo
+		}
+		end = hi
+	}
+	if start >= 0 {
+		flush()
+	}
+	return result
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return strconv.Itoa(start) + "," + strconv.Itoa(count)
+}
+
+var (
+	removedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	addedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	hunkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	headerStyle  = lipgloss.NewStyle().Bold(true)
+)
+
+// Colorize styles the lines of a unified or structural diff: removals red,
+// additions green, and hunk headers cyan.
+func Colorize(diff string) string {
+	lines := strings.SplitAfter(diff, "\n")
+	for i, line := range lines {
+		text := strings.TrimSuffix(line, "\n")
+		if text == "" {
+			continue
+		}
+		var style *lipgloss.Style
+		switch {
+		case strings.HasPrefix(text, "--- "), strings.HasPrefix(text, "+++ "):
+			style = &headerStyle
+		case strings.HasPrefix(text, "@@"):
+			style = &hunkStyle
+		case text[0] == '-':
+			style = &removedStyle
+		case text[0] == '+':
+			style = &addedStyle
+		case text[0] == '~':
+			style = &hunkStyle
+		default:
+			continue
+		}
+		lines[i] = style.Render(text) + line[len(text):]
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,111 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestProject(t *testing.T) {
+	want := decode(t, `{"name": "x", "threshold": {"value": 1}, "tags": [{"key": "a"}]}`)
+	got := decode(t, `{"name": "y", "disabled": false, "threshold": {"op": ">", "value": 2}, "tags": [{"key": "a", "value": "b"}]}`)
+
+	projected := Project(want, got)
+	expected := decode(t, `{"name": "y", "threshold": {"value": 2}, "tags": [{"key": "a"}]}`)
+	if !Equal(projected, expected) {
+		t.Errorf("Project() = %v, want %v", projected, expected)
+	}
+
+	// Arrays of different lengths are kept whole.
+	projected = Project(decode(t, `{"tags": [{"key": "a"}]}`), decode(t, `{"tags": [{"key": "a", "value": "b"}, {"key": "c"}]}`))
+	if tags := projected.(map[string]any)["tags"].([]any); len(tags) != 2 || len(tags[0].(map[string]any)) != 2 {
+		t.Errorf("Project() tags = %v, want live tags unchanged", tags)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	from := decode(t, `{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10}`)
+	to := decode(t, `{"a": 1, "b": 20, "c": 3, "d": 4, "e": 5, "f": 6, "g": 7, "h": 8, "i": 9, "j": 10, "k": 11}`)
+
+	got, err := Unified(from, to, "live", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- live
++++ local
+@@ -1,6 +1,6 @@
+ {
+   "a": 1,
+-  "b": 2,
++  "b": 20,
+   "c": 3,
+   "d": 4,
+   "e": 5,
+@@ -8,5 +8,6 @@
+   "g": 7,
+   "h": 8,
+   "i": 9,
+-  "j": 10
++  "j": 10,
++  "k": 11
+ }
+`
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_Equal(t *testing.T) {
+	v := decode(t, `{"name": "x"}`)
+	got, err := Unified(v, decode(t, `{"name": "x"}`), "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("Unified() = %q, want empty", got)
+	}
+}
+
+func TestUnified_Create(t *testing.T) {
+	got, err := Unified(nil, decode(t, `{"name": "x"}`), "/dev/null", "local")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "@@ -0,0 +1,3 @@\n+{\n+  \"name\": \"x\"\n+}\n") {
+		t.Errorf("Unified() =\n%s", got)
+	}
+}
+
+func TestStructural(t *testing.T) {
+	from := decode(t, `{"name": "x", "threshold": {"op": ">", "value": 1}, "tags": [{"key": "a"}], "old": true}`)
+	to := decode(t, `{"name": "x", "threshold": {"op": ">", "value": 2}, "tags": [{"key": "b"}], "new": [1]}`)
+
+	var lines []string
+	for _, c := range Structural(from, to) {
+		lines = append(lines, c.String())
+	}
+	want := `+ new: [1]
+- old: true
+~ tags[0].key: "a" -> "b"
+~ threshold.value: 1 -> 2`
+	if got := strings.Join(lines, "\n"); got != want {
+		t.Errorf("Structural() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestColorize_Plain(t *testing.T) {
+	// Without a terminal, lipgloss renders no escape codes.
+	diff := "--- a\n+++ b\n@@ -1 +1 @@\n-x\n+y\n"
+	if got := Colorize(diff); got != diff {
+		t.Errorf("Colorize() = %q, want %q", got, diff)
+	}
+}
@@ -0,0 +1,121 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	OpAdded   = "added"
+	OpRemoved = "removed"
+	OpChanged = "changed"
+)
+
+// Change is one difference between two JSON values, located by a path such
+// as threshold.value or tags[0].key.
+type Change struct {
+	Path string `json:"path"`
+	Op   string `json:"op"`
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
+// String renders the change as one line prefixed with -, +, or ~.
+func (c Change) String() string {
+	switch c.Op {
+	case OpAdded:
+		return fmt.Sprintf("+ %s: %s", c.Path, compact(c.To))
+	case OpRemoved:
+		return fmt.Sprintf("- %s: %s", c.Path, compact(c.From))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Path, compact(c.From), compact(c.To))
+	}
+}
+
+// Structural returns the paths at which from and to differ, in sorted
+// order. Objects are compared key by key and arrays of the same length
+// element by element; arrays of different lengths change as a whole.
+//
+// A nil side stands for a resource that does not exist, so every field of
+// the other side is reported as added or removed.
+func Structural(from, to any) []Change {
+	if from == nil {
+		if _, ok := to.(map[string]any); ok {
+			from = map[string]any{}
+		}
+	}
+	if to == nil {
+		if _, ok := from.(map[string]any); ok {
+			to = map[string]any{}
+		}
+	}
+
+	var changes []Change
+	walk("", from, to, &changes)
+	return changes
+}
+
+func walk(path string, from, to any, changes *[]Change) {
+	switch f := from.(type) {
+	case map[string]any:
+		t, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(f)+len(t))
+		for k := range f {
+			keys = append(keys, k)
+		}
+		for k := range t {
+			if _, ok := f[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			fv, inFrom := f[k]
+			tv, inTo := t[k]
+			switch {
+			case !inTo:
+				*changes = append(*changes, Change{Path: p, Op: OpRemoved, From: fv})
+			case !inFrom:
+				*changes = append(*changes, Change{Path: p, Op: OpAdded, To: tv})
+			default:
+				walk(p, fv, tv, changes)
+			}
+		}
+		return
+	case []any:
+		t, ok := to.([]any)
+		if !ok || len(t) != len(f) {
+			break
+		}
+		for i := range f {
+			walk(path+"["+strconv.Itoa(i)+"]", f[i], t[i], changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		if path == "" {
+			path = "."
+		}
+		*changes = append(*changes, Change{Path: path, Op: OpChanged, From: from, To: to})
+	}
+}
+
+func compact(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(b))
+}
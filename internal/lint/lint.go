@@ -0,0 +1,551 @@
+// Package lint checks resource manifests against the datasets they refer to
+// before they are applied: that the columns their queries, calculated field
+// expressions, and SLIs use exist, that query ops suit the column types, that
+// trigger schedules and thresholds are allowed, and that recipients exist.
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Rule identifies a check.
+type Rule struct {
+	ID          string
+	Description string
+}
+
+var (
+	RuleUnknownDataset   = Rule{"unknown-dataset", "The dataset does not exist."}
+	RuleUnknownColumn    = Rule{"unknown-column", "The column is not a column or calculated field of the dataset."}
+	RuleInvalidOp        = Rule{"invalid-op", "The op is not a known calculation, filter, or having op."}
+	RuleOpColumnType     = Rule{"op-column-type", "The op does not apply to the column's type."}
+	RuleTriggerFrequency = Rule{"trigger-frequency", "The trigger frequency or query time range is out of bounds."}
+	RuleTriggerThreshold = Rule{"trigger-threshold", "The trigger threshold is invalid."}
+	RuleTriggerQuery     = Rule{"trigger-query", "The trigger query cannot be used in a trigger."}
+	RuleUnknownRecipient = Rule{"unknown-recipient", "The recipient does not exist."}
+	RuleUnknownSLI       = Rule{"unknown-sli", "The SLI is not a calculated field of the dataset."}
+	RuleUnknownQuery     = Rule{"unknown-query", "The board panel's query does not exist."}
+)
+
+// Rules lists every rule, for reports that describe them.
+var Rules = []Rule{
+	RuleUnknownDataset,
+	RuleUnknownColumn,
+	RuleInvalidOp,
+	RuleOpColumnType,
+	RuleTriggerFrequency,
+	RuleTriggerThreshold,
+	RuleTriggerQuery,
+	RuleUnknownRecipient,
+	RuleUnknownSLI,
+	RuleUnknownQuery,
+}
+
+// Diagnostic is a problem found at a path within a manifest, such as
+// spec.query.calculations[0].column.
+type Diagnostic struct {
+	Source   string `json:"source" col:"Source"`
+	Path     string `json:"path" col:"Path"`
+	Severity string `json:"severity" col:"Severity"`
+	Rule     string `json:"rule" col:"Rule"`
+	Message  string `json:"message" col:"Message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s (%s)", d.Source, d.Path, d.Severity, d.Message, d.Rule)
+}
+
+// Errors counts the diagnostics with error severity.
+func Errors(diags []Diagnostic) int {
+	n := 0
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+type schema struct {
+	// columns maps column names to their types. Calculated fields have an
+	// empty type.
+	columns map[string]string
+	fields  map[string]bool
+}
+
+type linter struct {
+	ctx       context.Context
+	client    api.ClientInterface
+	manifests []manifest.Manifest
+
+	schemas    map[string]*schema
+	datasets   map[string]string
+	recipients []manifest.Object
+	diags      []Diagnostic
+}
+
+// Lint checks manifests against the API and returns their diagnostics in
+// manifest order. Resources defined by the manifests themselves, such as a
+// calculated field an SLO uses, count as existing. Errors reading from the API
+// other than a missing dataset or query are returned.
+func Lint(ctx context.Context, c api.ClientInterface, manifests []manifest.Manifest) ([]Diagnostic, error) {
+	l := &linter{ctx: ctx, client: c, manifests: manifests, schemas: map[string]*schema{}}
+	for _, m := range manifests {
+		r := &reporter{l: l, source: m.Source}
+		var err error
+		switch m.Kind {
+		case manifest.KindTrigger:
+			err = l.lintTrigger(r, m)
+		case manifest.KindSLO:
+			err = l.lintSLO(r, m)
+		case manifest.KindBurnAlert:
+			err = l.lintRecipients(r, "spec.recipients", m.Spec["recipients"])
+		case manifest.KindCalculatedField:
+			err = l.lintCalculatedField(r, m)
+		case manifest.KindBoard:
+			err = l.lintBoard(r, m)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Source, err)
+		}
+	}
+	return l.diags, nil
+}
+
+type reporter struct {
+	l      *linter
+	source string
+}
+
+func (r *reporter) report(severity string, rule Rule, path, format string, args ...any) {
+	r.l.diags = append(r.l.diags, Diagnostic{
+		Source:   r.source,
+		Path:     path,
+		Severity: severity,
+		Rule:     rule.ID,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *reporter) errorf(rule Rule, path, format string, args ...any) {
+	r.report(SeverityError, rule, path, format, args...)
+}
+
+func (r *reporter) warnf(rule Rule, path, format string, args ...any) {
+	r.report(SeverityWarning, rule, path, format, args...)
+}
+
+// schema loads a dataset's columns and calculated fields, with the calculated
+// fields the manifests define. It returns nil for environment-wide scopes and
+// datasets that do not exist.
+func (l *linter) schema(dataset string) (*schema, error) {
+	if dataset == manifestAllDatasets {
+		return nil, nil
+	}
+	if s, ok := l.schemas[dataset]; ok {
+		return s, nil
+	}
+
+	columns, err := api.DecodeResponse[[]manifest.Object](l.client.ListColumns(l.ctx, dataset, nil))
+	if isNotFound(err) {
+		l.schemas[dataset] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing columns of %s: %w", dataset, err)
+	}
+	fields, err := api.DecodeResponse[[]manifest.Object](l.client.ListCalculatedFields(l.ctx, dataset, nil))
+	if err != nil {
+		return nil, fmt.Errorf("listing calculated fields of %s: %w", dataset, err)
+	}
+
+	s := &schema{columns: map[string]string{}, fields: map[string]bool{}}
+	for _, c := range columns {
+		name, _ := c["key_name"].(string)
+		typ, _ := c["type"].(string)
+		s.columns[name] = typ
+	}
+	for _, f := range fields {
+		alias, _ := f["alias"].(string)
+		s.columns[alias] = ""
+		s.fields[alias] = true
+	}
+	for _, m := range l.manifests {
+		if m.Kind == manifest.KindCalculatedField && m.Dataset == dataset {
+			alias := m.Key()
+			s.columns[alias] = ""
+			s.fields[alias] = true
+		}
+	}
+	l.schemas[dataset] = s
+	return s, nil
+}
+
+// manifestAllDatasets is the dataset of environment-wide triggers and
+// queries, which have no schema to check against.
+const manifestAllDatasets = "__all__"
+
+// lintDataset reports a dataset that does not exist and returns its schema.
+func (l *linter) lintDataset(r *reporter, dataset string) (*schema, error) {
+	s, err := l.schema(dataset)
+	if err != nil || s != nil || dataset == manifestAllDatasets {
+		return s, err
+	}
+	r.errorf(RuleUnknownDataset, "dataset", "dataset %s not found", dataset)
+	return nil, nil
+}
+
+func (l *linter) lintTrigger(r *reporter, m manifest.Manifest) error {
+	s, err := l.lintDataset(r, m.Dataset)
+	if err != nil {
+		return err
+	}
+
+	frequency, hasFrequency := number(m.Spec["frequency"])
+	if hasFrequency {
+		if frequency < 60 || frequency > 86400 || int(frequency)%60 != 0 {
+			r.errorf(RuleTriggerFrequency, "spec.frequency", "frequency %v must be a multiple of 60 between 60 and 86400 seconds", frequency)
+		}
+	} else {
+		frequency = 900
+	}
+
+	if query, ok := m.Spec["query"].(map[string]any); ok {
+		if s != nil {
+			lintQuery(r, "spec.query", query, s)
+		}
+		if timeRange, ok := number(query["time_range"]); ok && timeRange > frequency*4 {
+			r.errorf(RuleTriggerFrequency, "spec.query.time_range", "query time range %vs exceeds 4 times the frequency (%vs)", timeRange, frequency*4)
+		}
+		calcs, _ := query["calculations"].([]any)
+		if len(calcs) != 1 {
+			r.errorf(RuleTriggerQuery, "spec.query.calculations", "trigger queries must have exactly one calculation, found %d", len(calcs))
+		}
+		for i, c := range calcs {
+			calc, _ := c.(map[string]any)
+			if calc["op"] == string(api.QueryOpHEATMAP) {
+				r.errorf(RuleTriggerQuery, fmt.Sprintf("spec.query.calculations[%d].op", i), "HEATMAP cannot be used in a trigger")
+			}
+		}
+	}
+
+	if threshold, ok := m.Spec["threshold"].(map[string]any); ok {
+		if op, _ := threshold["op"].(string); !api.BaseTriggerThresholdOp(op).Valid() {
+			r.errorf(RuleTriggerThreshold, "spec.threshold.op", "threshold op %q must be one of >, >=, <, <=", op)
+		}
+		if _, ok := number(threshold["value"]); !ok {
+			r.errorf(RuleTriggerThreshold, "spec.threshold.value", "threshold value must be a number")
+		}
+		if limit, ok := number(threshold["exceeded_limit"]); ok && (limit < 1 || limit > 5) {
+			r.errorf(RuleTriggerThreshold, "spec.threshold.exceeded_limit", "exceeded_limit %v must be between 1 and 5", limit)
+		}
+	} else if m.Spec["baseline_details"] == nil {
+		r.errorf(RuleTriggerThreshold, "spec.threshold", "a threshold is required")
+	}
+
+	return l.lintRecipients(r, "spec.recipients", m.Spec["recipients"])
+}
+
+func (l *linter) lintSLO(r *reporter, m manifest.Manifest) error {
+	s, err := l.lintDataset(r, m.Dataset)
+	if err != nil || s == nil {
+		return err
+	}
+	sli, _ := m.Spec["sli"].(map[string]any)
+	alias, _ := sli["alias"].(string)
+	if !s.fields[alias] {
+		r.errorf(RuleUnknownSLI, "spec.sli.alias", "calculated field %q not found in dataset %s", alias, m.Dataset)
+	}
+	return nil
+}
+
+func (l *linter) lintCalculatedField(r *reporter, m manifest.Manifest) error {
+	s, err := l.lintDataset(r, m.Dataset)
+	if err != nil || s == nil {
+		return err
+	}
+	expr, _ := m.Spec["expression"].(string)
+	for _, col := range ExpressionColumns(expr) {
+		if _, ok := s.columns[col]; !ok {
+			r.errorf(RuleUnknownColumn, "spec.expression", "column %q not found in dataset %s", col, m.Dataset)
+		}
+	}
+	return nil
+}
+
+// lintBoard checks the queries of panels that name their dataset. Panels
+// without one cannot be looked up, since queries are scoped to a dataset.
+func (l *linter) lintBoard(r *reporter, m manifest.Manifest) error {
+	panels, _ := m.Spec["panels"].([]any)
+	for i, p := range panels {
+		panel, _ := p.(map[string]any)
+		q, _ := panel["query_panel"].(map[string]any)
+		name, _ := q["dataset"].(string)
+		id, _ := q["query_id"].(string)
+		if name == "" || id == "" {
+			continue
+		}
+
+		path := fmt.Sprintf("spec.panels[%d].query_panel", i)
+		slug, err := l.datasetSlug(name)
+		if err != nil {
+			return err
+		}
+		if slug == "" {
+			r.errorf(RuleUnknownDataset, path+".dataset", "dataset %q not found", name)
+			continue
+		}
+
+		query, err := api.DecodeResponse[manifest.Object](l.client.GetQuery(l.ctx, slug, id))
+		if isNotFound(err) {
+			r.errorf(RuleUnknownQuery, path+".query_id", "query %s not found in dataset %s", id, slug)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("getting query %s: %w", id, err)
+		}
+		s, err := l.schema(slug)
+		if err != nil {
+			return err
+		}
+		if s != nil {
+			lintQuery(r, path+".query_id("+id+")", query, s)
+		}
+	}
+	return nil
+}
+
+// datasetSlug resolves a dataset name, as boards report it, to its slug. A
+// slug is accepted as well.
+func (l *linter) datasetSlug(name string) (string, error) {
+	if l.datasets == nil {
+		datasets, err := api.DecodeResponse[[]manifest.Object](l.client.ListDatasets(l.ctx))
+		if err != nil {
+			return "", fmt.Errorf("listing datasets: %w", err)
+		}
+		l.datasets = map[string]string{}
+		for _, d := range datasets {
+			n, _ := d["name"].(string)
+			slug, _ := d["slug"].(string)
+			l.datasets[n] = slug
+			l.datasets[slug] = slug
+		}
+	}
+	return l.datasets[name], nil
+}
+
+func (l *linter) lintRecipients(r *reporter, path string, v any) error {
+	list, _ := v.([]any)
+	if len(list) == 0 {
+		return nil
+	}
+	if l.recipients == nil {
+		recipients, err := api.DecodeResponse[[]manifest.Object](l.client.ListRecipients(l.ctx))
+		if err != nil {
+			return fmt.Errorf("listing recipients: %w", err)
+		}
+		l.recipients = recipients
+	}
+
+	kind, _ := manifest.LookupKind(manifest.KindRecipient)
+	ids := map[string]bool{}
+	keys := map[string]bool{}
+	for _, rcpt := range l.recipients {
+		ids[manifest.ObjectID(rcpt)] = true
+		keys[kind.Key(rcpt)] = true
+	}
+	for _, m := range l.manifests {
+		if m.Kind == manifest.KindRecipient {
+			ids[m.ID] = true
+			keys[m.Key()] = true
+		}
+	}
+
+	for i, item := range list {
+		rcpt, _ := item.(map[string]any)
+		at := fmt.Sprintf("%s[%d]", path, i)
+		if id, _ := rcpt["id"].(string); id != "" {
+			if !ids[id] {
+				r.errorf(RuleUnknownRecipient, at+".id", "recipient %s not found", id)
+			}
+			continue
+		}
+		typ, _ := rcpt["type"].(string)
+		target, _ := rcpt["target"].(string)
+		if key := typ + ":" + target; !keys[key] {
+			r.errorf(RuleUnknownRecipient, at, "recipient %s not found", key)
+		}
+	}
+	return nil
+}
+
+var (
+	numericTypes = []string{string(api.ColumnTypeInteger), string(api.ColumnTypeFloat)}
+
+	// columnlessOps count events rather than aggregating a column.
+	columnlessOps = []string{string(api.QueryOpCOUNT), string(api.QueryOpCONCURRENCY)}
+
+	stringFilterOps = []string{
+		string(api.FilterOpContains), string(api.FilterOpDoesNotContain),
+		string(api.FilterOpStartsWith), string(api.FilterOpDoesNotStartWith),
+		string(api.FilterOpEndsWith), string(api.FilterOpDoesNotEndWith),
+	}
+	comparisonFilterOps = []string{
+		string(api.FilterOpGreaterThan), string(api.FilterOpGreaterThanEqual),
+		string(api.FilterOpLessThan), string(api.FilterOpLessThanEqual),
+	}
+)
+
+// lintQuery checks a query spec's columns and ops against a dataset schema.
+// Columns defined inline by the query's calculated_fields are known.
+func lintQuery(r *reporter, path string, query map[string]any, s *schema) {
+	inline := map[string]bool{}
+	for _, f := range items(query["calculated_fields"]) {
+		if name, _ := f["name"].(string); name != "" {
+			inline[name] = true
+		}
+	}
+
+	// column reports an unknown column and returns the type of a known one,
+	// which is empty for calculated fields.
+	column := func(at string, name string) (string, bool) {
+		if inline[name] {
+			return "", true
+		}
+		typ, ok := s.columns[name]
+		if !ok {
+			r.errorf(RuleUnknownColumn, at, "column %q not found", name)
+		}
+		return typ, ok
+	}
+
+	breakdowns, _ := query["breakdowns"].([]any)
+	for i, b := range breakdowns {
+		if name, _ := b.(string); name != "" {
+			column(fmt.Sprintf("%s.breakdowns[%d]", path, i), name)
+		}
+	}
+
+	for i, c := range items(query["calculations"]) {
+		at := fmt.Sprintf("%s.calculations[%d]", path, i)
+		op, _ := c["op"].(string)
+		name, _ := c["column"].(string)
+		if !api.QueryOp(op).Valid() {
+			r.errorf(RuleInvalidOp, at+".op", "unknown calculation op %q", op)
+			continue
+		}
+		switch {
+		case slices.Contains(columnlessOps, op):
+			if name != "" {
+				r.errorf(RuleOpColumnType, at+".column", "%s does not take a column", op)
+			}
+		case name == "":
+			r.errorf(RuleOpColumnType, at+".column", "%s requires a column", op)
+		default:
+			typ, ok := column(at+".column", name)
+			if ok && typ != "" && op != string(api.QueryOpCOUNTDISTINCT) && !slices.Contains(numericTypes, typ) {
+				r.errorf(RuleOpColumnType, at+".op", "%s requires a numeric column, but %q is a %s", op, name, typ)
+			}
+		}
+	}
+
+	for i, f := range items(query["filters"]) {
+		at := fmt.Sprintf("%s.filters[%d]", path, i)
+		op, _ := f["op"].(string)
+		name, _ := f["column"].(string)
+		if !api.FilterOp(op).Valid() {
+			r.errorf(RuleInvalidOp, at+".op", "unknown filter op %q", op)
+			continue
+		}
+		typ, ok := column(at+".column", name)
+		if !ok || typ == "" {
+			continue
+		}
+		switch {
+		case slices.Contains(stringFilterOps, op) && typ != string(api.ColumnTypeString):
+			r.errorf(RuleOpColumnType, at+".op", "%s requires a string column, but %q is a %s", op, name, typ)
+		case slices.Contains(comparisonFilterOps, op) && typ == string(api.ColumnTypeBoolean):
+			r.errorf(RuleOpColumnType, at+".op", "%s cannot compare boolean column %q", op, name)
+		case slices.Contains(comparisonFilterOps, op) && typ == string(api.ColumnTypeString):
+			r.warnf(RuleOpColumnType, at+".op", "%s compares string column %q lexically", op, name)
+		}
+	}
+
+	for i, o := range items(query["orders"]) {
+		at := fmt.Sprintf("%s.orders[%d]", path, i)
+		if op, _ := o["op"].(string); op != "" && !api.QueryOp(op).Valid() {
+			r.errorf(RuleInvalidOp, at+".op", "unknown calculation op %q", op)
+		}
+		if name, _ := o["column"].(string); name != "" {
+			column(at+".column", name)
+		}
+	}
+
+	for i, h := range items(query["havings"]) {
+		at := fmt.Sprintf("%s.havings[%d]", path, i)
+		if op, _ := h["op"].(string); !api.HavingOp(op).Valid() {
+			r.errorf(RuleInvalidOp, at+".op", "unknown having op %q", op)
+		}
+		if op, _ := h["calculate_op"].(string); !api.QueryOp(op).Valid() {
+			r.errorf(RuleInvalidOp, at+".calculate_op", "unknown calculation op %q", op)
+		}
+		if name, _ := h["column"].(string); name != "" {
+			column(at+".column", name)
+		}
+	}
+}
+
+// expressionColumn matches a column reference in a calculated field
+// expression: $name, or $"name" for names with other characters.
+var expressionColumn = regexp.MustCompile(`\$(?:"((?:[^"\\]|\\.)*)"|([A-Za-z0-9_.\-]+))`)
+
+// ExpressionColumns returns the columns a calculated field expression
+// references, in order of first use.
+func ExpressionColumns(expr string) []string {
+	var columns []string
+	for _, m := range expressionColumn.FindAllStringSubmatch(expr, -1) {
+		name := m[2]
+		if name == "" {
+			name = strings.ReplaceAll(m[1], `\"`, `"`)
+		}
+		if !slices.Contains(columns, name) {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+func items(v any) []map[string]any {
+	list, _ := v.([]any)
+	out := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func number(v any) (float64, bool) {
+	n, ok := v.(float64)
+	return n, ok
+}
+
+func isNotFound(err error) bool {
+	var apiErr *api.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
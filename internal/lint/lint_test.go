@@ -0,0 +1,262 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+	"github.com/bendrucker/honeycomb-cli/internal/manifest"
+)
+
+func newServer(t *testing.T) api.ClientInterface {
+	t.Helper()
+	responses := map[string]string{
+		"/1/columns/production": `[
+			{"key_name": "duration_ms", "type": "float"},
+			{"key_name": "service.name", "type": "string"},
+			{"key_name": "error", "type": "boolean"}
+		]`,
+		"/1/derived_columns/production": `[{"alias": "sli.ok", "expression": "LT($duration_ms, 300)"}]`,
+		"/1/recipients":                 `[{"id": "r1", "type": "email", "details": {"email_address": "oncall@example.com"}}]`,
+		"/1/datasets":                   `[{"name": "Production", "slug": "production"}]`,
+		"/1/queries/production/q1":      `{"id": "q1", "breakdowns": ["endpoint"], "calculations": [{"op": "COUNT"}]}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := api.NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func parse(t *testing.T, doc string) []manifest.Manifest {
+	t.Helper()
+	m, err := manifest.Parse([]byte(doc), "defs.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func lint(t *testing.T, doc string) []string {
+	t.Helper()
+	diags, err := Lint(context.Background(), newServer(t), parse(t, doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := make([]string, len(diags))
+	for i, d := range diags {
+		lines[i] = d.Path + " " + d.Rule
+	}
+	return lines
+}
+
+func TestLint_Valid(t *testing.T) {
+	got := lint(t, `
+kind: Trigger
+dataset: production
+spec:
+  name: Slow
+  frequency: 300
+  query:
+    time_range: 1200
+    breakdowns: [service.name]
+    calculations: [{op: P99, column: duration_ms}]
+    filters: [{op: starts-with, column: service.name, value: api}]
+  threshold: {op: ">", value: 500, exceeded_limit: 2}
+  recipients: [{id: r1}, {type: email, target: oncall@example.com}]
+---
+kind: SLO
+dataset: production
+spec: {name: Fast, sli: {alias: sli.ok}}
+`)
+	if len(got) != 0 {
+		t.Errorf("diagnostics = %v, want none", got)
+	}
+}
+
+func TestLint_Trigger(t *testing.T) {
+	got := lint(t, `
+kind: Trigger
+dataset: production
+spec:
+  name: Broken
+  frequency: 90
+  query:
+    time_range: 3600
+    breakdowns: [endpoint]
+    calculations: [{op: AVG, column: service.name}, {op: COUNT, column: duration_ms}]
+    filters: [{op: contains, column: error}, {op: like, column: error}]
+    havings: [{op: "=", calculate_op: MEDIAN, column: duration_ms}]
+  threshold: {op: "!=", value: 1, exceeded_limit: 9}
+  recipients: [{id: r2}, {type: slack, target: "#alerts"}]
+`)
+	want := []string{
+		"spec.frequency trigger-frequency",
+		"spec.query.breakdowns[0] unknown-column",
+		"spec.query.calculations[0].op op-column-type",
+		"spec.query.calculations[1].column op-column-type",
+		"spec.query.filters[0].op op-column-type",
+		"spec.query.filters[1].op invalid-op",
+		"spec.query.havings[0].calculate_op invalid-op",
+		"spec.query.time_range trigger-frequency",
+		"spec.query.calculations trigger-query",
+		"spec.threshold.op trigger-threshold",
+		"spec.threshold.exceeded_limit trigger-threshold",
+		"spec.recipients[0].id unknown-recipient",
+		"spec.recipients[1] unknown-recipient",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("diagnostics =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLint_ManifestDefinitions(t *testing.T) {
+	got := lint(t, `
+kind: CalculatedField
+dataset: production
+spec: {alias: slow, expression: 'GT($duration_ms, $"latency budget")'}
+---
+kind: SLO
+dataset: production
+spec: {name: Slow, sli: {alias: slow}}
+---
+kind: SLO
+dataset: production
+spec: {name: Missing, sli: {alias: missing}}
+---
+kind: Recipient
+spec: {type: slack, details: {slack_channel: "#alerts"}}
+---
+kind: BurnAlert
+dataset: production
+slo: Slow
+spec: {alert_type: exhaustion_time, description: Soon, recipients: [{type: slack, target: "#alerts"}]}
+`)
+	want := []string{
+		"spec.expression unknown-column",
+		"spec.sli.alias unknown-sli",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("diagnostics = %v, want %v", got, want)
+	}
+}
+
+func TestLint_Dataset(t *testing.T) {
+	got := lint(t, `
+kind: Trigger
+dataset: missing
+spec: {name: T, threshold: {op: ">", value: 1}, query: {calculations: [{op: COUNT}]}}
+`)
+	if want := []string{"dataset unknown-dataset"}; !slices.Equal(got, want) {
+		t.Errorf("diagnostics = %v, want %v", got, want)
+	}
+}
+
+func TestLint_Board(t *testing.T) {
+	got := lint(t, `
+kind: Board
+spec:
+  name: Overview
+  panels:
+    - {type: query, query_panel: {dataset: Production, query_id: q1}}
+    - {type: query, query_panel: {dataset: Production, query_id: q2}}
+    - {type: query, query_panel: {dataset: Staging, query_id: q3}}
+    - {type: query, query_panel: {query_id: q4}}
+`)
+	want := []string{
+		"spec.panels[0].query_panel.query_id(q1).breakdowns[0] unknown-column",
+		"spec.panels[1].query_panel.query_id unknown-query",
+		"spec.panels[2].query_panel.dataset unknown-dataset",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("diagnostics = %v, want %v", got, want)
+	}
+}
+
+func TestExpressionColumns(t *testing.T) {
+	got := ExpressionColumns(`IF(EQUALS($status_code, 500), $"http.route", $status_code)`)
+	if want := []string{"status_code", "http.route"}; !slices.Equal(got, want) {
+		t.Errorf("ExpressionColumns = %v, want %v", got, want)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteSARIF(&buf, []Diagnostic{{
+		Source:   "defs/triggers.yaml#2",
+		Path:     "spec.query.breakdowns[0]",
+		Severity: SeverityError,
+		Rule:     RuleUnknownColumn.ID,
+		Message:  `column "endpoint" not found`,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("log = %s", buf.String())
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(Rules) {
+		t.Errorf("rules = %d, want %d", len(run.Tool.Driver.Rules), len(Rules))
+	}
+	result := run.Results[0]
+	if result.RuleID != "unknown-column" || result.Level != "error" {
+		t.Errorf("result = %+v", result)
+	}
+	loc := result.Locations[0]
+	if loc.PhysicalLocation.ArtifactLocation.URI != "defs/triggers.yaml" {
+		t.Errorf("uri = %q, want defs/triggers.yaml", loc.PhysicalLocation.ArtifactLocation.URI)
+	}
+	if got := loc.LogicalLocations[0].FullyQualifiedName; got != "#2/spec.query.breakdowns[0]" {
+		t.Errorf("logical location = %q", got)
+	}
+}
@@ -0,0 +1,280 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+const (
+	KindBoard           = "Board"
+	KindTrigger         = "Trigger"
+	KindSLO             = "SLO"
+	KindBurnAlert       = "BurnAlert"
+	KindRecipient       = "Recipient"
+	KindCalculatedField = "CalculatedField"
+	KindMarkerSetting   = "MarkerSetting"
+)
+
+// Scope locates the resources of a kind: the dataset for dataset-scoped kinds
+// and, for burn alerts, the SLO they belong to.
+type Scope struct {
+	Dataset string
+	SLOID   string
+}
+
+type Object = map[string]any
+
+// Kind describes how manifests of one kind map onto the API.
+type Kind struct {
+	Name string
+	// Dataset reports whether resources of this kind belong to a dataset.
+	Dataset bool
+	// Schema names the API schema whose read-only fields are stripped from an
+	// existing resource before it is sent back in an update.
+	Schema string
+	// KeyField describes Key for documentation and errors.
+	KeyField string
+	// Key returns the value that matches a spec to an existing resource, or ""
+	// when the spec has none.
+	Key func(spec Object) string
+
+	List   func(ctx context.Context, c api.ClientInterface, s Scope) ([]Object, error)
+	Create func(ctx context.Context, c api.ClientInterface, s Scope, body []byte) (Object, error)
+	Update func(ctx context.Context, c api.ClientInterface, s Scope, id string, body []byte) (Object, error)
+	Delete func(ctx context.Context, c api.ClientInterface, s Scope, id string) error
+
+	// Prepare adjusts an update body, merged from the existing resource and
+	// the spec, before it is sent. It may be nil.
+	Prepare func(body, spec Object)
+}
+
+// Kinds lists every supported kind in dependency order: a kind only refers to
+// kinds before it, so creates and updates run in this order and deletes in
+// reverse.
+var Kinds = []Kind{
+	{
+		Name:     KindRecipient,
+		Schema:   "RecipientProperties",
+		KeyField: "type and target",
+		Key:      recipientKey,
+		List: func(ctx context.Context, c api.ClientInterface, _ Scope) ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListRecipients(ctx))
+		},
+		Create: func(ctx context.Context, c api.ClientInterface, _ Scope, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateRecipientWithBody(ctx, "application/json", bytes.NewReader(body)))
+		},
+		Update: func(ctx context.Context, c api.ClientInterface, _ Scope, id string, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.UpdateRecipientWithBody(ctx, id, "application/json", bytes.NewReader(body)))
+		},
+		Delete: func(ctx context.Context, c api.ClientInterface, _ Scope, id string) error {
+			return check(c.DeleteRecipient(ctx, id))
+		},
+	},
+	{
+		Name:     KindCalculatedField,
+		Dataset:  true,
+		Schema:   "CalculatedField",
+		KeyField: "alias",
+		Key:      stringField("alias"),
+		List: func(ctx context.Context, c api.ClientInterface, s Scope) ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListCalculatedFields(ctx, s.Dataset, nil))
+		},
+		Create: func(ctx context.Context, c api.ClientInterface, s Scope, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateCalculatedFieldWithBody(ctx, s.Dataset, "application/json", bytes.NewReader(body)))
+		},
+		Update: func(ctx context.Context, c api.ClientInterface, s Scope, id string, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.UpdateCalculatedFieldWithBody(ctx, s.Dataset, id, "application/json", bytes.NewReader(body)))
+		},
+		Delete: func(ctx context.Context, c api.ClientInterface, s Scope, id string) error {
+			return check(c.DeleteCalculatedField(ctx, s.Dataset, id))
+		},
+	},
+	{
+		Name:     KindMarkerSetting,
+		Dataset:  true,
+		Schema:   "MarkerSetting",
+		KeyField: "type",
+		Key:      stringField("type"),
+		List: func(ctx context.Context, c api.ClientInterface, s Scope) ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListMarkerSettings(ctx, s.Dataset))
+		},
+		Create: func(ctx context.Context, c api.ClientInterface, s Scope, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateMarkerSettingWithBody(ctx, s.Dataset, "application/json", bytes.NewReader(body)))
+		},
+		Update: func(ctx context.Context, c api.ClientInterface, s Scope, id string, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.UpdateMarkerSettingsWithBody(ctx, s.Dataset, id, "application/json", bytes.NewReader(body)))
+		},
+		Delete: func(ctx context.Context, c api.ClientInterface, s Scope, id string) error {
+			return check(c.DeleteMarkerSettings(ctx, s.Dataset, id))
+		},
+	},
+	{
+		Name:     KindSLO,
+		Dataset:  true,
+		Schema:   "SLO",
+		KeyField: "name",
+		Key:      stringField("name"),
+		List: func(ctx context.Context, c api.ClientInterface, s Scope) ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListSlos(ctx, s.Dataset))
+		},
+		Create: func(ctx context.Context, c api.ClientInterface, s Scope, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateSloWithBody(ctx, s.Dataset, "application/json", bytes.NewReader(body)))
+		},
+		Update: func(ctx context.Context, c api.ClientInterface, s Scope, id string, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.UpdateSloWithBody(ctx, s.Dataset, id, "application/json", bytes.NewReader(body)))
+		},
+		Delete: func(ctx context.Context, c api.ClientInterface, s Scope, id string) error {
+			return check(c.DeleteSlo(ctx, s.Dataset, id))
+		},
+	},
+	{
+		Name:     KindBurnAlert,
+		Dataset:  true,
+		Schema:   "BurnAlertSharedParams",
+		KeyField: "description",
+		Key:      stringField("description"),
+		List: func(ctx context.Context, c api.ClientInterface, s Scope) ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListBurnAlertsBySlo(ctx, s.Dataset, &api.ListBurnAlertsBySloParams{SloId: s.SLOID}))
+		},
+		Create: func(ctx context.Context, c api.ClientInterface, s Scope, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateBurnAlertWithBody(ctx, s.Dataset, "application/json", bytes.NewReader(body)))
+		},
+		Update: func(ctx context.Context, c api.ClientInterface, s Scope, id string, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.UpdateBurnAlertWithBody(ctx, s.Dataset, id, "application/json", bytes.NewReader(body)))
+		},
+		Delete: func(ctx context.Context, c api.ClientInterface, s Scope, id string) error {
+			return check(c.DeleteBurnAlert(ctx, s.Dataset, id))
+		},
+		Prepare: func(body, _ Object) {
+			delete(body, "slo_id")
+		},
+	},
+	{
+		Name:     KindTrigger,
+		Dataset:  true,
+		Schema:   "TriggerResponse",
+		KeyField: "name",
+		Key:      stringField("name"),
+		List: func(ctx context.Context, c api.ClientInterface, s Scope) ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListTriggers(ctx, s.Dataset))
+		},
+		Create: func(ctx context.Context, c api.ClientInterface, s Scope, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateTriggerWithBody(ctx, s.Dataset, "application/json", bytes.NewReader(body)))
+		},
+		Update: func(ctx context.Context, c api.ClientInterface, s Scope, id string, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.UpdateTriggerWithBody(ctx, s.Dataset, id, "application/json", bytes.NewReader(body)))
+		},
+		Delete: func(ctx context.Context, c api.ClientInterface, s Scope, id string) error {
+			return check(c.DeleteTrigger(ctx, s.Dataset, id))
+		},
+		// The API returns both query_id and the resolved inline query but
+		// rejects a body carrying both, so keep whichever the spec sets and
+		// otherwise the authoritative query_id.
+		Prepare: func(body, spec Object) {
+			if _, ok := spec["query"]; ok {
+				delete(body, "query_id")
+			} else if body["query_id"] != nil {
+				delete(body, "query")
+			}
+		},
+	},
+	{
+		Name:     KindBoard,
+		Schema:   "Board",
+		KeyField: "name",
+		Key:      stringField("name"),
+		List: func(ctx context.Context, c api.ClientInterface, _ Scope) ([]Object, error) {
+			return api.DecodeResponse[[]Object](c.ListBoards(ctx))
+		},
+		Create: func(ctx context.Context, c api.ClientInterface, _ Scope, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.CreateBoardWithBody(ctx, "application/json", bytes.NewReader(body)))
+		},
+		Update: func(ctx context.Context, c api.ClientInterface, _ Scope, id string, body []byte) (Object, error) {
+			return api.DecodeResponse[Object](c.UpdateBoardWithBody(ctx, id, "application/json", bytes.NewReader(body)))
+		},
+		Delete: func(ctx context.Context, c api.ClientInterface, _ Scope, id string) error {
+			return check(c.DeleteBoard(ctx, id))
+		},
+		Prepare: func(body, _ Object) {
+			StripPanelDatasets(body)
+		},
+	},
+}
+
+// LookupKind returns the kind with the given name.
+func LookupKind(name string) (Kind, bool) {
+	for _, k := range Kinds {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Kind{}, false
+}
+
+// KindNames returns the names of every supported kind.
+func KindNames() []string {
+	names := make([]string, len(Kinds))
+	for i, k := range Kinds {
+		names[i] = k.Name
+	}
+	return names
+}
+
+func kindIndex(name string) int {
+	for i, k := range Kinds {
+		if k.Name == name {
+			return i
+		}
+	}
+	return len(Kinds)
+}
+
+func stringField(name string) func(Object) string {
+	return func(o Object) string {
+		s, _ := o[name].(string)
+		return s
+	}
+}
+
+// recipientTargets are the detail fields that identify a recipient, one per
+// recipient type.
+var recipientTargets = []string{"email_address", "slack_channel", "pagerduty_integration_name", "webhook_name"}
+
+// recipientKey identifies a recipient by its type and target, such as
+// "email:oncall@example.com" or "slack:#alerts".
+func recipientKey(o Object) string {
+	typ, _ := o["type"].(string)
+	details, _ := o["details"].(map[string]any)
+	for _, f := range recipientTargets {
+		if target, _ := details[f].(string); target != "" && typ != "" {
+			return typ + ":" + target
+		}
+	}
+	return ""
+}
+
+// StripPanelDatasets removes the dataset from a board's query panels. The API
+// returns it on read but rejects it on write.
+func StripPanelDatasets(board Object) {
+	panels, _ := board["panels"].([]any)
+	for _, p := range panels {
+		panel, _ := p.(map[string]any)
+		if q, ok := panel["query_panel"].(map[string]any); ok {
+			delete(q, "dataset")
+		}
+	}
+}
+
+// ObjectID returns the id of an API object.
+func ObjectID(o Object) string {
+	id, _ := o["id"].(string)
+	return id
+}
+
+func check(resp *http.Response, err error) error {
+	_, err = api.ReadResponse(resp, err)
+	return err
+}
@@ -0,0 +1,196 @@
+// Package manifest reads declarative resource definitions and reconciles them
+// with the Honeycomb API.
+//
+// A manifest names a resource kind, the dataset it belongs to when the kind is
+// dataset-scoped, and a spec in the same shape as the API's JSON request body:
+//
+//	kind: Trigger
+//	dataset: production
+//	spec:
+//	  name: High latency
+//	  frequency: 300
+//	  ...
+//
+// Manifests are matched to existing resources by a per-kind key, such as a
+// trigger's name (see Kind.KeyField), or pinned to a resource with id.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+type Manifest struct {
+	Kind    string `json:"kind"`
+	Dataset string `json:"dataset,omitempty"`
+	// ID pins the manifest to an existing resource, overriding the key match.
+	// It keeps a resource stable across renames.
+	ID string `json:"id,omitempty"`
+	// SLO names the SLO a BurnAlert belongs to, resolved in the same dataset.
+	// It can be omitted when the spec sets slo.id.
+	SLO  string         `json:"slo,omitempty"`
+	Spec map[string]any `json:"spec"`
+
+	// Source is the file the manifest was read from, for error messages.
+	Source string `json:"-"`
+}
+
+// Key returns the value that matches the manifest to an existing resource.
+func (m Manifest) Key() string {
+	k, ok := LookupKind(m.Kind)
+	if !ok {
+		return ""
+	}
+	return k.Key(m.Spec)
+}
+
+// Validate checks the manifest's kind, scope, and key.
+func (m Manifest) Validate() error {
+	k, ok := LookupKind(m.Kind)
+	if !ok {
+		return fmt.Errorf("%s: unknown kind %q, must be one of %s", m.Source, m.Kind, strings.Join(KindNames(), ", "))
+	}
+	if k.Dataset && m.Dataset == "" {
+		return fmt.Errorf("%s: %s requires dataset", m.Source, m.Kind)
+	}
+	if !k.Dataset && m.Dataset != "" {
+		return fmt.Errorf("%s: %s is not dataset-scoped, remove dataset", m.Source, m.Kind)
+	}
+	if m.SLO != "" && m.Kind != KindBurnAlert {
+		return fmt.Errorf("%s: slo is only valid for %s", m.Source, KindBurnAlert)
+	}
+	if len(m.Spec) == 0 {
+		return fmt.Errorf("%s: %s has an empty spec", m.Source, m.Kind)
+	}
+	if m.ID == "" && k.Key(m.Spec) == "" {
+		return fmt.Errorf("%s: %s requires spec %s or id to match existing resources", m.Source, m.Kind, k.KeyField)
+	}
+	if m.Kind == KindBurnAlert && m.SLO == "" && sloID(m.Spec) == "" {
+		return fmt.Errorf("%s: %s requires slo or spec slo.id", m.Source, m.Kind)
+	}
+	return nil
+}
+
+// String identifies the manifest in plans and errors, such as
+// "Trigger production/High latency".
+func (m Manifest) String() string {
+	name := m.Key()
+	if name == "" {
+		name = m.ID
+	}
+	if m.Dataset != "" {
+		name = m.Dataset + "/" + name
+	}
+	return m.Kind + " " + name
+}
+
+var extensions = []string{".yaml", ".yml", ".json"}
+
+// Load reads manifests from a file, or from every .yaml, .yml, and .json file
+// under a directory in lexical order. YAML files may hold several documents
+// separated by ---; JSON files hold one manifest or an array of them. Every
+// manifest is validated.
+func Load(path string) ([]Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	if info.IsDir() {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && slices.Contains(extensions, strings.ToLower(filepath.Ext(p))) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		files = []string{path}
+	}
+
+	var manifests []Manifest
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := Parse(data, f)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, parsed...)
+	}
+	return manifests, nil
+}
+
+// Parse decodes and validates the manifests in data, which may be YAML or
+// JSON. source names the input in errors.
+func Parse(data []byte, source string) ([]Manifest, error) {
+	var docs []any
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		switch v := doc.(type) {
+		case nil:
+		case []any:
+			docs = append(docs, v...)
+		default:
+			docs = append(docs, v)
+		}
+	}
+
+	manifests := make([]Manifest, 0, len(docs))
+	for i, doc := range docs {
+		// Round-trip through JSON so specs hold the same types as decoded API
+		// responses (float64 numbers, map[string]any objects) and compare
+		// cleanly against them.
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", source, i+1, err)
+		}
+		var m Manifest
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("%s: document %d: %w", source, i+1, err)
+		}
+		m.Source = source
+		if len(docs) > 1 {
+			m.Source = fmt.Sprintf("%s#%d", source, i+1)
+		}
+		if err := m.Validate(); err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+func sloID(spec map[string]any) string {
+	slo, _ := spec["slo"].(map[string]any)
+	id, _ := slo["id"].(string)
+	return id
+}
@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse_YAMLDocuments(t *testing.T) {
+	data := []byte(`kind: Trigger
+dataset: production
+spec:
+  name: High latency
+  frequency: 300
+---
+kind: Board
+spec:
+  name: Overview
+`)
+
+	manifests, err := Parse(data, "config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("got %d manifests, want 2", len(manifests))
+	}
+
+	trigger := manifests[0]
+	if trigger.Kind != KindTrigger || trigger.Dataset != "production" || trigger.Key() != "High latency" {
+		t.Errorf("trigger = %+v", trigger)
+	}
+	if trigger.Spec["frequency"] != float64(300) {
+		t.Errorf("frequency = %#v, want float64(300)", trigger.Spec["frequency"])
+	}
+	if trigger.Source != "config.yaml#1" {
+		t.Errorf("source = %q", trigger.Source)
+	}
+	if manifests[1].String() != "Board Overview" {
+		t.Errorf("String() = %q", manifests[1].String())
+	}
+}
+
+func TestParse_JSONArray(t *testing.T) {
+	data := []byte(`[
+		{"kind": "CalculatedField", "dataset": "production", "spec": {"alias": "is_slow", "expression": "GT($duration_ms, 1000)"}},
+		{"kind": "Recipient", "spec": {"type": "email", "details": {"email_address": "oncall@example.com"}}}
+	]`)
+
+	manifests, err := Parse(data, "config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifests[0].Key(); got != "is_slow" {
+		t.Errorf("calculated field key = %q", got)
+	}
+	if got := manifests[1].Key(); got != "email:oncall@example.com" {
+		t.Errorf("recipient key = %q", got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+		want string
+	}{
+		{"unknown kind", "kind: Widget\nspec: {name: x}", `unknown kind "Widget"`},
+		{"missing dataset", "kind: Trigger\nspec: {name: x}", "requires dataset"},
+		{"unexpected dataset", "kind: Board\ndataset: x\nspec: {name: x}", "not dataset-scoped"},
+		{"empty spec", "kind: Board\nspec: {}", "empty spec"},
+		{"missing key", "kind: Board\nspec: {description: x}", "requires spec name or id"},
+		{"burn alert without slo", "kind: BurnAlert\ndataset: x\nspec: {description: x}", "requires slo"},
+		{"unknown field", "kind: Board\nname: x\nspec: {name: x}", "unknown field"},
+		{"invalid yaml", "kind: [", "config.yaml"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data), "config.yaml")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want error containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Directory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"b.yaml":        "kind: Board\nspec: {name: B}\n",
+		"a.json":        `{"kind": "Board", "spec": {"name": "A"}}`,
+		"nested/c.yml":  "kind: Board\nspec: {name: C}\n",
+		"README.md":     "not a manifest",
+		"nested/d.yaml": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifests, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, m := range manifests {
+		names = append(names, m.Key())
+	}
+	if got := strings.Join(names, ","); got != "A,B,C" {
+		t.Errorf("loaded %s, want A,B,C", got)
+	}
+}
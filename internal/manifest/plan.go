@@ -0,0 +1,431 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+// Changes lists the top-level spec fields an update changes.
+type Changes []string
+
+func (c Changes) FormatField() string {
+	return strings.Join(c, ", ")
+}
+
+// Action is one step of a plan.
+type Action struct {
+	Op      string  `json:"action" col:"Action"`
+	Kind    string  `json:"kind" col:"Kind"`
+	Dataset string  `json:"dataset,omitempty" col:"Dataset"`
+	Name    string  `json:"name" col:"Name"`
+	ID      string  `json:"id,omitempty" col:"ID"`
+	Changes Changes `json:"changes,omitempty" col:"Changes"`
+	Source  string  `json:"source,omitempty"`
+
+	manifest *Manifest
+	existing Object
+	scope    Scope
+}
+
+func (a Action) String() string {
+	name := a.Name
+	if a.Dataset != "" {
+		name = a.Dataset + "/" + name
+	}
+	return fmt.Sprintf("%s %s %s", a.Op, a.Kind, name)
+}
+
+// Spec returns the manifest spec the action reconciles to, or nil for a
+// delete.
+func (a Action) Spec() Object {
+	if a.manifest == nil {
+		return nil
+	}
+	return a.manifest.Spec
+}
+
+// Live returns the existing resource with its read-only fields stripped, or
+// nil for a create.
+func (a Action) Live() (Object, error) {
+	if a.existing == nil {
+		return nil, nil
+	}
+	k, _ := LookupKind(a.Kind)
+	data, err := api.MarshalStrippingReadOnly(a.existing, k.Schema)
+	if err != nil {
+		return nil, err
+	}
+	var live Object
+	if err := json.Unmarshal(data, &live); err != nil {
+		return nil, err
+	}
+	return live, nil
+}
+
+type PlanOptions struct {
+	// Prune deletes existing resources that no manifest matches. Only the
+	// kinds and datasets the manifests cover are pruned, and burn alerts only
+	// for the SLOs they reference.
+	Prune bool
+}
+
+// Plan compares manifests with the existing resources and returns the
+// actions that reconcile them: creates and updates in dependency order,
+// followed by any deletes in reverse order. Manifests that already match are
+// omitted.
+func Plan(ctx context.Context, c api.ClientInterface, manifests []Manifest, opts PlanOptions) ([]Action, error) {
+	manifests = slices.Clone(manifests)
+	slices.SortStableFunc(manifests, func(a, b Manifest) int {
+		return kindIndex(a.Kind) - kindIndex(b.Kind)
+	})
+
+	p := &planner{ctx: ctx, client: c, lists: map[scopeKey][]Object{}, matched: map[scopeKey]map[string]bool{}}
+
+	seen := map[string]string{}
+	var actions []Action
+	for i := range manifests {
+		m := &manifests[i]
+		if err := m.Validate(); err != nil {
+			return nil, err
+		}
+
+		id := strings.Join([]string{m.Kind, m.Dataset, m.SLO, sloID(m.Spec), m.Key(), m.ID}, "\x00")
+		if prev, ok := seen[id]; ok {
+			return nil, fmt.Errorf("%s: duplicates %s in %s", m.Source, m, prev)
+		}
+		seen[id] = m.Source
+
+		action, err := p.plan(m, manifests)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Source, err)
+		}
+		if action != nil {
+			actions = append(actions, *action)
+		}
+	}
+
+	if opts.Prune {
+		actions = append(actions, p.prune()...)
+	}
+	return actions, nil
+}
+
+type scopeKey struct {
+	kind string
+	Scope
+}
+
+type planner struct {
+	ctx     context.Context
+	client  api.ClientInterface
+	lists   map[scopeKey][]Object
+	matched map[scopeKey]map[string]bool
+	// declared lists the scopes manifests declare resources in, which are
+	// the only scopes pruned. Scopes listed only to look up a dependency,
+	// such as the SLO of a burn alert, are not.
+	declared []scopeKey
+}
+
+func (p *planner) list(key scopeKey) ([]Object, error) {
+	if list, ok := p.lists[key]; ok {
+		return list, nil
+	}
+	k, _ := LookupKind(key.kind)
+	list, err := k.List(p.ctx, p.client, key.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s resources: %w", key.kind, err)
+	}
+	p.lists[key] = list
+	return list, nil
+}
+
+func (p *planner) declare(key scopeKey) {
+	if _, ok := p.matched[key]; ok {
+		return
+	}
+	p.matched[key] = map[string]bool{}
+	p.declared = append(p.declared, key)
+}
+
+func (p *planner) plan(m *Manifest, all []Manifest) (*Action, error) {
+	k, _ := LookupKind(m.Kind)
+	action := &Action{
+		Kind:     m.Kind,
+		Dataset:  m.Dataset,
+		Name:     m.Key(),
+		Source:   m.Source,
+		manifest: m,
+		scope:    Scope{Dataset: m.Dataset},
+	}
+	if action.Name == "" {
+		action.Name = m.ID
+	}
+
+	if m.Kind == KindBurnAlert {
+		id, err := p.resolveSLO(m, all)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			// The SLO is created by this plan, so the alert cannot exist yet.
+			// Apply resolves the SLO's ID once it is created.
+			action.Op = OpCreate
+			return action, nil
+		}
+		action.scope.SLOID = id
+	}
+
+	key := scopeKey{kind: m.Kind, Scope: action.scope}
+	p.declare(key)
+	existing, err := p.list(key)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := match(k, m, existing)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		action.Op = OpCreate
+		return action, nil
+	}
+
+	action.ID = ObjectID(current)
+	p.matched[key][action.ID] = true
+
+	changes := Diff(m.Spec, current)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	action.Op = OpUpdate
+	action.Changes = changes
+	action.existing = current
+	return action, nil
+}
+
+// resolveSLO returns the ID of the SLO a burn alert manifest belongs to, or
+// "" when the SLO is defined by another manifest and does not exist yet.
+func (p *planner) resolveSLO(m *Manifest, all []Manifest) (string, error) {
+	if id := sloID(m.Spec); id != "" {
+		return id, nil
+	}
+
+	slos, err := p.list(scopeKey{kind: KindSLO, Scope: Scope{Dataset: m.Dataset}})
+	if err != nil {
+		return "", err
+	}
+	for _, slo := range slos {
+		if name, _ := slo["name"].(string); name == m.SLO {
+			return ObjectID(slo), nil
+		}
+	}
+	for _, other := range all {
+		if other.Kind == KindSLO && other.Dataset == m.Dataset && other.Key() == m.SLO {
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("SLO %q not found in dataset %s", m.SLO, m.Dataset)
+}
+
+func match(k Kind, m *Manifest, existing []Object) (Object, error) {
+	if m.ID != "" {
+		for _, obj := range existing {
+			if ObjectID(obj) == m.ID {
+				return obj, nil
+			}
+		}
+		return nil, fmt.Errorf("%s %s not found", m.Kind, m.ID)
+	}
+
+	var found Object
+	for _, obj := range existing {
+		if k.Key(obj) != m.Key() {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%s %q matches more than one existing resource (%s, %s); set id to choose one", m.Kind, m.Key(), ObjectID(found), ObjectID(obj))
+		}
+		found = obj
+	}
+	return found, nil
+}
+
+func (p *planner) prune() []Action {
+	var actions []Action
+	for _, key := range p.declared {
+		k, _ := LookupKind(key.kind)
+		for _, obj := range p.lists[key] {
+			id := ObjectID(obj)
+			if p.matched[key][id] {
+				continue
+			}
+			name := k.Key(obj)
+			if name == "" {
+				name = id
+			}
+			actions = append(actions, Action{
+				Op:       OpDelete,
+				Kind:     key.kind,
+				Dataset:  key.Dataset,
+				Name:     name,
+				ID:       id,
+				existing: obj,
+				scope:    key.Scope,
+			})
+		}
+	}
+
+	// Delete dependents before what they depend on.
+	slices.SortStableFunc(actions, func(a, b Action) int {
+		return kindIndex(b.Kind) - kindIndex(a.Kind)
+	})
+	return actions
+}
+
+// Apply executes a plan in order, stopping at the first failure. It returns
+// the actions that completed, with IDs filled in for created resources.
+func Apply(ctx context.Context, c api.ClientInterface, actions []Action) ([]Action, error) {
+	createdSLOs := map[string]string{}
+	done := make([]Action, 0, len(actions))
+
+	for _, a := range actions {
+		k, ok := LookupKind(a.Kind)
+		if !ok {
+			return done, fmt.Errorf("%s: unknown kind", a)
+		}
+
+		var (
+			obj Object
+			err error
+		)
+		switch a.Op {
+		case OpCreate:
+			spec := maps.Clone(a.manifest.Spec)
+			if a.Kind == KindBurnAlert && sloID(spec) == "" {
+				id := a.scope.SLOID
+				if id == "" {
+					id = createdSLOs[a.Dataset+"/"+a.manifest.SLO]
+				}
+				if id == "" {
+					return done, fmt.Errorf("%s: SLO %q was not created", a, a.manifest.SLO)
+				}
+				spec["slo"] = map[string]any{"id": id}
+			}
+			obj, err = create(ctx, c, k, a.scope, spec)
+		case OpUpdate:
+			obj, err = update(ctx, c, k, a.scope, a.ID, a.existing, a.manifest.Spec)
+		case OpDelete:
+			err = k.Delete(ctx, c, a.scope, a.ID)
+		default:
+			err = fmt.Errorf("unknown action %q", a.Op)
+		}
+		if err != nil {
+			return done, fmt.Errorf("%s: %w", a, err)
+		}
+
+		if obj != nil {
+			if id := ObjectID(obj); id != "" {
+				a.ID = id
+			}
+		}
+		if a.Kind == KindSLO && a.Op != OpDelete {
+			createdSLOs[a.Dataset+"/"+a.Name] = a.ID
+		}
+		done = append(done, a)
+	}
+	return done, nil
+}
+
+func create(ctx context.Context, c api.ClientInterface, k Kind, s Scope, spec Object) (Object, error) {
+	body, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	return k.Create(ctx, c, s, body)
+}
+
+// update sends the existing resource, stripped of read-only fields, with the
+// spec's fields replacing its own. Fields the spec omits keep their current
+// values.
+func update(ctx context.Context, c api.ClientInterface, k Kind, s Scope, id string, existing, spec Object) (Object, error) {
+	current, err := api.MarshalStrippingReadOnly(existing, k.Schema)
+	if err != nil {
+		return nil, err
+	}
+	var body Object
+	if err := json.Unmarshal(current, &body); err != nil {
+		return nil, err
+	}
+	maps.Copy(body, spec)
+	if k.Prepare != nil {
+		k.Prepare(body, spec)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return k.Update(ctx, c, s, id, data)
+}
+
+// Diff returns the top-level spec fields whose values differ from existing,
+// in sorted order. A field matches when every value the spec sets is present
+// in existing; fields only existing has, such as server defaults and
+// read-only fields, are ignored.
+func Diff(spec, existing Object) Changes {
+	var changes Changes
+	for field, want := range spec {
+		if !Matches(want, existing[field]) {
+			changes = append(changes, field)
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// Matches reports whether got contains want: objects match when got has a
+// matching value for each of want's keys, and arrays when they have the same
+// length and match element-wise.
+func Matches(want, got any) bool {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return false
+		}
+		for k, v := range w {
+			if !Matches(v, g[k]) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !Matches(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(want, got)
+	}
+}
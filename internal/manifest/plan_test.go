@@ -0,0 +1,362 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bendrucker/honeycomb-cli/internal/api"
+)
+
+// fakeServer is an in-memory store of API collections, keyed by collection
+// path such as /1/triggers/production.
+type fakeServer struct {
+	collections map[string][]Object
+	nextID      int
+	requests    []string
+	bodies      map[string]Object
+}
+
+func newFakeServer(t *testing.T, collections map[string][]Object) (*fakeServer, api.ClientInterface) {
+	t.Helper()
+	f := &fakeServer{collections: collections, bodies: map[string]Object{}}
+	if f.collections == nil {
+		f.collections = map[string][]Object{}
+	}
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, client
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	n := 3
+	if parts[1] == "boards" || parts[1] == "recipients" {
+		n = 2
+	}
+	collection := "/" + strings.Join(parts[:n], "/")
+	id := ""
+	if len(parts) > n {
+		id = parts[n]
+	}
+
+	var body Object
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.bodies[r.Method+" "+r.URL.Path] = body
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	items := f.collections[collection]
+	switch r.Method {
+	case http.MethodGet:
+		list := []Object{}
+		for _, obj := range items {
+			if slo := r.URL.Query().Get("slo_id"); slo != "" && sloID(obj) != slo {
+				continue
+			}
+			list = append(list, obj)
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	case http.MethodPost:
+		f.nextID++
+		body["id"] = fmt.Sprintf("new%d", f.nextID)
+		f.collections[collection] = append(items, body)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(body)
+	case http.MethodPut:
+		for i, obj := range items {
+			if ObjectID(obj) == id {
+				body["id"] = id
+				items[i] = body
+			}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	case http.MethodDelete:
+		for i, obj := range items {
+			if ObjectID(obj) == id {
+				f.collections[collection] = append(items[:i], items[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func parseManifests(t *testing.T, data string) []Manifest {
+	t.Helper()
+	manifests, err := Parse([]byte(data), "test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifests
+}
+
+func summarize(actions []Action) string {
+	lines := make([]string, len(actions))
+	for i, a := range actions {
+		lines[i] = a.String()
+		if len(a.Changes) > 0 {
+			lines[i] += " (" + a.Changes.FormatField() + ")"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestPlan(t *testing.T) {
+	_, client := newFakeServer(t, map[string][]Object{
+		"/1/triggers/production": {
+			{"id": "t1", "name": "Unchanged", "frequency": float64(300), "disabled": false},
+			{"id": "t2", "name": "Changed", "frequency": float64(300), "disabled": false},
+			{"id": "t3", "name": "Unmanaged", "frequency": float64(300)},
+		},
+		"/1/boards": {
+			{"id": "b1", "name": "Overview", "type": "flexible"},
+		},
+	})
+
+	manifests := parseManifests(t, `
+kind: Board
+spec: {name: Overview}
+---
+kind: Trigger
+dataset: production
+spec: {name: Unchanged, frequency: 300}
+---
+kind: Trigger
+dataset: production
+spec: {name: Changed, frequency: 900, disabled: false}
+---
+kind: Trigger
+dataset: production
+spec: {name: New, frequency: 60}
+`)
+
+	plan, err := Plan(context.Background(), client, manifests, PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "update Trigger production/Changed (frequency)\ncreate Trigger production/New"
+	if got := summarize(plan); got != want {
+		t.Errorf("plan:\n%s\nwant:\n%s", got, want)
+	}
+
+	plan, err = Plan(context.Background(), client, manifests, PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := plan[len(plan)-1]
+	if last.Op != OpDelete || last.ID != "t3" {
+		t.Errorf("last action = %s %s, want delete t3", last, last.ID)
+	}
+	if len(plan) != 3 {
+		t.Errorf("prune planned %d actions, want 3:\n%s", len(plan), summarize(plan))
+	}
+}
+
+func TestPlan_AmbiguousMatch(t *testing.T) {
+	_, client := newFakeServer(t, map[string][]Object{
+		"/1/boards": {
+			{"id": "b1", "name": "Overview"},
+			{"id": "b2", "name": "Overview"},
+		},
+	})
+
+	_, err := Plan(context.Background(), client, parseManifests(t, "kind: Board\nspec: {name: Overview}"), PlanOptions{})
+	if err == nil || !strings.Contains(err.Error(), "set id to choose one") {
+		t.Errorf("err = %v, want ambiguous match error", err)
+	}
+
+	plan, err := Plan(context.Background(), client, parseManifests(t, "kind: Board\nid: b2\nspec: {name: Overview, description: Pinned}"), PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0].ID != "b2" {
+		t.Errorf("plan = %+v, want update of b2", plan)
+	}
+}
+
+func TestPlan_Duplicate(t *testing.T) {
+	_, client := newFakeServer(t, nil)
+
+	_, err := Plan(context.Background(), client, parseManifests(t, "kind: Board\nspec: {name: A}\n---\nkind: Board\nspec: {name: A}"), PlanOptions{})
+	if err == nil || !strings.Contains(err.Error(), "duplicates Board A") {
+		t.Errorf("err = %v, want duplicate error", err)
+	}
+}
+
+func TestPlan_PruneBurnAlertsOnly(t *testing.T) {
+	_, client := newFakeServer(t, map[string][]Object{
+		"/1/slos/production": {
+			{"id": "s1", "name": "Availability"},
+			{"id": "s2", "name": "Latency"},
+		},
+		"/1/burn_alerts/production": {
+			{"id": "a1", "description": "Stale", "slo": map[string]any{"id": "s1"}},
+			{"id": "a2", "description": "Other SLO", "slo": map[string]any{"id": "s2"}},
+		},
+	})
+
+	manifests := parseManifests(t, `
+kind: BurnAlert
+dataset: production
+slo: Availability
+spec: {description: Fast burn, alert_type: exhaustion_time, exhaustion_minutes: 60}
+`)
+
+	plan, err := Plan(context.Background(), client, manifests, PlanOptions{Prune: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "create BurnAlert production/Fast burn\ndelete BurnAlert production/Stale"
+	if got := summarize(plan); got != want {
+		t.Errorf("plan:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestApply_BurnAlertForNewSLO(t *testing.T) {
+	f, client := newFakeServer(t, nil)
+
+	manifests := parseManifests(t, `
+kind: BurnAlert
+dataset: production
+slo: Availability
+spec: {description: Fast burn, alert_type: exhaustion_time, exhaustion_minutes: 60}
+---
+kind: SLO
+dataset: production
+spec: {name: Availability, target_per_million: 999000}
+`)
+
+	plan, err := Plan(context.Background(), client, manifests, PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "create SLO production/Availability\ncreate BurnAlert production/Fast burn"
+	if got := summarize(plan); got != want {
+		t.Fatalf("plan:\n%s\nwant:\n%s", got, want)
+	}
+
+	done, err := Apply(context.Background(), client, plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 2 || done[0].ID != "new1" || done[1].ID != "new2" {
+		t.Errorf("done = %+v", done)
+	}
+
+	alert := f.bodies["POST /1/burn_alerts/production"]
+	if got := sloID(alert); got != "new1" {
+		t.Errorf("burn alert slo.id = %q, want new1", got)
+	}
+
+	// Planning again finds the alert under the created SLO.
+	plan, err = Plan(context.Background(), client, manifests, PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 0 {
+		t.Errorf("replan = %s, want no changes", summarize(plan))
+	}
+}
+
+func TestApply_UpdateMergesExisting(t *testing.T) {
+	f, client := newFakeServer(t, map[string][]Object{
+		"/1/triggers/production": {{
+			"id":         "t1",
+			"name":       "Latency",
+			"frequency":  float64(300),
+			"query_id":   "q1",
+			"query":      map[string]any{"time_range": float64(900)},
+			"created_at": "2024-01-01T00:00:00Z",
+		}},
+	})
+
+	plan, err := Plan(context.Background(), client, parseManifests(t, `
+kind: Trigger
+dataset: production
+spec: {name: Latency, frequency: 600}
+`), PlanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Apply(context.Background(), client, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	body := f.bodies["PUT /1/triggers/production/t1"]
+	if body["frequency"] != float64(600) || body["query_id"] != "q1" {
+		t.Errorf("body = %v, want merged frequency and query_id", body)
+	}
+	for _, field := range []string{"query", "created_at"} {
+		if _, ok := body[field]; ok {
+			t.Errorf("body has %s, want it removed", field)
+		}
+	}
+}
+
+func TestApply_StopsOnError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/1/boards/b2" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(ts.Close)
+	client, err := api.NewClient(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := Apply(context.Background(), client, []Action{
+		{Op: OpDelete, Kind: KindBoard, Name: "A", ID: "b1"},
+		{Op: OpDelete, Kind: KindBoard, Name: "B", ID: "b2"},
+		{Op: OpDelete, Kind: KindBoard, Name: "C", ID: "b3"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "delete Board B") {
+		t.Errorf("err = %v, want delete Board B failure", err)
+	}
+	if len(done) != 1 {
+		t.Errorf("completed %d actions, want 1", len(done))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	existing := Object{
+		"name":      "Latency",
+		"frequency": float64(300),
+		"threshold": map[string]any{"op": ">", "value": float64(1000), "exceeded_limit": float64(1)},
+		"tags":      []any{map[string]any{"key": "team", "value": "api"}},
+	}
+
+	for _, tc := range []struct {
+		name string
+		spec Object
+		want string
+	}{
+		{"subset matches", Object{"name": "Latency", "threshold": map[string]any{"value": float64(1000)}}, ""},
+		{"scalar changed", Object{"frequency": float64(60)}, "frequency"},
+		{"nested changed", Object{"threshold": map[string]any{"op": "<"}}, "threshold"},
+		{"array length", Object{"tags": []any{}}, "tags"},
+		{"missing field", Object{"description": "x", "name": "Other"}, "description, name"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Diff(tc.spec, existing).FormatField(); got != tc.want {
+				t.Errorf("Diff() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/internal/jq"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/itchyny/gojq"
+)
+
+// Filter replaces the --format rendering with a jq expression or a Go
+// template, both applied to the JSON form of the data being written.
+type Filter struct {
+	JQ       string
+	Template string
+}
+
+func (f Filter) active() bool {
+	return f.JQ != "" || f.Template != ""
+}
+
+// Validate parses the jq expression and template so that syntax errors are
+// reported before a command does any work.
+func (f Filter) Validate() error {
+	if f.JQ != "" && f.Template != "" {
+		return fmt.Errorf("--jq and --template cannot be used together")
+	}
+	if f.JQ != "" {
+		if _, err := gojq.Parse(f.JQ); err != nil {
+			return fmt.Errorf("parsing --jq expression: %w", err)
+		}
+	}
+	if f.Template != "" {
+		if _, err := parseTemplate(io.Discard, f.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithFilter returns a copy of w that renders every write through f instead of
+// the configured format. A zero Filter leaves w unchanged.
+func (w *Writer) WithFilter(f Filter) *Writer {
+	c := *w
+	c.filter = f
+	return &c
+}
+
+func (w *Writer) writeFiltered(data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+	if w.filter.JQ != "" {
+		return jq.Filter(bytes.NewReader(b), w.out, w.filter.JQ)
+	}
+
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf("encoding output: %w", err)
+	}
+	return RenderTemplate(w.out, w.filter.Template, v)
+}
+
+// RenderTemplate executes a Go text/template against data, which should be
+// the decoded JSON form of the output so that fields are addressed by their
+// JSON names, as in {{range .}}{{.name}}{{end}}.
+func RenderTemplate(out io.Writer, text string, data any) error {
+	tmpl, err := parseTemplate(out, text)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(out, data); err != nil {
+		return fmt.Errorf("executing --template: %w", err)
+	}
+	return nil
+}
+
+// parseTemplate parses text with the template functions, styling colors for
+// the terminal out refers to, if any.
+func parseTemplate(out io.Writer, text string) (*template.Template, error) {
+	funcs := maps.Clone(templateFuncs)
+	funcs["color"] = colorFunc(lipgloss.NewRenderer(out))
+	tmpl, err := template.New("output").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// now is the clock timeago measures against. Tests replace it to get
+// deterministic results.
+var now = time.Now
+
+var templateFuncs = template.FuncMap{
+	"timeago":  timeAgo,
+	"truncate": truncateFunc,
+	"join":     join,
+}
+
+// timeAgo renders an RFC3339 string or a Unix timestamp in seconds relative
+// to now, such as "3 hours ago". Values it cannot parse are returned as-is.
+func timeAgo(v any) string {
+	var t time.Time
+	switch val := v.(type) {
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, val)
+		if err != nil {
+			return val
+		}
+		t = parsed
+	case float64:
+		t = time.Unix(int64(val), 0)
+	case int:
+		t = time.Unix(int64(val), 0)
+	case int64:
+		t = time.Unix(val, 0)
+	case time.Time:
+		t = val
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+
+	d := now().Sub(t)
+	suffix := "ago"
+	if d < 0 {
+		d, suffix = -d, "from now"
+	}
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute") + " " + suffix
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour") + " " + suffix
+	case d < 30*24*time.Hour:
+		return plural(int(d.Hours()/24), "day") + " " + suffix
+	case d < 365*24*time.Hour:
+		return plural(int(d.Hours()/24/30), "month") + " " + suffix
+	default:
+		return plural(int(math.Floor(d.Hours()/24/365)), "year") + " " + suffix
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return strconv.Itoa(n) + " " + unit + "s"
+}
+
+// truncateFunc takes the length first so it reads well in a pipeline:
+// {{.description | truncate 40}}.
+func truncateFunc(length int, v any) string {
+	return Truncate(formatJSONValue(v), length)
+}
+
+// join takes the separator first so it reads well in a pipeline:
+// {{.recipients | join ", "}}.
+func join(sep string, v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return formatJSONValue(v)
+	}
+	parts := make([]string, rv.Len())
+	for i := range rv.Len() {
+		parts[i] = formatJSONValue(rv.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
+
+var ansiColors = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+}
+
+// colorFunc returns the color template function, which styles text with a
+// color name, ANSI number, or hex code, optionally followed by +b (bold), +u
+// (underline), or +i (italic) modifiers, as in {{color "red+b" .name}}.
+// Styling is dropped when r does not render to a terminal.
+func colorFunc(r *lipgloss.Renderer) func(spec string, v any) string {
+	return func(spec string, v any) string {
+		return color(r, spec, v)
+	}
+}
+
+func color(r *lipgloss.Renderer, spec string, v any) string {
+	name, mods, _ := strings.Cut(spec, "+")
+
+	style := r.NewStyle()
+	if name != "" {
+		if c, ok := ansiColors[name]; ok {
+			name = c
+		}
+		style = style.Foreground(lipgloss.Color(name))
+	}
+	for _, m := range mods {
+		switch m {
+		case 'b':
+			style = style.Bold(true)
+		case 'u':
+			style = style.Underline(true)
+		case 'i':
+			style = style.Italic(true)
+		}
+	}
+	return style.Render(formatJSONValue(v))
+}
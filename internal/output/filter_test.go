@@ -0,0 +1,128 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite_JQ(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatTable).WithFilter(Filter{JQ: ".[] | select(.count > 1) | .name"})
+
+	items := []testItem{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+	if err := w.Write(items, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "b\n" {
+		t.Errorf("output = %q, want %q", got, "b\n")
+	}
+}
+
+func TestWriteList_JQ_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatTable).WithFilter(Filter{JQ: "length"})
+
+	if err := w.WriteList([]testItem{}, testTable, "No items found."); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "0\n" {
+		t.Errorf("output = %q, want %q", got, "0\n")
+	}
+}
+
+func TestWrite_Template(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatJSON).WithFilter(Filter{Template: `{{range .}}{{.name}}={{.count}}{{"\n"}}{{end}}`})
+
+	items := []testItem{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+	if err := w.Write(items, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "a=1\nb=2\n" {
+		t.Errorf("output = %q, want %q", got, "a=1\nb=2\n")
+	}
+}
+
+func TestWriteMessage_Template(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatTable).WithFilter(Filter{Template: "{{.id}}"})
+
+	if err := w.WriteDeleted("abc", "Deleted abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "abc" {
+		t.Errorf("output = %q, want %q", got, "abc")
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		filter  Filter
+		wantErr string
+	}{
+		{name: "empty", filter: Filter{}},
+		{name: "jq", filter: Filter{JQ: ".[].name"}},
+		{name: "template", filter: Filter{Template: "{{.name | truncate 10}}"}},
+		{name: "invalid jq", filter: Filter{JQ: ".[invalid"}, wantErr: "parsing --jq"},
+		{name: "invalid template", filter: Filter{Template: "{{.name"}, wantErr: "parsing --template"},
+		{name: "unknown func", filter: Filter{Template: "{{nope .name}}"}, wantErr: "parsing --template"},
+		{name: "both", filter: Filter{JQ: ".", Template: "x"}, wantErr: "cannot be used together"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.filter.Validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = time.Now })
+
+	data := map[string]any{
+		"created_at":  "2024-01-02T12:00:00Z",
+		"updated_at":  float64(fixed.Add(-90 * time.Second).Unix()),
+		"description": "a long description of the trigger",
+		"tags":        []any{"a", "b", float64(3)},
+		"name":        "api",
+	}
+
+	for _, tc := range []struct {
+		tmpl string
+		want string
+	}{
+		{`{{timeago .created_at}}`, "3 hours ago"},
+		{`{{timeago .updated_at}}`, "1 minute ago"},
+		{`{{timeago "2024-01-05T15:00:00Z"}}`, "3 days from now"},
+		{`{{timeago "2022-01-02T15:00:00Z"}}`, "2 years ago"},
+		{`{{.description | truncate 10}}`, "a long ..."},
+		{`{{.tags | join ", "}}`, "a, b, 3"},
+		{`{{color "red+b" .name}}`, "api"},
+	} {
+		t.Run(tc.tmpl, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RenderTemplate(&buf, tc.tmpl, data); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tc.want {
+				t.Errorf("output = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// writeDelimited writes a header row followed by rows as CSV, or TSV when the
+// writer's format is FormatTSV. Values are quoted only when they contain the
+// delimiter, a quote, or a newline.
+func (w *Writer) writeDelimited(headers []string, rows [][]string) error {
+	if len(headers) == 0 {
+		return fmt.Errorf("%s format requires at least one column definition", w.format)
+	}
+
+	cw := csv.NewWriter(w.out)
+	if w.format == FormatTSV {
+		cw.Comma = '\t'
+	}
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+	return cw.WriteAll(rows)
+}
+
+// writeNDJSON writes each element of a slice as a compact JSON object on its
+// own line. Any other value is written as a single line.
+func (w *Writer) writeNDJSON(data any) error {
+	enc := json.NewEncoder(w.out)
+
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return enc.Encode(data)
+	}
+	for i := range rv.Len() {
+		if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAML encodes data as YAML. It round-trips through JSON so that json
+// tags, omitempty, and custom marshalers (such as nullable fields in the
+// generated API types) govern the output exactly as they do for JSON, with keys
+// in the same order.
+func (w *Writer) writeYAML(data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, so decoding it into a node keeps key order. The
+	// decoded styles are flow collections and double-quoted strings; clearing
+	// them lets the encoder choose block style and quote only when needed.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	clearStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err = w.out.Write(buf.Bytes())
+	return err
+}
+
+func clearStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		clearStyle(c)
+	}
+}
+
+func tableHeaders(td TableDef) []string {
+	headers := make([]string, len(td.Columns))
+	for i, col := range td.Columns {
+		headers[i] = col.Header
+	}
+	return headers
+}
+
+// tableRows applies the column accessors to each element of a slice, or to
+// data itself when it is a single value.
+func tableRows(data any, td TableDef) [][]string {
+	row := func(elem any) []string {
+		r := make([]string, len(td.Columns))
+		for j, col := range td.Columns {
+			r[j] = col.Value(elem)
+		}
+		return r
+	}
+
+	rv := reflect.ValueOf(data)
+	if rv.Kind() != reflect.Slice {
+		return [][]string{row(data)}
+	}
+	rows := make([][]string, rv.Len())
+	for i := range rv.Len() {
+		rows[i] = row(rv.Index(i).Interface())
+	}
+	return rows
+}
+
+func fieldLabels(fields []Field) []string {
+	labels := make([]string, len(fields))
+	for i, f := range fields {
+		labels[i] = f.Label
+	}
+	return labels
+}
+
+func fieldValues(fields []Field) []string {
+	values := make([]string, len(fields))
+	for i, f := range fields {
+		values[i] = f.Value
+	}
+	return values
+}
@@ -0,0 +1,156 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatCSV)
+
+	items := []testItem{{Name: "a", Count: 1}, {Name: "b, c", Count: 2}}
+	if err := w.Write(items, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Name,Count\na,1\n\"b, c\",2\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWrite_TSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatTSV)
+
+	items := []testItem{{Name: "a b", Count: 1}}
+	if err := w.Write(items, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Name\tCount\na b\t1\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWriteList_CSV_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatCSV)
+
+	if err := w.WriteList([]testItem{}, testTable, "No items found."); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "Name,Count\n" {
+		t.Errorf("output = %q, want header row only", got)
+	}
+}
+
+func TestWrite_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatNDJSON)
+
+	items := []testItem{{Name: "a", Count: 1}, {Name: "b", Count: 2}}
+	if err := w.Write(items, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "{\"name\":\"a\",\"count\":1}\n{\"name\":\"b\",\"count\":2}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWriteList_NDJSON_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatNDJSON)
+
+	if err := w.WriteList([]testItem{}, testTable, "No items found."); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "" {
+		t.Errorf("output = %q, want empty", got)
+	}
+}
+
+func TestWrite_YAML(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatYAML)
+
+	items := []testItem{{Name: "a", Count: 1}, {Name: "true", Count: 2}}
+	if err := w.Write(items, testTable); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "- name: a\n  count: 1\n- name: \"true\"\n  count: 2\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWriteYAML_PreservesKeyOrder(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatYAML)
+
+	data := struct {
+		Zebra string         `json:"zebra"`
+		Apple map[string]int `json:"apple"`
+		Empty []string       `json:"empty"`
+		Omit  string         `json:"omit,omitempty"`
+	}{Zebra: "z", Apple: map[string]int{"b": 2, "a": 1}, Empty: []string{}}
+	if err := w.WriteMessage(data, "ignored"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "zebra: z\napple:\n  a: 1\n  b: 2\nempty: []\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestWriteFields_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatCSV)
+
+	fields := []Field{{"Name", "a"}, {"Count", "1"}}
+	if err := w.WriteFields(testItem{Name: "a", Count: 1}, fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "Name,Count\na,1\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestWriteDynamic_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatCSV)
+
+	td := DynamicTableDef{
+		Headers: []string{"Col A", "Col B"},
+		Rows:    [][]string{{"1", "2"}, {"3", "4"}},
+	}
+	if err := w.WriteDynamic(nil, td); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "Col A,Col B\n1,2\n3,4\n" {
+		t.Errorf("output = %q", got)
+	}
+}
+
+func TestWriteMessage_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatCSV)
+
+	if err := w.WriteMessage(map[string]string{"id": "x"}, "Deleted x"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := buf.String(); got != "Deleted x\n" {
+		t.Errorf("output = %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -12,23 +13,25 @@ import (
 )
 
 const (
-	FormatJSON  = "json"
-	FormatTable = "table"
+	FormatJSON   = "json"
+	FormatTable  = "table"
+	FormatCSV    = "csv"
+	FormatTSV    = "tsv"
+	FormatNDJSON = "ndjson"
+	FormatYAML   = "yaml"
 )
 
 // ValidFormats lists the output formats accepted by the --format flag.
-var ValidFormats = []string{FormatJSON, FormatTable}
+var ValidFormats = []string{FormatJSON, FormatTable, FormatCSV, FormatTSV, FormatNDJSON, FormatYAML}
 
 // ValidateFormat reports whether format is an accepted --format value. The
 // empty string is allowed: it represents the unset flag, which callers resolve
 // to a concrete format based on TTY detection and command type.
 func ValidateFormat(format string) error {
-	switch format {
-	case "", FormatJSON, FormatTable:
+	if format == "" || slices.Contains(ValidFormats, format) {
 		return nil
-	default:
-		return fmt.Errorf("invalid --format %q: must be one of %s", format, strings.Join(ValidFormats, ", "))
 	}
+	return fmt.Errorf("invalid --format %q: must be one of %s", format, strings.Join(ValidFormats, ", "))
 }
 
 var cellStyle = lipgloss.NewStyle().Padding(0, 1)
@@ -48,7 +51,10 @@ type Column struct {
 	// Header is the column title, written in Title Case (e.g., "Key Name").
 	// It is automatically uppercased when rendered in a table.
 	Header string
-	Value  func(any) string
+	// Key is the JSON field the column displays, if any. --columns matches it
+	// before falling back to the header.
+	Key   string
+	Value func(any) string
 }
 
 // Col builds a Column from a typed accessor, removing the per-call-site v.(T)
@@ -70,6 +76,8 @@ type Field struct {
 type Writer struct {
 	out    io.Writer
 	format string
+	filter Filter
+	view   View
 }
 
 func New(out io.Writer, format string) *Writer {
@@ -83,11 +91,25 @@ func (w *Writer) writeJSON(data any) error {
 }
 
 func (w *Writer) Write(data any, td TableDef) error {
+	data, td, err := w.applyView(data, td)
+	if err != nil {
+		return err
+	}
+
+	if w.filter.active() {
+		return w.writeFiltered(data)
+	}
 	switch w.format {
 	case FormatJSON:
 		return w.writeJSON(data)
 	case FormatTable:
 		return w.writeTable(data, td)
+	case FormatCSV, FormatTSV:
+		return w.writeDelimited(tableHeaders(td), tableRows(data, td))
+	case FormatNDJSON:
+		return w.writeNDJSON(data)
+	case FormatYAML:
+		return w.writeYAML(data)
 	default:
 		return fmt.Errorf("unsupported format: %s", w.format)
 	}
@@ -95,9 +117,11 @@ func (w *Writer) Write(data any, td TableDef) error {
 
 // WriteList renders a slice the same as Write, except that an empty slice in
 // table mode prints emptyMessage on its own line instead of a header-only
-// table. JSON mode is unchanged and always emits the slice (e.g. []).
+// table. Other formats are unchanged: JSON and YAML emit the empty slice, CSV
+// and TSV just the header row, and NDJSON nothing. A --jq or --template filter
+// receives the empty slice.
 func (w *Writer) WriteList(data any, td TableDef, emptyMessage string) error {
-	if w.format == FormatTable {
+	if w.format == FormatTable && !w.filter.active() {
 		rv := reflect.ValueOf(data)
 		if rv.Kind() == reflect.Slice && rv.Len() == 0 {
 			_, err := fmt.Fprintln(w.out, emptyMessage)
@@ -110,12 +134,20 @@ func (w *Writer) WriteList(data any, td TableDef, emptyMessage string) error {
 // WriteMessage emits data as JSON, or a single human-readable line in table
 // mode. An empty line writes nothing in table mode. It covers the "JSON
 // object, or one status line" shape that previously required callers to pass
-// a table-building closure.
+// a table-building closure. NDJSON and YAML encode data; CSV and TSV have no
+// natural shape for a message and print the line as table mode does.
 func (w *Writer) WriteMessage(data any, line string) error {
+	if w.filter.active() {
+		return w.writeFiltered(data)
+	}
 	switch w.format {
 	case FormatJSON:
 		return w.writeJSON(data)
-	case FormatTable:
+	case FormatNDJSON:
+		return w.writeNDJSON(data)
+	case FormatYAML:
+		return w.writeYAML(data)
+	case FormatTable, FormatCSV, FormatTSV:
 		if line == "" {
 			return nil
 		}
@@ -127,11 +159,20 @@ func (w *Writer) WriteMessage(data any, line string) error {
 }
 
 func (w *Writer) WriteFields(data any, fields []Field) error {
+	if w.filter.active() {
+		return w.writeFiltered(data)
+	}
 	switch w.format {
 	case FormatJSON:
 		return w.writeJSON(data)
 	case FormatTable:
 		return w.writeFieldsTable(fields)
+	case FormatCSV, FormatTSV:
+		return w.writeDelimited(fieldLabels(fields), [][]string{fieldValues(fields)})
+	case FormatNDJSON:
+		return w.writeNDJSON(data)
+	case FormatYAML:
+		return w.writeYAML(data)
 	default:
 		return fmt.Errorf("unsupported format: %s", w.format)
 	}
@@ -192,11 +233,20 @@ type DynamicTableDef struct {
 }
 
 func (w *Writer) WriteDynamic(data any, td DynamicTableDef) error {
+	if w.filter.active() {
+		return w.writeFiltered(data)
+	}
 	switch w.format {
 	case FormatJSON:
 		return w.writeJSON(data)
 	case FormatTable:
 		return w.writeDynamicTable(td)
+	case FormatCSV, FormatTSV:
+		return w.writeDelimited(td.Headers, td.Rows)
+	case FormatNDJSON:
+		return w.writeNDJSON(data)
+	case FormatYAML:
+		return w.writeYAML(data)
 	default:
 		return fmt.Errorf("unsupported format: %s", w.format)
 	}
@@ -28,6 +28,10 @@ func TestValidateFormat(t *testing.T) {
 	}{
 		{name: "json", format: "json", wantErr: false},
 		{name: "table", format: "table", wantErr: false},
+		{name: "csv", format: "csv", wantErr: false},
+		{name: "tsv", format: "tsv", wantErr: false},
+		{name: "ndjson", format: "ndjson", wantErr: false},
+		{name: "yaml", format: "yaml", wantErr: false},
 		{name: "empty is unset default", format: "", wantErr: false},
 		{name: "unknown", format: "xml", wantErr: true},
 		{name: "case sensitive", format: "JSON", wantErr: true},
@@ -0,0 +1,145 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// ListStream writes a list incrementally, as pages of a paginated list
+// arrive. JSON, NDJSON, CSV, and TSV rows are written as each page is
+// appended, with the same bytes WriteList would produce for the whole list.
+// Tables, YAML, --columns, --sort, and --jq or --template need every row to
+// render, so those rows are buffered and written by Close.
+type ListStream struct {
+	w     *Writer
+	td    TableDef
+	empty string
+
+	buffered []any
+	csv      *csv.Writer
+	count    int
+}
+
+// StreamList starts a list that is written with Append and finished with
+// Close. td and emptyMessage are used as WriteList uses them.
+func (w *Writer) StreamList(td TableDef, emptyMessage string) *ListStream {
+	return &ListStream{w: w, td: td, empty: emptyMessage}
+}
+
+func (s *ListStream) streaming() bool {
+	if s.w.filter.active() || s.w.view.active() {
+		return false
+	}
+	switch s.w.format {
+	case FormatJSON, FormatNDJSON, FormatCSV, FormatTSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// Append writes, or buffers, the elements of items, which must be a slice.
+func (s *ListStream) Append(items any) error {
+	rv := reflect.ValueOf(items)
+	if rv.Kind() != reflect.Slice {
+		return fmt.Errorf("list stream requires a slice, got %s", rv.Kind())
+	}
+
+	if !s.streaming() {
+		for i := range rv.Len() {
+			s.buffered = append(s.buffered, rv.Index(i).Interface())
+		}
+		return nil
+	}
+
+	for i := range rv.Len() {
+		if err := s.writeElem(rv.Index(i).Interface()); err != nil {
+			return err
+		}
+		s.count++
+	}
+	if s.csv != nil {
+		s.csv.Flush()
+		return s.csv.Error()
+	}
+	return nil
+}
+
+func (s *ListStream) writeElem(elem any) error {
+	switch s.w.format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(elem, "  ", "  ")
+		if err != nil {
+			return err
+		}
+		sep := ",\n  "
+		if s.count == 0 {
+			sep = "[\n  "
+		}
+		_, err = fmt.Fprint(s.w.out, sep, string(b))
+		return err
+	case FormatNDJSON:
+		return json.NewEncoder(s.w.out).Encode(elem)
+	default:
+		if err := s.startCSV(); err != nil {
+			return err
+		}
+		return s.csv.Write(tableRows(elem, s.td)[0])
+	}
+}
+
+func (s *ListStream) startCSV() error {
+	if s.csv != nil {
+		return nil
+	}
+	if len(s.td.Columns) == 0 {
+		return fmt.Errorf("%s format requires at least one column definition", s.w.format)
+	}
+	s.csv = csv.NewWriter(s.w.out)
+	if s.w.format == FormatTSV {
+		s.csv.Comma = '\t'
+	}
+	return s.csv.Write(tableHeaders(s.td))
+}
+
+// Abort ends a list that failed partway, such as when a page fetch errors. It
+// closes a streamed JSON array and flushes CSV rows so the rows already written
+// remain well formed, and discards buffered rows.
+func (s *ListStream) Abort() error {
+	if !s.streaming() {
+		s.buffered = nil
+		return nil
+	}
+	return s.Close()
+}
+
+// Close finishes the list: it closes a streamed JSON array or writes the
+// buffered rows.
+func (s *ListStream) Close() error {
+	if !s.streaming() {
+		data := s.buffered
+		if data == nil {
+			data = []any{}
+		}
+		return s.w.WriteList(data, s.td, s.empty)
+	}
+
+	switch s.w.format {
+	case FormatJSON:
+		end := "\n]\n"
+		if s.count == 0 {
+			end = "[]\n"
+		}
+		_, err := fmt.Fprint(s.w.out, end)
+		return err
+	case FormatCSV, FormatTSV:
+		if err := s.startCSV(); err != nil {
+			return err
+		}
+		s.csv.Flush()
+		return s.csv.Error()
+	}
+	return nil
+}
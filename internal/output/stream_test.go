@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestListStream_MatchesWriteList checks that streaming a list in pages
+// writes the same bytes as writing it at once, for every format and for
+// empty lists.
+func TestListStream_MatchesWriteList(t *testing.T) {
+	items := []testItem{{"a", 1}, {"b, c", 2}, {"d", 3}}
+	pages := [][]testItem{items[:2], {}, items[2:]}
+
+	for _, format := range ValidFormats {
+		for _, tc := range []struct {
+			name  string
+			pages [][]testItem
+			all   []testItem
+		}{
+			{name: "items", pages: pages, all: items},
+			{name: "empty", pages: nil, all: []testItem{}},
+		} {
+			t.Run(format+"/"+tc.name, func(t *testing.T) {
+				var want bytes.Buffer
+				if err := New(&want, format).WriteList(tc.all, testTable, "Nothing."); err != nil {
+					t.Fatal(err)
+				}
+
+				var got bytes.Buffer
+				s := New(&got, format).StreamList(testTable, "Nothing.")
+				for _, p := range tc.pages {
+					if err := s.Append(p); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := s.Close(); err != nil {
+					t.Fatal(err)
+				}
+
+				if got.String() != want.String() {
+					t.Errorf("stream =\n%s\nwant\n%s", got.String(), want.String())
+				}
+			})
+		}
+	}
+}
+
+func TestListStream_WritesPagesAsAppended(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, FormatNDJSON).StreamList(testTable, "")
+	if err := s.Append([]testItem{{"a", 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != `{"name":"a","count":1}`+"\n" {
+		t.Errorf("after first page = %q", got)
+	}
+}
+
+func TestListStream_BuffersView(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, FormatNDJSON).WithView(View{Sort: "-count"}).StreamList(testTable, "")
+	for _, p := range [][]testItem{{{"a", 1}}, {{"b", 2}}} {
+		if err := s.Append(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %q before Close, want buffered", buf.String())
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"b","count":2}` + "\n" + `{"name":"a","count":1}` + "\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestListStream_Abort(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := New(&buf, FormatJSON).StreamList(testTable, "")
+		if err := s.Append([]testItem{{"a", 1}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Abort(); err != nil {
+			t.Fatal(err)
+		}
+		var items []testItem
+		if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+			t.Fatalf("output %q is not a JSON array: %v", buf.String(), err)
+		}
+		if len(items) != 1 {
+			t.Errorf("items = %v, want 1", items)
+		}
+	})
+
+	t.Run("buffered", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := New(&buf, FormatTable).StreamList(testTable, "Nothing.")
+		if err := s.Append([]testItem{{"a", 1}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Abort(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("output = %q, want nothing", buf.String())
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 // TableFromTags derives a TableDef from the `col:"Header"` struct tags on T, in
@@ -22,12 +23,26 @@ func TableFromTags[T any]() TableDef {
 		field := i
 		columns = append(columns, Column{
 			Header: header,
+			Key:    jsonName(t.Field(i)),
 			Value:  func(v any) string { return formatField(reflect.ValueOf(v).Field(field)) },
 		})
 	}
 	return TableDef{Columns: columns}
 }
 
+// jsonName returns the name a field is encoded under, or "" when it is
+// skipped by encoding/json.
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
 // FieldsFromTags derives detail Fields from the `detail:"Label"` struct tags on
 // v, in field order. Fields without a `detail` tag are skipped, so computed or
 // conditional fields can be appended by the caller.
@@ -0,0 +1,223 @@
+package output
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// View customizes list output with --columns and --sort. Both address fields
+// by their JSON names, so any field in the JSON output can be shown or sorted
+// on, including fields the default table omits.
+type View struct {
+	// Columns selects and orders the table, CSV, and TSV columns.
+	Columns []string
+	// Sort orders rows by a field, descending when prefixed with "-".
+	Sort string
+}
+
+func (v View) active() bool {
+	return len(v.Columns) > 0 || v.Sort != ""
+}
+
+// Validate reports malformed --columns and --sort values. Field names are
+// checked against the data when it is written.
+func (v View) Validate() error {
+	for _, c := range v.Columns {
+		if strings.TrimSpace(c) == "" {
+			return fmt.Errorf("invalid --columns: empty column name")
+		}
+	}
+	if v.Sort != "" && sortField(v.Sort) == "" {
+		return fmt.Errorf("invalid --sort %q: expected a field name, optionally prefixed with -", v.Sort)
+	}
+	return nil
+}
+
+// WithView returns a copy of w that applies v to slices written with Write or
+// WriteList. A zero View leaves w unchanged.
+func (w *Writer) WithView(v View) *Writer {
+	c := *w
+	c.view = v
+	return &c
+}
+
+// applyView sorts a slice and replaces td with the selected columns. Values
+// that are not slices pass through unchanged.
+func (w *Writer) applyView(data any, td TableDef) (any, TableDef, error) {
+	rv := reflect.ValueOf(data)
+	if !w.view.active() || rv.Kind() != reflect.Slice {
+		return data, td, nil
+	}
+
+	records := make([]map[string]any, rv.Len())
+	for i := range rv.Len() {
+		records[i] = jsonRecord(rv.Index(i).Interface())
+	}
+
+	if w.view.Sort != "" {
+		field := sortField(w.view.Sort)
+		if !hasField(records, field) {
+			return nil, td, fmt.Errorf("invalid --sort %q: unknown field", w.view.Sort)
+		}
+		desc := strings.HasPrefix(w.view.Sort, "-")
+
+		order := make([]int, len(records))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return compareValues(records[a][field], records[b][field], desc)
+		})
+
+		sorted := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i, j := range order {
+			sorted.Index(i).Set(rv.Index(j))
+		}
+		data = sorted.Interface()
+	}
+
+	if len(w.view.Columns) > 0 {
+		columns := make([]Column, len(w.view.Columns))
+		for i, name := range w.view.Columns {
+			name = strings.TrimSpace(name)
+			if col, ok := findColumn(td, name); ok {
+				columns[i] = col
+				continue
+			}
+			if !hasField(records, name) {
+				return nil, td, fmt.Errorf("invalid --columns: unknown column %q", name)
+			}
+			columns[i] = Column{Header: name, Key: name, Value: func(v any) string {
+				return formatJSONValue(jsonRecord(v)[name])
+			}}
+		}
+		td = TableDef{Columns: columns}
+	}
+
+	return data, td, nil
+}
+
+func sortField(s string) string {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+"))
+}
+
+// findColumn matches a column by its JSON key, falling back to its header
+// so that columns without a backing field can still be selected, as in
+// "key_name" for "Key Name".
+func findColumn(td TableDef, name string) (Column, bool) {
+	for _, col := range td.Columns {
+		if col.Key == name {
+			return col, true
+		}
+	}
+	for _, col := range td.Columns {
+		if normalizeHeader(col.Header) == normalizeHeader(name) {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
+
+func normalizeHeader(s string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(s))
+}
+
+func jsonRecord(v any) map[string]any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var record map[string]any
+	if err := json.Unmarshal(b, &record); err != nil {
+		return nil
+	}
+	return record
+}
+
+// hasField reports whether any record has the field. An empty list has no
+// records to check against, so every field is accepted.
+func hasField(records []map[string]any, field string) bool {
+	if len(records) == 0 {
+		return true
+	}
+	for _, r := range records {
+		if _, ok := r[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// compareValues orders numbers numerically, RFC3339 timestamps
+// chronologically, and other values as strings. Missing and null values sort
+// last in either direction.
+func compareValues(a, b any, desc bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	c := compareNonNil(a, b)
+	if desc {
+		return -c
+	}
+	return c
+}
+
+func compareNonNil(a, b any) int {
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			return cmp.Compare(x, y)
+		}
+	}
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			return cmp.Compare(strconv.FormatBool(x), strconv.FormatBool(y))
+		}
+	}
+
+	x, y := formatJSONValue(a), formatJSONValue(b)
+	if tx, err := time.Parse(time.RFC3339Nano, x); err == nil {
+		if ty, err := time.Parse(time.RFC3339Nano, y); err == nil {
+			return tx.Compare(ty)
+		}
+	}
+	if fx, err := strconv.ParseFloat(x, 64); err == nil {
+		if fy, err := strconv.ParseFloat(y, 64); err == nil {
+			return cmp.Compare(fx, fy)
+		}
+	}
+	return cmp.Compare(x, y)
+}
+
+// formatJSONValue renders a decoded JSON value as a table cell: strings as-is,
+// numbers without exponents, null as empty, and objects and arrays as compact
+// JSON.
+func formatJSONValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(b)
+	}
+}
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type viewItem struct {
+	ID        string `json:"id" col:"ID"`
+	Name      string `json:"name" col:"Name"`
+	Count     int    `json:"count" col:"Count"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+var viewItems = []viewItem{
+	{ID: "a", Name: "alpha", Count: 10, UpdatedAt: "2024-01-02T10:00:00Z"},
+	{ID: "b", Name: "beta", Count: 9, UpdatedAt: "2024-01-03T09:00:00+02:00"},
+	{ID: "c", Name: "gamma", Count: 100},
+}
+
+func writeViewCSV(t *testing.T, v View) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := New(&buf, FormatCSV).WithView(v)
+	if err := w.WriteList(viewItems, TableFromTags[viewItem](), "No items found."); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestView_Columns(t *testing.T) {
+	got := writeViewCSV(t, View{Columns: []string{"updated_at", "id"}})
+	want := "updated_at,ID\n2024-01-02T10:00:00Z,a\n2024-01-03T09:00:00+02:00,b\n,c\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestView_SortNumeric(t *testing.T) {
+	got := writeViewCSV(t, View{Columns: []string{"id"}, Sort: "count"})
+	if want := "ID\nb\na\nc\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestView_SortTimeDescending(t *testing.T) {
+	// b is later by wall clock even though its offset makes it sort first as
+	// a string; c has no timestamp and sorts last.
+	got := writeViewCSV(t, View{Columns: []string{"id"}, Sort: "-updated_at"})
+	if want := "ID\nb\na\nc\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestView_SortJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := New(&buf, FormatNDJSON).WithView(View{Sort: "-name"})
+	if err := w.WriteList(viewItems, TableFromTags[viewItem](), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], `"gamma"`) || !strings.Contains(lines[2], `"alpha"`) {
+		t.Errorf("output = %q, want gamma first and alpha last", buf.String())
+	}
+	if viewItems[0].ID != "a" {
+		t.Error("sorting mutated the caller's slice")
+	}
+}
+
+func TestView_HeaderFallback(t *testing.T) {
+	td := TableDef{Columns: []Column{
+		Col("Key Name", func(v viewItem) string { return strings.ToUpper(v.Name) }),
+	}}
+
+	var buf bytes.Buffer
+	w := New(&buf, FormatCSV).WithView(View{Columns: []string{"key_name"}})
+	if err := w.Write(viewItems[:1], td); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "Key Name\nALPHA\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestView_UnknownField(t *testing.T) {
+	for _, v := range []View{
+		{Columns: []string{"nope"}},
+		{Sort: "-nope"},
+	} {
+		var buf bytes.Buffer
+		err := New(&buf, FormatTable).WithView(v).Write(viewItems, TableFromTags[viewItem]())
+		if err == nil || !strings.Contains(err.Error(), "nope") {
+			t.Errorf("view %+v: err = %v, want unknown field error", v, err)
+		}
+	}
+}
+
+func TestView_Validate(t *testing.T) {
+	if err := (View{Sort: "-"}).Validate(); err == nil {
+		t.Error("expected error for --sort without a field")
+	}
+	if err := (View{Columns: []string{"id", " "}}).Validate(); err == nil {
+		t.Error("expected error for empty column")
+	}
+	if err := (View{Columns: []string{"id"}, Sort: "-id"}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
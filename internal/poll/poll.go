@@ -96,3 +96,25 @@ func pollLoop[T any](ctx context.Context, cfg Config, check func(ctx context.Con
 		}
 	}
 }
+
+// Watch calls fn immediately and then every interval until ctx is done. It
+// returns nil once ctx is cancelled, or the first error fn returns.
+func Watch(ctx context.Context, interval time.Duration, fn func(ctx context.Context) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Package querycache stores completed query results on disk, so that running
+// the same query over the same window again can be answered without the API
+// and past results can be rendered again offline.
+package querycache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bendrucker/honeycomb-cli/internal/config"
+)
+
+// DefaultTTL is how long a cached result is used for a new run of its query.
+const DefaultTTL = 5 * time.Minute
+
+// defaultTimeRange is the window, in seconds, of a query spec without time
+// fields, as the API defaults it.
+const defaultTimeRange = 7200
+
+// DefaultDir returns the directory of the query result cache.
+func DefaultDir() string {
+	return filepath.Join(config.DefaultDir(), "cache", "queries")
+}
+
+// Cache is a directory of cached query results, one JSON file per entry.
+type Cache struct {
+	dir string
+}
+
+// New returns the cache stored in dir, which is created on the first Put.
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Query identifies a query run: the profile and API URL it ran against, the
+// dataset, the JSON spec including its time fields, and whether the result
+// includes the time series.
+type Query struct {
+	Profile string
+	APIUrl  string
+	Dataset string
+	Spec    []byte
+	Series  bool
+}
+
+// Entry is a cached query result.
+type Entry struct {
+	ID        string          `json:"id"`
+	Profile   string          `json:"profile"`
+	APIUrl    string          `json:"api_url"`
+	Dataset   string          `json:"dataset"`
+	Spec      json.RawMessage `json:"spec"`
+	Series    bool            `json:"series"`
+	StartTime time.Time       `json:"start_time"`
+	EndTime   time.Time       `json:"end_time"`
+	CreatedAt time.Time       `json:"created_at"`
+	Result    json.RawMessage `json:"result"`
+}
+
+// Filter selects entries by profile and dataset. Empty fields match any
+// entry.
+type Filter struct {
+	Profile string
+	Dataset string
+}
+
+// Match reports whether e is selected by f.
+func (f Filter) Match(e Entry) bool {
+	return (f.Profile == "" || e.Profile == f.Profile) && (f.Dataset == "" || e.Dataset == f.Dataset)
+}
+
+// Age is how long ago the entry was cached.
+func (e Entry) Age(now time.Time) time.Duration {
+	return now.Sub(e.CreatedAt)
+}
+
+// key is the normalized identity of a query run. The spec is compared
+// without its time fields, which are replaced by the window: absolute for
+// specs with a start or end time, and the range alone for relative specs, so
+// a relative query matches its earlier runs until they expire.
+type key struct {
+	spec       []byte
+	window     string
+	start, end time.Time
+}
+
+func (q Query) key(now time.Time) (key, error) {
+	var m map[string]any
+	if err := json.Unmarshal(q.Spec, &m); err != nil {
+		return key{}, fmt.Errorf("parsing query spec: %w", err)
+	}
+
+	start, hasStart := unixField(m, "start_time")
+	end, hasEnd := unixField(m, "end_time")
+	rangeSecs, hasRange := intField(m, "time_range")
+	delete(m, "start_time")
+	delete(m, "end_time")
+	delete(m, "time_range")
+	if !hasRange {
+		rangeSecs = defaultTimeRange
+	}
+	span := time.Duration(rangeSecs) * time.Second
+
+	k := key{}
+	switch {
+	case hasStart && hasEnd:
+	case hasStart:
+		end = start.Add(span)
+	case hasEnd:
+		start = end.Add(-span)
+	default:
+		k.window = fmt.Sprintf("last %ds", rangeSecs)
+		k.start, k.end = now.Add(-span).Truncate(time.Second), now.Truncate(time.Second)
+	}
+	if k.window == "" {
+		k.window = fmt.Sprintf("%d-%d", start.Unix(), end.Unix())
+		k.start, k.end = start, end
+	}
+
+	// Maps marshal with sorted keys, so equal specs encode identically.
+	spec, err := json.Marshal(m)
+	if err != nil {
+		return key{}, fmt.Errorf("encoding query spec: %w", err)
+	}
+	k.spec = spec
+	return k, nil
+}
+
+func (q Query) id(k key) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n%s\n%s\n%s\n%t", q.Profile, q.APIUrl, q.Dataset, k.spec, k.window, q.Series)
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func unixField(m map[string]any, name string) (time.Time, bool) {
+	secs, ok := intField(m, name)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(secs, 0).UTC(), true
+}
+
+func intField(m map[string]any, name string) (int64, bool) {
+	v, ok := m[name].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int64(v), true
+}
+
+// Get returns the entry for q cached within ttl of now, or nil when there is
+// none.
+func (c *Cache) Get(q Query, ttl time.Duration, now time.Time) (*Entry, error) {
+	k, err := q.key(now)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := c.Load(q.id(k))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if entry.Age(now) > ttl {
+		return nil, nil
+	}
+	return entry, nil
+}
+
+// Put caches result, the JSON query result details of q, replacing any
+// earlier result of the same query.
+func (c *Cache) Put(q Query, result []byte, now time.Time) (*Entry, error) {
+	k, err := q.key(now)
+	if err != nil {
+		return nil, err
+	}
+	entry := &Entry{
+		ID:        q.id(k),
+		Profile:   q.Profile,
+		APIUrl:    q.APIUrl,
+		Dataset:   q.Dataset,
+		Spec:      k.spec,
+		Series:    q.Series,
+		StartTime: k.start,
+		EndTime:   k.end,
+		CreatedAt: now.UTC(),
+		Result:    result,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("encoding cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path(entry.ID), data, 0o600); err != nil {
+		return nil, fmt.Errorf("writing cache entry: %w", err)
+	}
+	return entry, nil
+}
+
+// Load returns the entry with id. It returns an error wrapping
+// os.ErrNotExist when there is none.
+func (c *Cache) Load(id string) (*Entry, error) {
+	if id == "" || strings.ContainsAny(id, `/\.`) {
+		return nil, fmt.Errorf("invalid cache entry ID %q", id)
+	}
+	data, err := os.ReadFile(c.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cached result %s not found: %w", id, os.ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading cache entry: %w", err)
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("parsing cache entry %s: %w", id, err)
+	}
+	return &entry, nil
+}
+
+// List returns every entry, most recent first.
+func (c *Cache) List() ([]Entry, error) {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []Entry{}
+	for _, f := range files {
+		entry, err := c.Load(strings.TrimSuffix(filepath.Base(f), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return entries, nil
+}
+
+// Clear removes the entries f selects and returns how many were removed.
+func (c *Cache) Clear(f Filter) (int, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if !f.Match(e) {
+			continue
+		}
+		if err := os.Remove(c.path(e.ID)); err != nil {
+			return removed, fmt.Errorf("removing cache entry: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (c *Cache) path(id string) string {
+	return filepath.Join(c.dir, id+".json")
+}
@@ -0,0 +1,139 @@
+package querycache
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+
+func TestGetPut(t *testing.T) {
+	c := New(t.TempDir())
+	q := Query{Dataset: "production", Spec: []byte(`{"calculations": [{"op": "COUNT"}], "time_range": 3600}`)}
+
+	if e, err := c.Get(q, DefaultTTL, now); err != nil || e != nil {
+		t.Fatalf("Get before Put = %v, %v; want a miss", e, err)
+	}
+
+	put, err := c.Put(q, []byte(`{"complete": true}`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(-time.Hour); !put.StartTime.Equal(want) || !put.EndTime.Equal(now) {
+		t.Errorf("window = %s..%s, want %s..%s", put.StartTime, put.EndTime, want, now)
+	}
+
+	// Keys are order-insensitive and the relative window matches later runs.
+	same := Query{Dataset: "production", Spec: []byte(`{"time_range":3600,"calculations":[{"op":"COUNT"}]}`)}
+	got, err := c.Get(same, DefaultTTL, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.ID != put.ID || string(got.Result) != `{"complete":true}` {
+		t.Fatalf("Get = %+v, want the cached entry", got)
+	}
+
+	if got, _ := c.Get(same, DefaultTTL, now.Add(DefaultTTL+time.Second)); got != nil {
+		t.Errorf("Get after the TTL = %+v, want a miss", got)
+	}
+
+	for name, other := range map[string]Query{
+		"dataset": {Dataset: "staging", Spec: q.Spec},
+		"range":   {Dataset: "production", Spec: []byte(`{"calculations": [{"op": "COUNT"}], "time_range": 7200}`)},
+		"series":  {Dataset: "production", Spec: q.Spec, Series: true},
+		"profile": {Profile: "prod", Dataset: "production", Spec: q.Spec},
+		"api url": {APIUrl: "https://api.eu1.honeycomb.io", Dataset: "production", Spec: q.Spec},
+	} {
+		if got, _ := c.Get(other, DefaultTTL, now); got != nil {
+			t.Errorf("%s: Get = %+v, want a miss", name, got)
+		}
+	}
+}
+
+func TestAbsoluteWindow(t *testing.T) {
+	c := New(t.TempDir())
+	start := now.Add(-2 * time.Hour).Unix()
+	end := now.Add(-time.Hour).Unix()
+
+	put, err := c.Put(Query{Dataset: "d", Spec: []byte(`{"start_time": ` + itoa(start) + `, "end_time": ` + itoa(end) + `}`)}, []byte(`{}`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if put.StartTime.Unix() != start || put.EndTime.Unix() != end {
+		t.Errorf("window = %s..%s", put.StartTime, put.EndTime)
+	}
+
+	// A start time and range describe the same window.
+	got, err := c.Get(Query{Dataset: "d", Spec: []byte(`{"start_time": ` + itoa(start) + `, "time_range": 3600}`)}, DefaultTTL, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.ID != put.ID {
+		t.Errorf("Get = %+v, want %s", got, put.ID)
+	}
+}
+
+func TestListClear(t *testing.T) {
+	c := New(t.TempDir())
+	for i, ds := range []string{"a", "b", "a"} {
+		spec := []byte(`{"limit": ` + itoa(int64(i+1)) + `}`)
+		if _, err := c.Put(Query{Dataset: ds, Spec: spec}, []byte(`{}`), now.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || !entries[0].CreatedAt.After(entries[1].CreatedAt) {
+		t.Fatalf("entries = %+v, want 3 most recent first", entries)
+	}
+
+	removed, err := c.Clear(Filter{Dataset: "a"})
+	if err != nil || removed != 2 {
+		t.Fatalf("Clear(a) = %d, %v; want 2", removed, err)
+	}
+	if _, err := c.Load(entries[0].ID); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Load after Clear = %v, want not found", err)
+	}
+	removed, _ = c.Clear(Filter{})
+	if removed != 1 {
+		t.Errorf("Clear() = %d, want 1", removed)
+	}
+}
+
+func TestClear_Profile(t *testing.T) {
+	c := New(t.TempDir())
+	for _, profile := range []string{"prod", "staging"} {
+		q := Query{Profile: profile, APIUrl: "https://api.honeycomb.io", Dataset: "production", Spec: []byte(`{}`)}
+		if _, err := c.Put(q, []byte(`{}`), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := c.Clear(Filter{Profile: "staging", Dataset: "production"})
+	if err != nil || removed != 1 {
+		t.Fatalf("Clear(staging) = %d, %v; want 1", removed, err)
+	}
+	entries, err := c.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Profile != "prod" || entries[0].APIUrl != "https://api.honeycomb.io" {
+		t.Errorf("entries = %+v, want the prod entry", entries)
+	}
+}
+
+func TestLoad_InvalidID(t *testing.T) {
+	if _, err := New(t.TempDir()).Load("../config"); err == nil {
+		t.Error("expected error for an ID with a path")
+	}
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}